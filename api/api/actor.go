@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+)
+
+const ActorHeader = "X-Actor"
+
+const maxActorLength = 128
+
+type actorKey struct{}
+
+// Actor keeps who the caller says they are in the request context, so it is
+// recorded with the changes they ask for. There is no authentication yet,
+// so it is only as trustworthy as the caller.
+func Actor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if actor := c.GetHeader(ActorHeader); validActor(actor) {
+			c.Request = c.Request.WithContext(WithActor(c.Request.Context(), actor))
+		}
+		c.Next()
+	}
+}
+
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// validActor only accepts printable ASCII, an actor is usually a user name
+// or an email address.
+func validActor(actor string) bool {
+	if actor == "" || len(actor) > maxActorLength {
+		return false
+	}
+
+	for _, r := range actor {
+		if r < ' ' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
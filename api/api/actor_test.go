@@ -0,0 +1,49 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestActor(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "should keep actor sent by caller", header: "alice@example.com", want: "alice@example.com"},
+		{name: "should leave actor empty when missing"},
+		{name: "should drop actor when it is unsafe", header: "alice\r\nx-injected: 1"},
+		{name: "should drop actor when it is too long", header: strings.Repeat("a", maxActorLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(Actor())
+
+			var fromContext string
+			r.GET("/", func(c *gin.Context) {
+				fromContext = ActorFrom(c.Request.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(ActorHeader, tt.header)
+			}
+
+			// Act
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			// Assert
+			if fromContext != tt.want {
+				t.Errorf("expected actor %q, got %q", tt.want, fromContext)
+			}
+		})
+	}
+}
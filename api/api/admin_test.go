@@ -0,0 +1,49 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdmin(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		want          bool
+	}{
+		{name: "should accept configured token", token: "secret", authorization: "Bearer secret", want: true},
+		{name: "should reject other token", token: "secret", authorization: "Bearer guess"},
+		{name: "should reject missing token", token: "secret"},
+		{name: "should reject everyone when no token is configured", authorization: "Bearer "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(Admin(tt.token))
+
+			var admin bool
+			r.GET("/", func(c *gin.Context) {
+				admin = IsAdmin(c.Request.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			// Act
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			// Assert
+			if admin != tt.want {
+				t.Errorf("expected admin %t, got %t", tt.want, admin)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		generate bool
+	}{
+		{name: "should keep request id sent by caller", header: "req-123"},
+		{name: "should generate request id when missing", generate: true},
+		{name: "should generate request id when it is unsafe", header: "req\r\nx-injected: 1", generate: true},
+		{name: "should generate request id when it is too long", header: strings.Repeat("a", maxRequestIDLength+1), generate: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(RequestID())
+
+			var fromContext string
+			r.GET("/", func(c *gin.Context) {
+				fromContext = RequestIDFrom(c.Request.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			r.ServeHTTP(w, req)
+
+			// Assert
+			got := w.Header().Get(RequestIDHeader)
+			if got == "" || got != fromContext {
+				t.Fatalf("expected echoed request id to match context, got %q and %q", got, fromContext)
+			}
+
+			if !tt.generate && got != tt.header {
+				t.Errorf("expected request id %q, got %q", tt.header, got)
+			}
+
+			if tt.generate && got == tt.header {
+				t.Errorf("expected a generated request id, got %q", got)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ type Response struct {
 	Status  string `json:"status"`
 	Data    any    `json:"data,omitempty"`
 	Message string `json:"message,omitempty"`
+	Page    any    `json:"page,omitempty"`
 }
 
 func ErrorResponse(message string) Response {
@@ -13,6 +14,15 @@ func ErrorResponse(message string) Response {
 	}
 }
 
+// ErrorDataResponse is an error with details of what went wrong in data.
+func ErrorDataResponse(message string, data any) Response {
+	return Response{
+		Status:  "error",
+		Message: message,
+		Data:    data,
+	}
+}
+
 func MessageResponse(message string) Response {
 	return Response{
 		Status:  "success",
@@ -20,9 +30,26 @@ func MessageResponse(message string) Response {
 	}
 }
 
+func MessageDataResponse(message string, data any) Response {
+	return Response{
+		Status:  "success",
+		Message: message,
+		Data:    data,
+	}
+}
+
 func SuccessResponse(data any) Response {
 	return Response{
 		Status: "success",
 		Data:   data,
 	}
 }
+
+// PageResponse is a successful response holding one page of a listing.
+func PageResponse(data any, page any) Response {
+	return Response{
+		Status: "success",
+		Data:   data,
+		Page:   page,
+	}
+}
@@ -3,19 +3,57 @@ package config
 import (
 	"log"
 	"os"
+	"skill-api-kafka-contract/telemetry"
+	"strconv"
+	"time"
+)
+
+const (
+	MessageFormatJSON     = "json"
+	MessageFormatProtobuf = "protobuf"
+)
+
+const (
+	TracingExporterNone   = telemetry.ExporterNone
+	TracingExporterStdout = telemetry.ExporterStdout
+	TracingExporterOTLP   = telemetry.ExporterOTLP
 )
 
 type Config struct {
-	PostgresURI string
-	Port        string
-	Kafka       KafkaConfig
+	PostgresURI       string
+	Port              string
+	SchemaRegistryDir string
+	// AdminToken is the bearer token of admin-only endpoints, which are
+	// closed to everyone when it is empty.
+	AdminToken string
+	Kafka      KafkaConfig
+	Outbox     OutboxConfig
+	Tracing    TracingConfig
 }
 
 type KafkaConfig struct {
-	KafkaBroker string
-	SkillTopic  string
+	KafkaBroker   string
+	SkillTopic    string
+	ResultTopic   string
+	ProducerID    string
+	MessageFormat string
+	// ResultRefreshInterval is how often the result topic's partitions are
+	// listed, so partitions added while running are read too.
+	ResultRefreshInterval time.Duration
 }
 
+// OutboxConfig sets how often the outbox is relayed to Kafka, and how long
+// sent messages are kept. A zero Retention keeps them forever.
+type OutboxConfig struct {
+	PollInterval  time.Duration
+	BatchSize     int
+	SweepInterval time.Duration
+	Retention     time.Duration
+}
+
+// TracingConfig is the telemetry config both services are set up with.
+type TracingConfig = telemetry.Config
+
 func Configuration() Config {
 	if os.Getenv("PORT") == "" {
 		log.Fatal("PORT is not set")
@@ -33,12 +71,111 @@ func Configuration() Config {
 		log.Fatal("KAFKA_SKILL_TOPIC is not set")
 	}
 
+	resultTopic := os.Getenv("KAFKA_RESULT_TOPIC")
+	if resultTopic == "" {
+		resultTopic = os.Getenv("KAFKA_SKILL_TOPIC") + "_result"
+	}
+
+	// ProducerID is stamped on every skill message so the consumer can tell
+	// which API instance sent it.
+	producerID := os.Getenv("KAFKA_PRODUCER_ID")
+	if producerID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			log.Fatal("KAFKA_PRODUCER_ID is not set and hostname is unavailable")
+		}
+		producerID = "skill-api/" + hostname
+	}
+
+	// Consumers read both formats, so switching to protobuf is safe once
+	// they are deployed with a registry holding the schema.
+	messageFormat := os.Getenv("KAFKA_MESSAGE_FORMAT")
+	if messageFormat == "" {
+		messageFormat = MessageFormatJSON
+	}
+
+	if messageFormat != MessageFormatJSON && messageFormat != MessageFormatProtobuf {
+		log.Fatal("KAFKA_MESSAGE_FORMAT must be one of json or protobuf")
+	}
+
+	resultRefreshInterval := 30 * time.Second
+	if v := os.Getenv("KAFKA_RESULT_REFRESH_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatal("KAFKA_RESULT_REFRESH_INTERVAL must be a positive duration")
+		}
+		resultRefreshInterval = d
+	}
+
+	outbox := OutboxConfig{
+		PollInterval:  500 * time.Millisecond,
+		BatchSize:     100,
+		SweepInterval: time.Hour,
+		Retention:     7 * 24 * time.Hour,
+	}
+
+	if v := os.Getenv("OUTBOX_POLL_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatal("OUTBOX_POLL_INTERVAL must be a positive duration")
+		}
+		outbox.PollInterval = d
+	}
+
+	if v := os.Getenv("OUTBOX_BATCH_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			log.Fatal("OUTBOX_BATCH_SIZE must be a positive number")
+		}
+		outbox.BatchSize = n
+	}
+
+	if v := os.Getenv("OUTBOX_SWEEP_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatal("OUTBOX_SWEEP_INTERVAL must be a positive duration")
+		}
+		outbox.SweepInterval = d
+	}
+
+	if v := os.Getenv("OUTBOX_RETENTION"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			log.Fatal("OUTBOX_RETENTION must be a duration, 0 keeps sent messages")
+		}
+		outbox.Retention = d
+	}
+
+	// TRACING_FILE only applies to the stdout exporter, spans are written to
+	// stdout when it is empty.
+	tracing := TracingConfig{
+		Exporter: os.Getenv("TRACING_EXPORTER"),
+		File:     os.Getenv("TRACING_FILE"),
+	}
+	if tracing.Exporter == "" {
+		tracing.Exporter = TracingExporterNone
+	}
+
+	switch tracing.Exporter {
+	case TracingExporterNone, TracingExporterStdout, TracingExporterOTLP:
+	default:
+		log.Fatal("TRACING_EXPORTER must be one of none, stdout or otlp")
+	}
+
 	return Config{
-		PostgresURI: os.Getenv("POSTGRES_URI"),
-		Port:        os.Getenv("PORT"),
+		PostgresURI:       os.Getenv("POSTGRES_URI"),
+		Port:              os.Getenv("PORT"),
+		SchemaRegistryDir: os.Getenv("SCHEMA_REGISTRY_DIR"),
+		AdminToken:        os.Getenv("ADMIN_TOKEN"),
+		Outbox:            outbox,
+		Tracing:           tracing,
 		Kafka: KafkaConfig{
-			KafkaBroker: os.Getenv("KAFKA_BROKER"),
-			SkillTopic:  os.Getenv("KAFKA_SKILL_TOPIC"),
+			KafkaBroker:           os.Getenv("KAFKA_BROKER"),
+			SkillTopic:            os.Getenv("KAFKA_SKILL_TOPIC"),
+			ResultTopic:           resultTopic,
+			ProducerID:            producerID,
+			MessageFormat:         messageFormat,
+			ResultRefreshInterval: resultRefreshInterval,
 		},
 	}
 }
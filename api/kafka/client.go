@@ -0,0 +1,30 @@
+package kafka
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"log"
+	"skill-api-kafka/config"
+	"strings"
+)
+
+func Client(c config.KafkaConfig) (sarama.Client, func()) {
+	client, err := sarama.NewClient(strings.Split(c.KafkaBroker, ","), sarama.NewConfig())
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	return client, func() {
+		if err := client.Close(); err != nil {
+			log.Fatalln(err)
+		}
+	}
+}
+
+// MetadataCheck fetches fresh metadata for topics, which fails when no
+// broker is reachable or one of the topics does not exist.
+func MetadataCheck(client sarama.Client, topics ...string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return client.RefreshMetadata(topics...)
+	}
+}
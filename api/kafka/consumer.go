@@ -0,0 +1,27 @@
+package kafka
+
+import (
+	"github.com/IBM/sarama"
+	"log"
+	"skill-api-kafka/config"
+	"strings"
+)
+
+func Consumer(c config.KafkaConfig) (sarama.Consumer, func()) {
+	kafkaConfig := sarama.NewConfig()
+	kafkaConfig.Consumer.Return.Errors = true
+	// Partitions are listed from cached metadata, which has to be as fresh
+	// as the listing for a new partition to show up.
+	kafkaConfig.Metadata.RefreshFrequency = c.ResultRefreshInterval
+
+	consumer, err := sarama.NewConsumer(strings.Split(c.KafkaBroker, ","), kafkaConfig)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	return consumer, func() {
+		if err := consumer.Close(); err != nil {
+			log.Fatalln(err)
+		}
+	}
+}
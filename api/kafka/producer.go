@@ -11,7 +11,7 @@ func Producer(c config.KafkaConfig) (sarama.SyncProducer, func()) {
 	kafkaConfig := sarama.NewConfig()
 	kafkaConfig.Producer.Return.Successes = true
 	kafkaConfig.Producer.Return.Errors = true
-	kafkaConfig.Producer.Partitioner = sarama.NewRoundRobinPartitioner
+	kafkaConfig.Producer.Partitioner = sarama.NewHashPartitioner
 	kafkaConfig.Producer.RequiredAcks = sarama.WaitForAll
 
 	producer, err := sarama.NewSyncProducer(strings.Split(c.KafkaBroker, ","), kafkaConfig)
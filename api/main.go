@@ -6,14 +6,24 @@ import (
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"skill-api-kafka-contract/health"
+	"skill-api-kafka-contract/message"
+	"skill-api-kafka-contract/schema"
+	"skill-api-kafka-contract/telemetry"
+	"skill-api-kafka/api"
 	"skill-api-kafka/config"
 	"skill-api-kafka/database"
 	"skill-api-kafka/kafka"
+	"skill-api-kafka/metrics"
+	"skill-api-kafka/operation"
+	"skill-api-kafka/outbox"
 	"skill-api-kafka/skill"
+	"skill-api-kafka/tracing"
 	"syscall"
 	"time"
 )
@@ -21,15 +31,47 @@ import (
 func main() {
 	c := config.Configuration()
 
+	shutdownTracing, err := telemetry.Setup(context.Background(), tracing.ServiceName, c.Tracing)
+	if err != nil {
+		log.Fatal("Error setting up tracing: ", err)
+	}
+
 	db := database.Postgres(c.PostgresURI)
 	storage := skill.NewSkillStorage(db)
 
 	producer, closeKafka := kafka.Producer(c.Kafka)
 	defer closeKafka()
 
-	queue := skill.NewSkillQueue(producer, c.Kafka)
+	registry, err := schema.Open(c.SchemaRegistryDir)
+	if err != nil {
+		log.Fatal("Error loading schema registry: ", err)
+	}
+
+	var serializer message.Serializer = message.JSONSerializer{}
+	if c.Kafka.MessageFormat == config.MessageFormatProtobuf {
+		serializer, err = message.NewProtobufSerializer(registry)
+		if err != nil {
+			log.Fatal("Error checking skill schema: ", err)
+		}
+	}
+
+	outboxStorage := outbox.NewOutboxStorage(db)
+	operationStorage := operation.NewOperationStorage(db)
+	queue := skill.NewSkillQueue(skill.NewPublishStorage(db), serializer, c.Kafka)
 
-	r := Router(storage, queue)
+	resultConsumer, closeResultConsumer := kafka.Consumer(c.Kafka)
+	defer closeResultConsumer()
+
+	waiter := operation.NewWaiter(operationStorage)
+
+	kafkaClient, closeKafkaClient := kafka.Client(c.Kafka)
+	defer closeKafkaClient()
+
+	checker := health.NewChecker(2 * time.Second)
+	checker.Register("postgres", db.PingContext)
+	checker.Register("kafka", kafka.MetadataCheck(kafkaClient, c.Kafka.SkillTopic, c.Kafka.ResultTopic))
+
+	r := Router(storage, queue, operationStorage, waiter, checker, c.AdminToken)
 
 	defer func(db *sql.DB) {
 		err := db.Close()
@@ -41,6 +83,10 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
+	relay := outbox.NewRelay(outboxStorage, producer, c.Outbox)
+	go relay.Run(ctx)
+	go waiter.Listen(ctx, resultConsumer, c.Kafka.ResultTopic, c.Kafka.ResultRefreshInterval)
+
 	srv := http.Server{
 		Addr:    ":" + os.Getenv("PORT"),
 		Handler: r,
@@ -68,25 +114,54 @@ func main() {
 	}
 
 	<-closedChannel
+
+	// The relay may still have been sending, so spans are flushed last.
+	tracingCtx, tracingCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer tracingCancel()
+	if err := shutdownTracing(tracingCtx); err != nil {
+		log.Println("Error flushing traces:", err)
+	}
+
 	fmt.Println("Server shutdown successfully")
 
 }
 
-func Router(storage skill.SkillStorage, producer skill.SkillQueue) *gin.Engine {
+func Router(storage skill.SkillStorage, producer skill.SkillQueue, operations operation.OperationStorage, waiter skill.OperationWaiter, checker *health.Checker, adminToken string) *gin.Engine {
 	r := gin.Default()
-	h := skill.NewSkillHandler(storage, producer)
+	r.Use(otelgin.Middleware(tracing.ServiceName))
+	r.Use(metrics.HTTP())
+	r.Use(api.RequestID())
+	r.Use(api.Actor())
+	r.Use(api.Admin(adminToken))
+	h := skill.NewSkillHandler(storage, producer, waiter)
+	oh := operation.NewOperationHandler(operations)
+	hh := health.NewHealthHandler(checker)
+
+	r.GET("/metrics", metrics.Handler())
+	r.GET("/healthz", gin.WrapF(hh.Liveness))
+	r.GET("/readyz", gin.WrapF(hh.Readiness))
 
 	v1Group := r.Group("/api/v1")
 	{
 		v1Group.GET("/skills/:key", h.GetSkill)
 		v1Group.GET("/skills", h.GetSkills)
+		v1Group.GET("/skills/search", h.SearchSkills)
+		v1Group.GET("/skills/:key/history", h.GetSkillHistory)
 		v1Group.POST("/skills", h.CreateSkill)
 		v1Group.PUT("/skills/:key", h.UpdateSkill)
 		v1Group.PATCH("/skills/:key/actions/name", h.UpdateName)
 		v1Group.PATCH("/skills/:key/actions/description", h.UpdateDescription)
 		v1Group.PATCH("/skills/:key/actions/logo", h.UpdateLogo)
 		v1Group.PATCH("/skills/:key/actions/tags", h.UpdateTags)
+		v1Group.PATCH("/skills/:key/actions/add_tags", h.AddTags)
+		v1Group.PATCH("/skills/:key/actions/remove_tags", h.RemoveTags)
 		v1Group.DELETE("/skills/:key", h.DeleteSkill)
+		v1Group.POST("/skills/:key/actions/restore", h.RestoreSkill)
+		v1Group.GET("/tags", h.GetTags)
+		v1Group.GET("/tags/:tag/skills", h.GetTagSkills)
+		v1Group.POST("/skills/import", h.ImportSkills)
+		v1Group.GET("/operations/:id", oh.GetOperation)
+		v1Group.GET("/batches/:id", oh.GetBatch)
 	}
 
 	return r
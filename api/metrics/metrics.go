@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"strconv"
+	"time"
+)
+
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "skill_api_http_requests_total",
+		Help: "HTTP requests handled, by route, method and status code.",
+	}, []string{"route", "method", "code"})
+
+	HTTPDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "skill_api_http_request_duration_seconds",
+		Help:    "Time taken to handle HTTP requests, by route and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	// SkillPublished counts writes accepted into the outbox. Whether they
+	// reached Kafka is counted by OutboxSent.
+	SkillPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "skill_api_publish_total",
+		Help: "Skill messages published, by action and result.",
+	}, []string{"action", "result"})
+
+	OutboxSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "skill_api_outbox_sent_total",
+		Help: "Outbox messages sent to Kafka, by topic and result.",
+	}, []string{"topic", "result"})
+
+	OutboxSwept = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "skill_api_outbox_swept_total",
+		Help: "Sent outbox messages deleted after the retention.",
+	})
+)
+
+// HTTP records every request against the route pattern it matched, so
+// /skills/go and /skills/python count as one route. Requests matching no
+// route are grouped together to keep the label set bounded.
+func HTTP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		HTTPRequests.WithLabelValues(route, c.Request.Method, strconv.Itoa(c.Writer.Status())).Inc()
+		HTTPDuration.WithLabelValues(route, c.Request.Method).Observe(time.Since(start).Seconds())
+	}
+}
+
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
+
+func Result(err error) string {
+	if err != nil {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
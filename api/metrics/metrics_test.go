@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTP(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(HTTP())
+	r.GET("/skills/:key", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	r.GET("/metrics", Handler())
+
+	before := testutil.ToFloat64(HTTPRequests.WithLabelValues("/skills/:key", http.MethodGet, "404"))
+	unmatched := testutil.ToFloat64(HTTPRequests.WithLabelValues("unmatched", http.MethodGet, "404"))
+
+	// Act
+	for _, path := range []string{"/skills/go", "/skills/python", "/unknown"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	// Assert
+	if got := testutil.ToFloat64(HTTPRequests.WithLabelValues("/skills/:key", http.MethodGet, "404")) - before; got != 2 {
+		t.Errorf("expected 2 requests counted against the route, got %v", got)
+	}
+
+	if got := testutil.ToFloat64(HTTPRequests.WithLabelValues("unmatched", http.MethodGet, "404")) - unmatched; got != 1 {
+		t.Errorf("expected 1 unmatched request, got %v", got)
+	}
+
+	if !strings.Contains(w.Body.String(), `skill_api_http_request_duration_seconds_count{method="GET",route="/skills/:key"}`) {
+		t.Errorf("expected latency histogram to be exposed, got %s", w.Body.String())
+	}
+}
@@ -0,0 +1,107 @@
+package operation
+
+import (
+	"context"
+	"time"
+)
+
+type Status string
+
+const (
+	PendingStatus   Status = "pending"
+	SucceededStatus Status = "succeeded"
+	FailedStatus    Status = "failed"
+	// ConflictStatus is a change the consumer rejected because the skill
+	// had moved on from the version it was made against.
+	ConflictStatus Status = "conflict"
+	// NotFoundStatus is a change the consumer rejected because the skill
+	// did not exist.
+	NotFoundStatus Status = "not_found"
+)
+
+type Operation struct {
+	ID        string
+	Action    string
+	SkillKey  string
+	Status    Status
+	Reason    string
+	RequestID string
+	BatchID   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type ResponseOperation struct {
+	ID        string    `json:"id"`
+	Action    string    `json:"action"`
+	SkillKey  string    `json:"skill_key"`
+	Status    Status    `json:"status"`
+	Reason    string    `json:"reason,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	BatchID   string    `json:"batch_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ResponseBatch sums up the operations published together by one import.
+// Done is set once none of them is pending anymore.
+type ResponseBatch struct {
+	ID         string              `json:"id"`
+	Total      int                 `json:"total"`
+	Statuses   map[Status]int      `json:"statuses"`
+	Done       bool                `json:"done"`
+	Operations []ResponseOperation `json:"operations"`
+}
+
+func responseOperation(op Operation) ResponseOperation {
+	return ResponseOperation{
+		ID:        op.ID,
+		Action:    op.Action,
+		SkillKey:  op.SkillKey,
+		Status:    op.Status,
+		Reason:    op.Reason,
+		RequestID: op.RequestID,
+		BatchID:   op.BatchID,
+		CreatedAt: op.CreatedAt,
+		UpdatedAt: op.UpdatedAt,
+	}
+}
+
+func responseBatch(id string, ops []Operation) ResponseBatch {
+	res := ResponseBatch{
+		ID:         id,
+		Total:      len(ops),
+		Statuses:   map[Status]int{},
+		Done:       true,
+		Operations: make([]ResponseOperation, 0, len(ops)),
+	}
+
+	for _, op := range ops {
+		res.Statuses[op.Status]++
+		if op.Status == PendingStatus {
+			res.Done = false
+		}
+		res.Operations = append(res.Operations, responseOperation(op))
+	}
+	return res
+}
+
+func Location(id string) string {
+	return "/api/v1/operations/" + id
+}
+
+func BatchLocation(id string) string {
+	return "/api/v1/batches/" + id
+}
+
+type batchIDKey struct{}
+
+// WithBatchID groups every operation created with ctx under one batch.
+func WithBatchID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, batchIDKey{}, id)
+}
+
+func BatchIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(batchIDKey{}).(string)
+	return id
+}
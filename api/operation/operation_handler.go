@@ -0,0 +1,59 @@
+package operation
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"skill-api-kafka/api"
+)
+
+type OperationStorage interface {
+	GetOperation(id string) (*Operation, error)
+	GetBatch(id string) ([]Operation, error)
+}
+
+type operationHandler struct {
+	operationStorage OperationStorage
+}
+
+func NewOperationHandler(operationStorage OperationStorage) operationHandler {
+	return operationHandler{
+		operationStorage: operationStorage,
+	}
+}
+
+func (h operationHandler) GetOperation(c *gin.Context) {
+	op, err := h.operationStorage.GetOperation(c.Param("id"))
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, api.ErrorResponse("operation not found"))
+		return
+	}
+
+	if err != nil {
+		log.Println("Error:", err)
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to get operation"))
+		return
+	}
+
+	c.JSON(http.StatusOK, api.SuccessResponse(responseOperation(*op)))
+}
+
+// GetBatch reports how far the operations of a batch have come, so an import
+// can be followed with a single request.
+func (h operationHandler) GetBatch(c *gin.Context) {
+	ops, err := h.operationStorage.GetBatch(c.Param("id"))
+	if err != nil {
+		log.Println("Error:", err)
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to get batch"))
+		return
+	}
+
+	if len(ops) == 0 {
+		c.JSON(http.StatusNotFound, api.ErrorResponse("batch not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, api.SuccessResponse(responseBatch(c.Param("id"), ops)))
+}
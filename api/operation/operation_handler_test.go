@@ -0,0 +1,188 @@
+package operation
+
+import (
+	"database/sql"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mockOperationStorage struct {
+	operation *Operation
+	batch     []Operation
+	errGet    error
+}
+
+func (m *mockOperationStorage) GetOperation(id string) (*Operation, error) {
+	if m.errGet != nil {
+		return nil, m.errGet
+	}
+	return m.operation, nil
+}
+
+func (m *mockOperationStorage) GetBatch(id string) ([]Operation, error) {
+	if m.errGet != nil {
+		return nil, m.errGet
+	}
+	return m.batch, nil
+}
+
+func TestGetOperationHandler(t *testing.T) {
+	at := time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		expectedBody   string
+		mockStorage    *mockOperationStorage
+	}{
+		{
+			name:           "get pending operation",
+			url:            "/operations/op-1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "success", "data": {"id": "op-1", "action": "create", "skill_key": "python", "status": "pending", "created_at": "2024-07-01T10:00:00Z", "updated_at": "2024-07-01T10:00:00Z"}}`,
+			mockStorage: &mockOperationStorage{
+				operation: &Operation{ID: "op-1", Action: "create", SkillKey: "python", Status: PendingStatus, CreatedAt: at, UpdatedAt: at},
+			},
+		},
+		{
+			name:           "get failed operation",
+			url:            "/operations/op-2",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "success", "data": {"id": "op-2", "action": "create", "skill_key": "python", "status": "failed", "reason": "duplicate key", "created_at": "2024-07-01T10:00:00Z", "updated_at": "2024-07-01T10:00:00Z"}}`,
+			mockStorage: &mockOperationStorage{
+				operation: &Operation{ID: "op-2", Action: "create", SkillKey: "python", Status: FailedStatus, Reason: "duplicate key", CreatedAt: at, UpdatedAt: at},
+			},
+		},
+		{
+			name:           "not exist operation",
+			url:            "/operations/op-3",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status": "error", "message": "operation not found"}`,
+			mockStorage:    &mockOperationStorage{errGet: sql.ErrNoRows},
+		},
+		{
+			name:           "database connection error",
+			url:            "/operations/op-4",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"status": "error", "message": "not be able to get operation"}`,
+			mockStorage:    &mockOperationStorage{errGet: sql.ErrConnDone},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			res := httptest.NewRecorder()
+			c, r := gin.CreateTestContext(res)
+			c.Request = httptest.NewRequest(http.MethodGet, tt.url, nil)
+
+			h := NewOperationHandler(tt.mockStorage)
+			r.GET("/operations/:id", h.GetOperation)
+			r.ServeHTTP(res, c.Request)
+
+			// Assert response
+			if status := res.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			// Parse and compare JSON
+			var actual, expectedJSON map[string]interface{}
+			if err := json.Unmarshal(res.Body.Bytes(), &actual); err != nil {
+				t.Fatalf("could not unmarshal response body: %v", err)
+			}
+
+			if err := json.Unmarshal([]byte(tt.expectedBody), &expectedJSON); err != nil {
+				t.Fatalf("could not unmarshal expected JSON: %v", err)
+			}
+
+			// Assert response body
+			if !reflect.DeepEqual(expectedJSON, actual) {
+				t.Errorf("handler returned unexpected body: got %v want %v", actual, expectedJSON)
+			}
+		})
+	}
+}
+
+func TestGetBatchHandler(t *testing.T) {
+	at := time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		expectedStatus int
+		expectedBody   string
+		mockStorage    *mockOperationStorage
+	}{
+		{
+			name:           "get batch in progress",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "success", "data": {"id": "batch-1", "total": 2, "statuses": {"succeeded": 1, "pending": 1}, "done": false, "operations": [{"id": "op-1", "action": "create", "skill_key": "go", "status": "succeeded", "batch_id": "batch-1", "created_at": "2024-07-01T10:00:00Z", "updated_at": "2024-07-01T10:00:00Z"}, {"id": "op-2", "action": "update", "skill_key": "python", "status": "pending", "batch_id": "batch-1", "created_at": "2024-07-01T10:00:00Z", "updated_at": "2024-07-01T10:00:00Z"}]}}`,
+			mockStorage: &mockOperationStorage{
+				batch: []Operation{
+					{ID: "op-1", Action: "create", SkillKey: "go", Status: SucceededStatus, BatchID: "batch-1", CreatedAt: at, UpdatedAt: at},
+					{ID: "op-2", Action: "update", SkillKey: "python", Status: PendingStatus, BatchID: "batch-1", CreatedAt: at, UpdatedAt: at},
+				},
+			},
+		},
+		{
+			name:           "get finished batch",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "success", "data": {"id": "batch-1", "total": 1, "statuses": {"failed": 1}, "done": true, "operations": [{"id": "op-1", "action": "create", "skill_key": "go", "status": "failed", "reason": "duplicate key", "batch_id": "batch-1", "created_at": "2024-07-01T10:00:00Z", "updated_at": "2024-07-01T10:00:00Z"}]}}`,
+			mockStorage: &mockOperationStorage{
+				batch: []Operation{
+					{ID: "op-1", Action: "create", SkillKey: "go", Status: FailedStatus, Reason: "duplicate key", BatchID: "batch-1", CreatedAt: at, UpdatedAt: at},
+				},
+			},
+		},
+		{
+			name:           "not exist batch",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status": "error", "message": "batch not found"}`,
+			mockStorage:    &mockOperationStorage{},
+		},
+		{
+			name:           "database connection error",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"status": "error", "message": "not be able to get batch"}`,
+			mockStorage:    &mockOperationStorage{errGet: sql.ErrConnDone},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			res := httptest.NewRecorder()
+			c, r := gin.CreateTestContext(res)
+			c.Request = httptest.NewRequest(http.MethodGet, "/batches/batch-1", nil)
+
+			h := NewOperationHandler(tt.mockStorage)
+			r.GET("/batches/:id", h.GetBatch)
+			r.ServeHTTP(res, c.Request)
+
+			// Assert response
+			if status := res.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			// Parse and compare JSON
+			var actual, expectedJSON map[string]interface{}
+			if err := json.Unmarshal(res.Body.Bytes(), &actual); err != nil {
+				t.Fatalf("could not unmarshal response body: %v", err)
+			}
+
+			if err := json.Unmarshal([]byte(tt.expectedBody), &expectedJSON); err != nil {
+				t.Fatalf("could not unmarshal expected JSON: %v", err)
+			}
+
+			// Assert response body
+			if !reflect.DeepEqual(expectedJSON, actual) {
+				t.Errorf("handler returned unexpected body: got %v want %v", actual, expectedJSON)
+			}
+		})
+	}
+}
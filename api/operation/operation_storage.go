@@ -0,0 +1,63 @@
+package operation
+
+import "database/sql"
+
+// execer is what CreateOperation needs from either the database or a
+// transaction.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+type operationStorage struct {
+	db *sql.DB
+	q  execer
+}
+
+func NewOperationStorage(db *sql.DB) operationStorage {
+	return operationStorage{db: db, q: db}
+}
+
+// NewTxOperationStorage creates operations within tx, so an operation is only
+// written along with the message that completes it. It cannot read.
+func NewTxOperationStorage(tx *sql.Tx) operationStorage {
+	return operationStorage{q: tx}
+}
+
+func (s operationStorage) CreateOperation(op Operation) error {
+	qry := `INSERT INTO skill_operation (id, action, skill_key, status, request_id, batch_id) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := s.q.Exec(qry, op.ID, op.Action, op.SkillKey, PendingStatus, op.RequestID, op.BatchID)
+	return err
+}
+
+func (s operationStorage) GetOperation(id string) (*Operation, error) {
+	var op Operation
+	result := s.db.QueryRow("SELECT id,action,skill_key,status,reason,request_id,batch_id,created_at,updated_at from skill_operation where id = $1", id)
+	err := result.Scan(&op.ID, &op.Action, &op.SkillKey, &op.Status, &op.Reason, &op.RequestID, &op.BatchID, &op.CreatedAt, &op.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &op, nil
+}
+
+// GetBatch returns the operations of a batch in the order they were created,
+// none when there is no such batch.
+func (s operationStorage) GetBatch(id string) ([]Operation, error) {
+	rows, err := s.db.Query("SELECT id,action,skill_key,status,reason,request_id,batch_id,created_at,updated_at from skill_operation where batch_id = $1 ORDER BY created_at, id", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ops := make([]Operation, 0)
+	for rows.Next() {
+		var op Operation
+		err := rows.Scan(&op.ID, &op.Action, &op.SkillKey, &op.Status, &op.Reason, &op.RequestID, &op.BatchID, &op.CreatedAt, &op.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+	}
+
+	return ops, rows.Err()
+}
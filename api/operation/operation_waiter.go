@@ -0,0 +1,173 @@
+package operation
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/IBM/sarama"
+	"log"
+	"sync"
+	"time"
+)
+
+type Result struct {
+	OperationID string `json:"operation_id"`
+	Status      Status `json:"status"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// Waiter lets a request block until the consumer reports the outcome of the
+// operation it published. Outcomes arrive on the result topic, which every
+// API instance reads in full since the waiting request may be on any of them.
+type Waiter struct {
+	storage OperationStorage
+	mu      sync.Mutex
+	waiting map[string][]chan Result
+}
+
+func NewWaiter(storage OperationStorage) *Waiter {
+	return &Waiter{
+		storage: storage,
+		waiting: make(map[string][]chan Result),
+	}
+}
+
+// Wait returns the finished operation, or ctx.Err() if it is still pending
+// when ctx is done.
+func (w *Waiter) Wait(ctx context.Context, id string) (*Operation, error) {
+	ch := make(chan Result, 1)
+	w.mu.Lock()
+	w.waiting[id] = append(w.waiting[id], ch)
+	w.mu.Unlock()
+	defer w.remove(id, ch)
+
+	// The outcome may have been recorded before the waiter was registered.
+	if op, err := w.storage.GetOperation(id); err == nil && op.Status != PendingStatus {
+		return op, nil
+	}
+
+	select {
+	case result := <-ch:
+		if op, err := w.storage.GetOperation(id); err == nil && op.Status != PendingStatus {
+			return op, nil
+		}
+		return &Operation{ID: result.OperationID, Status: result.Status, Reason: result.Reason}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (w *Waiter) Complete(result Result) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, ch := range w.waiting[result.OperationID] {
+		select {
+		case ch <- result:
+		default:
+		}
+	}
+}
+
+func (w *Waiter) remove(id string, ch chan Result) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	waiting := w.waiting[id]
+	for i, c := range waiting {
+		if c == ch {
+			waiting = append(waiting[:i], waiting[i+1:]...)
+			break
+		}
+	}
+
+	if len(waiting) == 0 {
+		delete(w.waiting, id)
+		return
+	}
+	w.waiting[id] = waiting
+}
+
+// Listen completes waiting requests from the result topic until ctx is done.
+// Only results produced after startup matter, so the partitions there are at
+// startup are read from the newest offset. The partitions are listed again
+// every refresh, and one added since is read from the oldest offset since
+// all of its results were produced after startup.
+func (w *Waiter) Listen(ctx context.Context, consumer sarama.Consumer, topic string, refresh time.Duration) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	// initial holds the partitions of the first listing, nil until there is
+	// one. A partition that fails to be consumed is tried again next time.
+	var initial map[int32]bool
+	consuming := make(map[int32]bool)
+	for {
+		partitions, err := consumer.Partitions(topic)
+		if err != nil {
+			log.Printf("Error getting partitions of topic: %s, error: %s", topic, err)
+		} else if initial == nil {
+			initial = make(map[int32]bool, len(partitions))
+			for _, partition := range partitions {
+				initial[partition] = true
+			}
+		}
+
+		for _, partition := range partitions {
+			if consuming[partition] {
+				continue
+			}
+
+			offset := sarama.OffsetOldest
+			if initial[partition] {
+				offset = sarama.OffsetNewest
+			}
+
+			pc, err := consumer.ConsumePartition(topic, partition, offset)
+			if err != nil {
+				log.Printf("Error consuming topic: %s, partition: %d, error: %s", topic, partition, err)
+				continue
+			}
+			consuming[partition] = true
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				w.consume(ctx, pc)
+			}()
+		}
+
+		// Until the partitions are first listed no result is read at all, so
+		// listing is retried sooner.
+		wait := refresh
+		if initial == nil {
+			wait = time.Second
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (w *Waiter) consume(ctx context.Context, pc sarama.PartitionConsumer) {
+	defer pc.AsyncClose()
+
+	for {
+		select {
+		case msg, ok := <-pc.Messages():
+			if !ok {
+				return
+			}
+
+			var result Result
+			if err := json.Unmarshal(msg.Value, &result); err != nil {
+				log.Printf("Error decoding result at partition: %d, offset: %d, error: %s", msg.Partition, msg.Offset, err)
+				continue
+			}
+			w.Complete(result)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
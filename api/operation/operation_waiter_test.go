@@ -0,0 +1,139 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+)
+
+func TestWaiterWait(t *testing.T) {
+	t.Run("should return operation completed from result topic", func(t *testing.T) {
+		// Arrange
+		storage := &mockOperationStorage{operation: &Operation{ID: "op-1", Status: PendingStatus}}
+		w := NewWaiter(storage)
+
+		consumer := mocks.NewConsumer(t, nil)
+		consumer.SetTopicMetadata(map[string][]int32{"skill_topic_result": {0}})
+		consumer.ExpectConsumePartition("skill_topic_result", 0, sarama.OffsetNewest).
+			YieldMessage(&sarama.ConsumerMessage{Value: []byte(`{"operation_id":"op-2","status":"succeeded"}`)})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		done := make(chan *Operation)
+		go func() {
+			op, _ := w.Wait(ctx, "op-2")
+			done <- op
+		}()
+
+		// Wait until the request is registered before results start flowing.
+		for {
+			w.mu.Lock()
+			n := len(w.waiting["op-2"])
+			w.mu.Unlock()
+			if n == 1 {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		go w.Listen(ctx, consumer, "skill_topic_result", time.Minute)
+
+		// Act
+		op := <-done
+
+		// Assert
+		if op == nil || op.Status != SucceededStatus {
+			t.Errorf("expected succeeded operation, got %v", op)
+		}
+	})
+
+	t.Run("should return operation already finished", func(t *testing.T) {
+		// Arrange
+		storage := &mockOperationStorage{operation: &Operation{ID: "op-1", Status: FailedStatus, Reason: "invalid payload"}}
+		w := NewWaiter(storage)
+
+		// Act
+		op, err := w.Wait(context.Background(), "op-1")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if op.Status != FailedStatus || op.Reason != "invalid payload" {
+			t.Errorf("expected failed operation, got %v", op)
+		}
+	})
+
+	t.Run("should time out while operation is pending", func(t *testing.T) {
+		// Arrange
+		storage := &mockOperationStorage{operation: &Operation{ID: "op-1", Status: PendingStatus}}
+		w := NewWaiter(storage)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// Act
+		_, err := w.Wait(ctx, "op-1")
+
+		// Assert
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+
+		if len(w.waiting) != 0 {
+			t.Errorf("expected waiter to be removed, got %d", len(w.waiting))
+		}
+	})
+}
+
+func TestWaiterListen(t *testing.T) {
+	t.Run("should read partition added while listening from oldest offset", func(t *testing.T) {
+		// Arrange
+		w := NewWaiter(&mockOperationStorage{operation: &Operation{ID: "op-1", Status: PendingStatus}})
+		started, ch := make(chan Result, 1), make(chan Result, 1)
+		w.waiting["op-0"] = []chan Result{started}
+		w.waiting["op-1"] = []chan Result{ch}
+
+		consumer := mocks.NewConsumer(t, nil)
+		consumer.SetTopicMetadata(map[string][]int32{"skill_topic_result": {0}})
+		consumer.ExpectConsumePartition("skill_topic_result", 0, sarama.OffsetNewest).
+			YieldMessage(&sarama.ConsumerMessage{Value: []byte(`{"operation_id":"op-0","status":"succeeded"}`)})
+		consumer.ExpectConsumePartition("skill_topic_result", 1, sarama.OffsetOldest).
+			YieldMessage(&sarama.ConsumerMessage{Partition: 1, Value: []byte(`{"operation_id":"op-1","status":"succeeded"}`)})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		done := make(chan struct{})
+		go func() {
+			w.Listen(ctx, consumer, "skill_topic_result", time.Millisecond)
+			close(done)
+		}()
+
+		// The partitions at startup are being read once op-0 completes.
+		<-started
+
+		// Act
+		consumer.SetTopicMetadata(map[string][]int32{"skill_topic_result": {0, 1}})
+
+		// Assert
+		select {
+		case result := <-ch:
+			if result.Status != SucceededStatus {
+				t.Errorf("expected succeeded result, got %v", result)
+			}
+		case <-ctx.Done():
+			t.Fatal("expected result from added partition")
+		}
+
+		cancel()
+		<-done
+		if err := consumer.Close(); err != nil {
+			t.Errorf("expected no error, got %s", err)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package outbox
+
+import (
+	"database/sql"
+	"encoding/json"
+	"github.com/lib/pq"
+	"time"
+)
+
+type Message struct {
+	ID      int64
+	Topic   string
+	Key     *string
+	Value   []byte
+	Headers map[string]string
+}
+
+// execer is what Enqueue needs from either the database or a transaction.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+type outboxStorage struct {
+	db *sql.DB
+	q  execer
+}
+
+func NewOutboxStorage(db *sql.DB) outboxStorage {
+	return outboxStorage{db: db, q: db}
+}
+
+// NewTxOutboxStorage enqueues within tx, so a message is only written along
+// with the change it announces. It cannot dispatch.
+func NewTxOutboxStorage(tx *sql.Tx) outboxStorage {
+	return outboxStorage{q: tx}
+}
+
+func (s outboxStorage) Enqueue(msg Message) error {
+	headers, err := json.Marshal(msg.Headers)
+	if err != nil {
+		return err
+	}
+
+	qry := `INSERT INTO skill_outbox (topic, key, value, headers) VALUES ($1, $2, $3, $4)`
+	_, err = s.q.Exec(qry, msg.Topic, msg.Key, msg.Value, headers)
+	return err
+}
+
+// relayLock is the advisory lock a relay holds while it dispatches, so only
+// one API instance relays at a time.
+const relayLock = 7_317_301
+
+// Dispatch hands up to limit pending messages to send in insertion order and
+// marks the ones that were sent. It stops at the first failure so a later
+// message never overtakes an earlier one for the same skill. While another
+// API instance is dispatching it sends nothing, since its pending messages
+// could otherwise overtake the ones in flight there.
+func (s outboxStorage) Dispatch(limit int, send func(Message) error) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRow(`SELECT pg_try_advisory_xact_lock($1)`, relayLock).Scan(&locked); err != nil {
+		return 0, err
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	rows, err := tx.Query(`SELECT id, topic, key, value, headers FROM skill_outbox WHERE sent_at IS NULL ORDER BY id LIMIT $1`, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	messages := make([]Message, 0)
+	for rows.Next() {
+		var msg Message
+		var headers []byte
+		if err := rows.Scan(&msg.ID, &msg.Topic, &msg.Key, &msg.Value, &headers); err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		if err := json.Unmarshal(headers, &msg.Headers); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		messages = append(messages, msg)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	sent := make([]int64, 0, len(messages))
+	var sendErr error
+	for _, msg := range messages {
+		if sendErr = send(msg); sendErr != nil {
+			break
+		}
+		sent = append(sent, msg.ID)
+	}
+
+	if len(sent) > 0 {
+		if _, err := tx.Exec(`UPDATE skill_outbox SET sent_at = now() WHERE id = ANY($1)`, pq.Array(sent)); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(sent), sendErr
+}
+
+// DeleteSent removes messages sent before the given time and returns how
+// many were removed. Pending messages are never removed.
+func (s outboxStorage) DeleteSent(before time.Time) (int64, error) {
+	res, err := s.db.Exec(`DELETE FROM skill_outbox WHERE sent_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,146 @@
+package outbox
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"log"
+	"skill-api-kafka/config"
+	"skill-api-kafka/metrics"
+	"skill-api-kafka/tracing"
+	"strconv"
+	"time"
+)
+
+type Storage interface {
+	Dispatch(limit int, send func(Message) error) (int, error)
+	DeleteSent(before time.Time) (int64, error)
+}
+
+type Relay struct {
+	storage  Storage
+	producer sarama.SyncProducer
+	config   config.OutboxConfig
+	now      func() time.Time
+}
+
+func NewRelay(storage Storage, producer sarama.SyncProducer, config config.OutboxConfig) Relay {
+	return Relay{
+		storage:  storage,
+		producer: producer,
+		config:   config,
+		now:      time.Now,
+	}
+}
+
+// Run publishes pending outbox messages until ctx is done. A message stays
+// pending until Kafka acknowledges it, so anything written while the broker
+// or the API was down is published once both are back. Sent messages are
+// swept once they are older than the retention, unless it is zero.
+func (r Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	var sweep <-chan time.Time
+	if r.config.Retention > 0 {
+		sweepTicker := time.NewTicker(r.config.SweepInterval)
+		defer sweepTicker.Stop()
+		sweep = sweepTicker.C
+	}
+
+	for {
+		select {
+		case <-sweep:
+			if _, err := r.Sweep(); err != nil {
+				log.Println("Error sweeping outbox:", err)
+			}
+		case <-ticker.C:
+			for {
+				n, err := r.Flush()
+				if err != nil {
+					log.Println("Error relaying outbox:", err)
+					break
+				}
+
+				if n < r.config.BatchSize {
+					break
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r Relay) Flush() (int, error) {
+	return r.storage.Dispatch(r.config.BatchSize, r.send)
+}
+
+// Sweep deletes messages sent longer ago than the retention. Sweeping is
+// idempotent, so every API instance can run it.
+func (r Relay) Sweep() (int64, error) {
+	n, err := r.storage.DeleteSent(r.now().Add(-r.config.Retention))
+	if err != nil {
+		return 0, err
+	}
+
+	if n > 0 {
+		log.Printf("Swept %d sent outbox messages", n)
+		metrics.OutboxSwept.Add(float64(n))
+	}
+	return n, nil
+}
+
+// send continues the trace of the request that wrote msg, and replaces the
+// trace context in its headers with the send span so the consumer's span is
+// a child of it.
+func (r Relay) send(msg Message) error {
+	propagator := otel.GetTextMapPropagator()
+	ctx := propagator.Extract(context.Background(), propagation.MapCarrier(msg.Headers))
+	ctx, span := tracing.Tracer.Start(ctx, msg.Topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypePublish,
+			semconv.MessagingDestinationName(msg.Topic),
+		),
+	)
+	defer span.End()
+
+	carrier := propagation.MapCarrier{}
+	for k, v := range msg.Headers {
+		carrier[k] = v
+	}
+	propagator.Inject(ctx, carrier)
+
+	headers := make([]sarama.RecordHeader, 0, len(carrier))
+	for k, v := range carrier {
+		headers = append(headers, sarama.RecordHeader{Key: []byte(k), Value: []byte(v)})
+	}
+
+	producerMessage := &sarama.ProducerMessage{
+		Topic:   msg.Topic,
+		Value:   sarama.ByteEncoder(msg.Value),
+		Headers: headers,
+	}
+	if msg.Key != nil {
+		producerMessage.Key = sarama.StringEncoder(*msg.Key)
+	}
+
+	partition, offset, err := r.producer.SendMessage(producerMessage)
+	metrics.OutboxSent.WithLabelValues(msg.Topic, metrics.Result(err)).Inc()
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	span.SetAttributes(
+		semconv.MessagingDestinationPartitionID(strconv.Itoa(int(partition))),
+		semconv.MessagingKafkaMessageOffset(int(offset)),
+	)
+	return nil
+}
@@ -0,0 +1,215 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"skill-api-kafka/config"
+	"testing"
+	"time"
+)
+
+type mockStorage struct {
+	pending []Message
+	sent    []int64
+	swept   int64
+	before  time.Time
+	err     error
+}
+
+func (m *mockStorage) Dispatch(limit int, send func(Message) error) (int, error) {
+	n := 0
+	for _, msg := range m.pending {
+		if n == limit {
+			break
+		}
+		if err := send(msg); err != nil {
+			m.pending = m.pending[n:]
+			return n, err
+		}
+		m.sent = append(m.sent, msg.ID)
+		n++
+	}
+	m.pending = m.pending[n:]
+	return n, nil
+}
+
+func (m *mockStorage) DeleteSent(before time.Time) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.before = before
+	return m.swept, nil
+}
+
+func TestRelayFlush(t *testing.T) {
+	t.Run("should publish pending messages in order", func(t *testing.T) {
+		// Arrange
+		key := "python"
+		storage := &mockStorage{pending: []Message{
+			{ID: 1, Topic: "skill_topic", Key: &key, Value: []byte(`{"action":"create"}`), Headers: map[string]string{"x-request-id": "abc"}},
+			{ID: 2, Topic: "skill_topic", Key: &key, Value: []byte(`{"action":"update_name"}`)},
+		}}
+
+		producer := mocks.NewSyncProducer(t, nil)
+		defer producer.Close()
+		producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+			k, _ := msg.Key.Encode()
+			if string(k) != "python" {
+				t.Errorf("expected key python, got %s", k)
+			}
+			if len(msg.Headers) != 1 || string(msg.Headers[0].Value) != "abc" {
+				t.Errorf("expected headers to be relayed, got %v", msg.Headers)
+			}
+			return nil
+		})
+		producer.ExpectSendMessageWithCheckerFunctionAndSucceed(func(val []byte) error {
+			if string(val) != `{"action":"update_name"}` {
+				t.Errorf("expected second message, got %s", val)
+			}
+			return nil
+		})
+
+		r := NewRelay(storage, producer, config.OutboxConfig{BatchSize: 10})
+
+		// Act
+		n, err := r.Flush()
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if n != 2 || len(storage.sent) != 2 {
+			t.Errorf("expected 2 messages sent, got %d", n)
+		}
+	})
+
+	t.Run("should keep message pending when broker is unavailable", func(t *testing.T) {
+		// Arrange
+		storage := &mockStorage{pending: []Message{
+			{ID: 1, Topic: "skill_topic", Value: []byte(`{}`)},
+			{ID: 2, Topic: "skill_topic", Value: []byte(`{}`)},
+		}}
+
+		producer := mocks.NewSyncProducer(t, nil)
+		defer producer.Close()
+		producer.ExpectSendMessageAndFail(sarama.ErrOutOfBrokers)
+
+		r := NewRelay(storage, producer, config.OutboxConfig{BatchSize: 10})
+
+		// Act
+		n, err := r.Flush()
+
+		// Assert
+		if err == nil {
+			t.Fatal("expected error to be not nil")
+		}
+
+		if n != 0 || len(storage.pending) != 2 {
+			t.Errorf("expected both messages to stay pending, got %d sent", n)
+		}
+	})
+}
+
+func TestRelayContinuesTrace(t *testing.T) {
+	// Arrange
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(sdktrace.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "POST /api/v1/skills")
+	headers := map[string]string{}
+	propagation.TraceContext{}.Inject(ctx, propagation.MapCarrier(headers))
+	parent.End()
+
+	storage := &mockStorage{pending: []Message{{ID: 1, Topic: "skill_topic", Value: []byte(`{}`), Headers: headers}}}
+
+	var traceparent string
+	producer := mocks.NewSyncProducer(t, nil)
+	defer producer.Close()
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		for _, h := range msg.Headers {
+			if string(h.Key) == "traceparent" {
+				traceparent = string(h.Value)
+			}
+		}
+		return nil
+	})
+
+	r := NewRelay(storage, producer, config.OutboxConfig{BatchSize: 10})
+
+	// Act
+	_, err := r.Flush()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected request and publish spans, got %d", len(spans))
+	}
+
+	publish := spans[1]
+	if publish.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("expected publish span to be a child of the request span")
+	}
+
+	want := "00-" + publish.SpanContext().TraceID().String() + "-" + publish.SpanContext().SpanID().String() + "-01"
+	if traceparent != want {
+		t.Errorf("expected traceparent %s, got %s", want, traceparent)
+	}
+}
+
+func TestRelaySweep(t *testing.T) {
+	t.Run("should delete messages sent before retention", func(t *testing.T) {
+		// Arrange
+		storage := &mockStorage{swept: 3}
+		r := NewRelay(storage, nil, config.OutboxConfig{SweepInterval: time.Hour, Retention: 24 * time.Hour})
+		now := time.Date(2024, 7, 2, 0, 0, 0, 0, time.UTC)
+		r.now = func() time.Time { return now }
+
+		// Act
+		n, err := r.Sweep()
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if n != 3 {
+			t.Errorf("expected 3 messages swept, got %d", n)
+		}
+
+		if want := now.Add(-24 * time.Hour); !storage.before.Equal(want) {
+			t.Errorf("expected messages sent before %s to be swept, got %s", want, storage.before)
+		}
+	})
+
+	t.Run("should return storage error", func(t *testing.T) {
+		// Arrange
+		storage := &mockStorage{err: sql.ErrConnDone}
+		r := NewRelay(storage, nil, config.OutboxConfig{SweepInterval: time.Hour, Retention: 24 * time.Hour})
+
+		// Act
+		_, err := r.Sweep()
+
+		// Assert
+		if !errors.Is(err, sql.ErrConnDone) {
+			t.Errorf("expected error %s, got %v", sql.ErrConnDone, err)
+		}
+	})
+}
@@ -1,10 +1,38 @@
 package skill
 
+import (
+	"context"
+	"skill-api-kafka/operation"
+)
+
 type mockSkillQueue struct {
 	SkillQueue
-	errPublish error
+	errPublish      error
+	action          SkillAction
+	expectedVersion int64
+	published       []SkillAction
+	batchID         string
+}
+
+func (m *mockSkillQueue) PublishSkill(ctx context.Context, action SkillAction, key *string, expectedVersion int64, skillPayload interface{}) (string, error) {
+	if m.errPublish != nil {
+		return "", m.errPublish
+	}
+	m.action = action
+	m.expectedVersion = expectedVersion
+	m.published = append(m.published, action)
+	m.batchID = operation.BatchIDFrom(ctx)
+	return "op-1", nil
+}
+
+type mockOperationWaiter struct {
+	operation *operation.Operation
+	err       error
 }
 
-func (m *mockSkillQueue) PublishSkill(action SkillAction, key *string, skillPayload interface{}) error {
-	return m.errPublish
+func (m *mockOperationWaiter) Wait(ctx context.Context, id string) (*operation.Operation, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.operation, nil
 }
@@ -1,28 +1,98 @@
 package skill
 
+import (
+	"context"
+	"database/sql"
+)
+
 type mockSkillStorage struct {
 	SkillStorage
 	skill                 *Skill
 	skills                []Skill
+	deletedSkill          *Skill
+	deletedSkills         []Skill
+	history               []HistoryEntry
+	next                  *Cursor
+	searchResults         []SearchResult
+	tags                  []TagCount
+	existing              map[string]bool
+	listQuery             ListQuery
 	errGet                error
 	errUpdateCreateDelete error
 }
 
-func (m *mockSkillStorage) GetSkill(key string) (*Skill, error) {
+func (m *mockSkillStorage) GetSkill(ctx context.Context, key string) (*Skill, error) {
 	if m.errGet != nil {
 		return nil, m.errGet
 	}
 	return m.skill, nil
 }
 
-func (m *mockSkillStorage) GetSkills() ([]Skill, error) {
-	skills := make([]Skill, 0)
+func (m *mockSkillStorage) ListSkills(ctx context.Context, q ListQuery) (SkillPage, error) {
+	m.listQuery = q
+	if m.errGet != nil {
+		return SkillPage{}, m.errGet
+	}
+
+	skills := m.skills
+	if q.Deleted {
+		skills = m.deletedSkills
+	}
+	return SkillPage{Skills: append(make([]Skill, 0), skills...), Total: len(skills), Next: m.next}, nil
+}
+
+func (m *mockSkillStorage) SearchSkills(ctx context.Context, q string, limit int) ([]SearchResult, error) {
+	if m.errGet != nil {
+		return nil, m.errGet
+	}
+	return append(make([]SearchResult, 0), m.searchResults...), nil
+}
+
+func (m *mockSkillStorage) GetTags(ctx context.Context) ([]TagCount, error) {
 	if m.errGet != nil {
-		return make([]Skill, 0), m.errGet
+		return nil, m.errGet
 	}
+	return append(make([]TagCount, 0), m.tags...), nil
+}
 
-	for _, skill := range m.skills {
-		skills = append(skills, skill)
+func (m *mockSkillStorage) ExistingKeys(ctx context.Context, keys []string) (map[string]bool, error) {
+	if m.errGet != nil {
+		return nil, m.errGet
+	}
+
+	existing := map[string]bool{}
+	for _, key := range keys {
+		if deleted, ok := m.existing[key]; ok {
+			existing[key] = deleted
+		}
+	}
+	return existing, nil
+}
+
+func (m *mockSkillStorage) GetSkillHistory(ctx context.Context, key string, before int64, limit int) ([]HistoryEntry, error) {
+	if m.errGet != nil {
+		return nil, m.errGet
+	}
+
+	entries := make([]HistoryEntry, 0)
+	for _, entry := range m.history {
+		if before != 0 && entry.ID >= before {
+			continue
+		}
+		if len(entries) == limit {
+			break
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (m *mockSkillStorage) GetDeletedSkill(ctx context.Context, key string) (*Skill, error) {
+	if m.errGet != nil {
+		return nil, m.errGet
+	}
+	if m.deletedSkill == nil {
+		return nil, sql.ErrNoRows
 	}
-	return skills, nil
+	return m.deletedSkill, nil
 }
@@ -1,12 +1,22 @@
 package skill
 
-type CreateSkillRequest struct {
-	Key         string   `json:"key" binding:"required"`
-	Name        string   `json:"name" binding:"required"`
-	Description string   `json:"description" binding:"required"`
-	Logo        string   `json:"logo" binding:"required"`
-	Tags        []string `json:"tags" binding:"required"`
-}
+import (
+	"skill-api-kafka-contract/message"
+	"time"
+)
+
+// Request bodies are published to Kafka as they are bound, so they are part
+// of the message contract shared with the consumer.
+type (
+	CreateSkillRequest            = message.CreateSkillRequest
+	UpdateSkillRequest            = message.UpdateSkillRequest
+	UpdateSkillNameRequest        = message.UpdateSkillNameRequest
+	UpdateSkillDescriptionRequest = message.UpdateSkillDescriptionRequest
+	UpdateSkillLogoRequest        = message.UpdateSkillLogoRequest
+	UpdateSkillTagsRequest        = message.UpdateSkillTagsRequest
+	AddSkillTagsRequest           = message.AddSkillTagsRequest
+	RemoveSkillTagsRequest        = message.RemoveSkillTagsRequest
+)
 
 type ResponseSkill struct {
 	Key         string   `json:"key"`
@@ -16,25 +26,15 @@ type ResponseSkill struct {
 	Tags        []string `json:"tags"`
 }
 
-type UpdateSkillRequest struct {
-	Name        string   `json:"name" binding:"required"`
-	Description string   `json:"description" binding:"required"`
-	Logo        string   `json:"logo" binding:"required"`
-	Tags        []string `json:"tags" binding:"required"`
-}
-
-type UpdateSkillNameRequest struct {
-	Name string `json:"name" binding:"required"`
-}
-
-type UpdateSkillDescriptionRequest struct {
-	Description string `json:"description" binding:"required"`
-}
-
-type UpdateSkillLogoRequest struct {
-	Logo string `json:"logo" binding:"required"`
+// ResponseDeletedSkill is a skill in the trash. Its version is what a
+// restore has to send in If-Match.
+type ResponseDeletedSkill struct {
+	ResponseSkill
+	Version   int64     `json:"version"`
+	DeletedAt time.Time `json:"deleted_at"`
 }
 
-type UpdateSkillTagsRequest struct {
-	Tags []string `json:"tags" binding:"required"`
+type ResponseAccepted struct {
+	OperationID string `json:"operation_id"`
+	Status      string `json:"status"`
 }
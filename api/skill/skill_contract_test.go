@@ -0,0 +1,103 @@
+package skill
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin/binding"
+	"skill-api-kafka-contract/message"
+	"skill-api-kafka-contract/schema"
+	"skill-api-kafka/config"
+	"testing"
+)
+
+// These tests hold the API to the message contract the consumer decodes. If
+// either side changes the wire format on its own, they fail.
+
+func TestPublishSkillFollowsContract(t *testing.T) {
+	registry, err := schema.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serializers, err := message.NewSerializers(registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	protobuf, _ := message.NewProtobufSerializer(registry)
+	for _, serializer := range []message.Serializer{message.JSONSerializer{}, protobuf} {
+		for _, example := range message.Examples() {
+			t.Run(serializer.ContentType()+"/"+string(example.Action), func(t *testing.T) {
+				// Arrange
+				o := &mockOutbox{}
+				q := NewSkillQueue(&mockPublishStorage{outbox: o, operations: &mockOperationStorage{}}, serializer, config.KafkaConfig{SkillTopic: "skill_topic", ProducerID: "skill-api/test"})
+
+				// Act
+				_, err := q.PublishSkill(context.Background(), example.Action, example.Key, example.ExpectedVersion, example.Payload)
+
+				// Assert
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+
+				// Decode the way the consumer does, by content type header.
+				sent := o.messages[0]
+				consumerSide, err := serializers.Lookup(sent.Headers[message.ContentTypeHeader])
+				if err != nil {
+					t.Fatalf("expected consumer to accept the content type, got %s", err)
+				}
+
+				got, err := consumerSide.Unmarshal(sent.Value)
+				if err != nil {
+					t.Fatalf("expected consumer to decode the message, got %s", err)
+				}
+
+				if got.Action != example.Action || *got.Key != *example.Key {
+					t.Errorf("expected %s for %s, got %s for %s", example.Action, *example.Key, got.Action, *got.Key)
+				}
+
+				want, _ := json.Marshal(example.Payload)
+				have, _ := json.Marshal(got.Payload)
+				if string(have) != string(want) {
+					t.Errorf("expected payload %s, got %s", want, have)
+				}
+			})
+		}
+	}
+}
+
+func TestBindingAgreesWithContract(t *testing.T) {
+	requests := []struct {
+		name  string
+		valid any
+		empty any
+	}{
+		{"create", CreateSkillRequest{Key: "go", Name: "Go", Description: "Go", Logo: "logo", Tags: []string{}}, CreateSkillRequest{}},
+		{"update", UpdateSkillRequest{Name: "Go", Description: "Go", Logo: "logo", Tags: []string{}}, UpdateSkillRequest{}},
+		{"name", UpdateSkillNameRequest{Name: "Go"}, UpdateSkillNameRequest{}},
+		{"description", UpdateSkillDescriptionRequest{Description: "Go"}, UpdateSkillDescriptionRequest{}},
+		{"logo", UpdateSkillLogoRequest{Logo: "logo"}, UpdateSkillLogoRequest{}},
+		{"tags", UpdateSkillTagsRequest{Tags: []string{}}, UpdateSkillTagsRequest{}},
+		{"add tags", AddSkillTagsRequest{Tags: []string{}}, AddSkillTagsRequest{}},
+		{"remove tags", RemoveSkillTagsRequest{Tags: []string{}}, RemoveSkillTagsRequest{}},
+	}
+
+	for _, tt := range requests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			validBinding := binding.Validator.ValidateStruct(tt.valid)
+			validContract := tt.valid.(interface{ Validate() error }).Validate()
+			emptyBinding := binding.Validator.ValidateStruct(tt.empty)
+			emptyContract := tt.empty.(interface{ Validate() error }).Validate()
+
+			// Assert
+			if validBinding != nil || validContract != nil {
+				t.Errorf("expected valid request to pass both, got binding %v, contract %v", validBinding, validContract)
+			}
+
+			if emptyBinding == nil || emptyContract == nil {
+				t.Errorf("expected empty request to fail both, got binding %v, contract %v", emptyBinding, emptyContract)
+			}
+		})
+	}
+}
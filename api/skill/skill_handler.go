@@ -1,38 +1,53 @@
 package skill
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"log"
 	"net/http"
 	"skill-api-kafka/api"
+	"skill-api-kafka/operation"
+	"strconv"
 )
 
 type SkillStorage interface {
-	GetSkill(key string) (*Skill, error)
-	GetSkills() ([]Skill, error)
+	GetSkill(ctx context.Context, key string) (*Skill, error)
+	ListSkills(ctx context.Context, q ListQuery) (SkillPage, error)
+	SearchSkills(ctx context.Context, q string, limit int) ([]SearchResult, error)
+	GetTags(ctx context.Context) ([]TagCount, error)
+	ExistingKeys(ctx context.Context, keys []string) (map[string]bool, error)
+	GetDeletedSkill(ctx context.Context, key string) (*Skill, error)
+	GetSkillHistory(ctx context.Context, key string, before int64, limit int) ([]HistoryEntry, error)
 }
 
 type SkillQueue interface {
-	PublishSkill(action SkillAction, key *string, skillPayload interface{}) error
+	PublishSkill(ctx context.Context, action SkillAction, key *string, expectedVersion int64, skillPayload interface{}) (string, error)
+}
+
+type OperationWaiter interface {
+	Wait(ctx context.Context, id string) (*operation.Operation, error)
 }
 
 type skillHandler struct {
 	skillStorage SkillStorage
 	skillQueue   SkillQueue
+	waiter       OperationWaiter
 }
 
-func NewSkillHandler(skillStorage SkillStorage, skillQueue SkillQueue) skillHandler {
+func NewSkillHandler(skillStorage SkillStorage, skillQueue SkillQueue, waiter OperationWaiter) skillHandler {
 	return skillHandler{
 		skillStorage: skillStorage,
 		skillQueue:   skillQueue,
+		waiter:       waiter,
 	}
 }
 
 func (h skillHandler) GetSkill(c *gin.Context) {
 	idParams := c.Param("key")
-	skill, err := h.skillStorage.GetSkill(idParams)
+	skill, err := h.skillStorage.GetSkill(c.Request.Context(), idParams)
 	if errors.Is(err, sql.ErrNoRows) {
 		c.JSON(http.StatusNotFound, api.ErrorResponse("Skill not found"))
 		return
@@ -44,6 +59,7 @@ func (h skillHandler) GetSkill(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", etag(skill.Version))
 	c.JSON(http.StatusOK, api.SuccessResponse(ResponseSkill{
 		Key:         skill.Key,
 		Name:        skill.Name,
@@ -53,17 +69,82 @@ func (h skillHandler) GetSkill(c *gin.Context) {
 	}))
 }
 
+// GetSkills lists live skills a page at a time, or with ?deleted=true the
+// trash, which only admins may see. See listQuery for the filters and sorts.
 func (h skillHandler) GetSkills(c *gin.Context) {
-	skills, err := h.skillStorage.GetSkills()
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+	deleted, err := strconv.ParseBool(c.DefaultQuery("deleted", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("deleted must be true or false"))
+		return
+	}
+
+	if deleted && !api.IsAdmin(c.Request.Context()) {
+		c.JSON(http.StatusForbidden, api.ErrorResponse("only admins can list deleted skills"))
+		return
+	}
+
+	q, err := listQuery(c, deleted)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse(err.Error()))
+		return
+	}
+
+	page, err := h.skillStorage.ListSkills(c.Request.Context(), q)
+	if err != nil {
 		log.Println("Error:", err)
+		if deleted {
+			c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to get deleted skills"))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to get skills"))
 		return
 	}
 
-	skillsMap := make([]ResponseSkill, 0)
+	if deleted {
+		c.JSON(http.StatusOK, api.PageResponse(responseDeletedSkills(page.Skills), responsePage(page)))
+		return
+	}
+
+	c.JSON(http.StatusOK, api.PageResponse(responseSkills(page.Skills), responsePage(page)))
+}
+
+// GetTags lists every tag in use on live skills with how many skills carry
+// it, most used first.
+func (h skillHandler) GetTags(c *gin.Context) {
+	tags, err := h.skillStorage.GetTags(c.Request.Context())
+	if err != nil {
+		log.Println("Error:", err)
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to get tags"))
+		return
+	}
+
+	c.JSON(http.StatusOK, api.SuccessResponse(responseTags(tags)))
+}
+
+// GetTagSkills lists the live skills carrying a tag, paged and sorted like
+// GetSkills.
+func (h skillHandler) GetTagSkills(c *gin.Context) {
+	q, err := listQuery(c, false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse(err.Error()))
+		return
+	}
+	q.Tags, q.AllTags = []string{c.Param("tag")}, false
+
+	page, err := h.skillStorage.ListSkills(c.Request.Context(), q)
+	if err != nil {
+		log.Println("Error:", err)
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to get skills"))
+		return
+	}
+
+	c.JSON(http.StatusOK, api.PageResponse(responseSkills(page.Skills), responsePage(page)))
+}
+
+func responseSkills(skills []Skill) []ResponseSkill {
+	res := make([]ResponseSkill, 0, len(skills))
 	for _, skill := range skills {
-		skillsMap = append(skillsMap, ResponseSkill{
+		res = append(res, ResponseSkill{
 			Key:         skill.Key,
 			Name:        skill.Name,
 			Description: skill.Description,
@@ -71,8 +152,64 @@ func (h skillHandler) GetSkills(c *gin.Context) {
 			Tags:        skill.Tags,
 		})
 	}
+	return res
+}
+
+func responseDeletedSkills(skills []Skill) []ResponseDeletedSkill {
+	res := make([]ResponseDeletedSkill, 0, len(skills))
+	for _, skill := range skills {
+		res = append(res, ResponseDeletedSkill{
+			ResponseSkill: ResponseSkill{
+				Key:         skill.Key,
+				Name:        skill.Name,
+				Description: skill.Description,
+				Logo:        skill.Logo,
+				Tags:        skill.Tags,
+			},
+			Version:   skill.Version,
+			DeletedAt: *skill.DeletedAt,
+		})
+	}
+	return res
+}
+
+// SearchSkills finds live skills by the words in their name, description
+// and tags, most relevant first.
+func (h skillHandler) SearchSkills(c *gin.Context) {
+	q, limit, err := searchQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse(err.Error()))
+		return
+	}
+
+	results, err := h.skillStorage.SearchSkills(c.Request.Context(), q, limit)
+	if err != nil {
+		log.Println("Error:", err)
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to search skills"))
+		return
+	}
+
+	c.JSON(http.StatusOK, api.SuccessResponse(responseSearchResults(results)))
+}
+
+// GetSkillHistory lists the changes made to a skill, newest first. History
+// outlives the skill, so a deleted skill still has its changes listed.
+func (h skillHandler) GetSkillHistory(c *gin.Context) {
+	before, limit, err := historyPage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse(err.Error()))
+		return
+	}
 
-	c.JSON(http.StatusOK, api.SuccessResponse(skillsMap))
+	// One entry past the page tells whether another page follows.
+	entries, err := h.skillStorage.GetSkillHistory(c.Request.Context(), c.Param("key"), before, limit+1)
+	if err != nil {
+		log.Println("Error:", err)
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to get skill history"))
+		return
+	}
+
+	c.JSON(http.StatusOK, api.SuccessResponse(responseHistory(entries, limit)))
 }
 
 func (h skillHandler) CreateSkill(c *gin.Context) {
@@ -83,7 +220,7 @@ func (h skillHandler) CreateSkill(c *gin.Context) {
 		return
 	}
 
-	skill, err := h.skillStorage.GetSkill(req.Key)
+	skill, err := h.skillStorage.GetSkill(c.Request.Context(), req.Key)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		log.Println("Error:", err)
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to get skill"))
@@ -95,13 +232,115 @@ func (h skillHandler) CreateSkill(c *gin.Context) {
 		return
 	}
 
-	if err := h.skillQueue.PublishSkill(CreateSkillAction, &req.Key, req); err != nil {
+	// The key of a deleted skill stays taken until it is purged.
+	deleted, err := h.skillStorage.GetDeletedSkill(c.Request.Context(), req.Key)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Println("Error:", err)
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to get skill"))
+		return
+	}
+
+	if deleted != nil {
+		c.JSON(http.StatusConflict, api.ErrorResponse("skill is deleted, restore it instead"))
+		return
+	}
+
+	operationID, err := h.skillQueue.PublishSkill(c.Request.Context(), CreateSkillAction, &req.Key, 0, req)
+	if err != nil {
 		log.Println("Error:", err)
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to create skill"))
 		return
 	}
 
-	c.JSON(http.StatusCreated, api.MessageResponse("creating skill already in progress"))
+	h.accepted(c, http.StatusCreated, operationID, req.Key, "creating skill already in progress")
+}
+
+// ImportSkills creates skills from a JSON array, NDJSON or CSV body. Rows are
+// checked like CreateSkill requests, the valid ones are published as one batch
+// followed at GET /batches/:id and the others are reported by row. With
+// ?on_conflict= a row whose key is taken is skipped, overwrites the skill, or
+// by default fails the import before anything is published.
+func (h skillHandler) ImportSkills(c *gin.Context) {
+	format, err := importFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse(err.Error()))
+		return
+	}
+
+	mode, err := conflictMode(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse(err.Error()))
+		return
+	}
+
+	rows, err := readImport(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes), format)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, api.ErrorResponse("import is too large"))
+		return
+	}
+	if err != nil {
+		log.Println("Error:", err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("invalid import: "+err.Error()))
+		return
+	}
+
+	keys := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.Err == nil {
+			keys = append(keys, row.Skill.Key)
+		}
+	}
+
+	existing, err := h.skillStorage.ExistingKeys(c.Request.Context(), keys)
+	if err != nil {
+		log.Println("Error:", err)
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to get skills"))
+		return
+	}
+
+	items, res, conflict := planImport(rows, existing, mode)
+	if conflict {
+		c.JSON(http.StatusConflict, api.ErrorDataResponse("some skills already exist, nothing was imported", res))
+		return
+	}
+
+	if len(items) == 0 {
+		c.JSON(http.StatusUnprocessableEntity, api.ErrorDataResponse("no skills to import", res))
+		return
+	}
+
+	batchID := uuid.NewString()
+	ctx := operation.WithBatchID(c.Request.Context(), batchID)
+	for _, item := range items {
+		req := item.row.Skill
+		var payload any = req
+		if item.action == UpdateSkillAction {
+			payload = UpdateSkillRequest{Name: req.Name, Description: req.Description, Logo: req.Logo, Tags: req.Tags}
+		}
+
+		// An import overwrites whatever version the skill is at.
+		if _, err := h.skillQueue.PublishSkill(ctx, item.action, &req.Key, 0, payload); err != nil {
+			log.Println("Error:", err)
+			res.fail(item.row, errors.New("not be able to publish skill"))
+			continue
+		}
+
+		if item.action == UpdateSkillAction {
+			res.Overwritten++
+		} else {
+			res.Created++
+		}
+	}
+
+	if res.Created+res.Overwritten == 0 {
+		c.JSON(http.StatusInternalServerError, api.ErrorDataResponse("not be able to import skills", res))
+		return
+	}
+
+	res.BatchID = batchID
+	c.Header("Location", operation.BatchLocation(batchID))
+	c.JSON(http.StatusAccepted, api.MessageDataResponse("importing skills already in progress", res))
 }
 
 func (h skillHandler) UpdateSkill(c *gin.Context) {
@@ -114,7 +353,7 @@ func (h skillHandler) UpdateSkill(c *gin.Context) {
 		return
 	}
 
-	skill, err := h.skillStorage.GetSkill(key)
+	skill, err := h.skillStorage.GetSkill(c.Request.Context(), key)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		log.Println("Error:", err)
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to get skill"))
@@ -126,13 +365,19 @@ func (h skillHandler) UpdateSkill(c *gin.Context) {
 		return
 	}
 
-	if err := h.skillQueue.PublishSkill(UpdateSkillAction, &key, req); err != nil {
+	expectedVersion, ok := matchVersion(c, skill)
+	if !ok {
+		return
+	}
+
+	operationID, err := h.skillQueue.PublishSkill(c.Request.Context(), UpdateSkillAction, &key, expectedVersion, req)
+	if err != nil {
 		log.Println("Error:", err)
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to update skill"))
 		return
 	}
 
-	c.JSON(http.StatusOK, api.MessageResponse("updating skill already in progress"))
+	h.accepted(c, http.StatusOK, operationID, key, "updating skill already in progress")
 }
 
 func (h skillHandler) UpdateName(c *gin.Context) {
@@ -145,7 +390,7 @@ func (h skillHandler) UpdateName(c *gin.Context) {
 		return
 	}
 
-	skill, err := h.skillStorage.GetSkill(key)
+	skill, err := h.skillStorage.GetSkill(c.Request.Context(), key)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		log.Println("Error:", err)
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to get skill"))
@@ -157,13 +402,19 @@ func (h skillHandler) UpdateName(c *gin.Context) {
 		return
 	}
 
-	if err := h.skillQueue.PublishSkill(UpdateNameAction, &key, req); err != nil {
+	expectedVersion, ok := matchVersion(c, skill)
+	if !ok {
+		return
+	}
+
+	operationID, err := h.skillQueue.PublishSkill(c.Request.Context(), UpdateNameAction, &key, expectedVersion, req)
+	if err != nil {
 		log.Println("Error:", err)
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to update skill name"))
 		return
 	}
 
-	c.JSON(http.StatusOK, api.MessageResponse("updating skill name already in progress"))
+	h.accepted(c, http.StatusOK, operationID, key, "updating skill name already in progress")
 }
 
 func (h skillHandler) UpdateDescription(c *gin.Context) {
@@ -176,7 +427,7 @@ func (h skillHandler) UpdateDescription(c *gin.Context) {
 		return
 	}
 
-	skill, err := h.skillStorage.GetSkill(key)
+	skill, err := h.skillStorage.GetSkill(c.Request.Context(), key)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		log.Println("Error:", err)
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to get skill"))
@@ -188,13 +439,19 @@ func (h skillHandler) UpdateDescription(c *gin.Context) {
 		return
 	}
 
-	if err := h.skillQueue.PublishSkill(UpdateDescAction, &key, req); err != nil {
+	expectedVersion, ok := matchVersion(c, skill)
+	if !ok {
+		return
+	}
+
+	operationID, err := h.skillQueue.PublishSkill(c.Request.Context(), UpdateDescAction, &key, expectedVersion, req)
+	if err != nil {
 		log.Println("Error:", err)
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to update skill description"))
 		return
 	}
 
-	c.JSON(http.StatusOK, api.MessageResponse("updating skill description already in progress"))
+	h.accepted(c, http.StatusOK, operationID, key, "updating skill description already in progress")
 }
 
 func (h skillHandler) UpdateLogo(c *gin.Context) {
@@ -207,7 +464,7 @@ func (h skillHandler) UpdateLogo(c *gin.Context) {
 		return
 	}
 
-	skill, err := h.skillStorage.GetSkill(key)
+	skill, err := h.skillStorage.GetSkill(c.Request.Context(), key)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		log.Println("Error:", err)
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to get skill"))
@@ -219,13 +476,19 @@ func (h skillHandler) UpdateLogo(c *gin.Context) {
 		return
 	}
 
-	if err := h.skillQueue.PublishSkill(UpdateLogoAction, &key, req); err != nil {
+	expectedVersion, ok := matchVersion(c, skill)
+	if !ok {
+		return
+	}
+
+	operationID, err := h.skillQueue.PublishSkill(c.Request.Context(), UpdateLogoAction, &key, expectedVersion, req)
+	if err != nil {
 		log.Println("Error:", err)
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to update skill logo"))
 		return
 	}
 
-	c.JSON(http.StatusOK, api.MessageResponse("updating skill logo already in progress"))
+	h.accepted(c, http.StatusOK, operationID, key, "updating skill logo already in progress")
 }
 
 func (h skillHandler) UpdateTags(c *gin.Context) {
@@ -238,7 +501,7 @@ func (h skillHandler) UpdateTags(c *gin.Context) {
 		return
 	}
 
-	skill, err := h.skillStorage.GetSkill(key)
+	skill, err := h.skillStorage.GetSkill(c.Request.Context(), key)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		log.Println("Error:", err)
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to get skill"))
@@ -250,19 +513,81 @@ func (h skillHandler) UpdateTags(c *gin.Context) {
 		return
 	}
 
-	if err := h.skillQueue.PublishSkill(UpdateTagsAction, &key, req); err != nil {
+	expectedVersion, ok := matchVersion(c, skill)
+	if !ok {
+		return
+	}
+
+	operationID, err := h.skillQueue.PublishSkill(c.Request.Context(), UpdateTagsAction, &key, expectedVersion, req)
+	if err != nil {
 		log.Println("Error:", err)
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to update skill tags"))
 		return
 	}
 
-	c.JSON(http.StatusOK, api.MessageResponse("updating skill tags already in progress"))
+	h.accepted(c, http.StatusOK, operationID, key, "updating skill tags already in progress")
+}
+
+// AddTags adds tags a skill does not have yet and RemoveTags drops the ones
+// it has. Both leave its other tags alone, so unlike UpdateTags they need no
+// If-Match: edits made at the same time merge instead of overwriting each
+// other.
+func (h skillHandler) AddTags(c *gin.Context) {
+	var req AddSkillTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println("Error:", err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("invalid request"))
+		return
+	}
+
+	h.editTags(c, AddTagsAction, req, "adding skill tags already in progress")
+}
+
+func (h skillHandler) RemoveTags(c *gin.Context) {
+	var req RemoveSkillTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println("Error:", err)
+		c.JSON(http.StatusBadRequest, api.ErrorResponse("invalid request"))
+		return
+	}
+
+	h.editTags(c, RemoveTagsAction, req, "removing skill tags already in progress")
+}
+
+func (h skillHandler) editTags(c *gin.Context, action SkillAction, req any, inProgress string) {
+	key := c.Param("key")
+
+	skill, err := h.skillStorage.GetSkill(c.Request.Context(), key)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Println("Error:", err)
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to get skill"))
+		return
+	}
+
+	if skill == nil {
+		c.JSON(http.StatusNotFound, api.ErrorResponse("skill not found"))
+		return
+	}
+
+	expectedVersion, ok := matchOptionalVersion(c, skill)
+	if !ok {
+		return
+	}
+
+	operationID, err := h.skillQueue.PublishSkill(c.Request.Context(), action, &key, expectedVersion, req)
+	if err != nil {
+		log.Println("Error:", err)
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to update skill tags"))
+		return
+	}
+
+	h.accepted(c, http.StatusOK, operationID, key, inProgress)
 }
 
 func (h skillHandler) DeleteSkill(c *gin.Context) {
 	key := c.Param("key")
 
-	_, err := h.skillStorage.GetSkill(key)
+	skill, err := h.skillStorage.GetSkill(c.Request.Context(), key)
 	if err != nil && errors.Is(err, sql.ErrNoRows) {
 		c.JSON(http.StatusNotFound, api.ErrorResponse("skill not found"))
 		return
@@ -274,11 +599,116 @@ func (h skillHandler) DeleteSkill(c *gin.Context) {
 		return
 	}
 
-	if err := h.skillQueue.PublishSkill(DeleteSkillAction, &key, nil); err != nil {
+	expectedVersion, ok := matchVersion(c, skill)
+	if !ok {
+		return
+	}
+
+	operationID, err := h.skillQueue.PublishSkill(c.Request.Context(), DeleteSkillAction, &key, expectedVersion, nil)
+	if err != nil {
 		log.Println("Error:", err)
 		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to delete skill"))
 		return
 	}
 
-	c.JSON(http.StatusOK, api.MessageResponse("deleting skill already in progress"))
+	h.accepted(c, http.StatusOK, operationID, key, "deleting skill already in progress")
+}
+
+// RestoreSkill brings a deleted skill back out of the trash. If-Match is
+// checked against the version the skill was deleted at.
+func (h skillHandler) RestoreSkill(c *gin.Context) {
+	key := c.Param("key")
+
+	skill, err := h.skillStorage.GetDeletedSkill(c.Request.Context(), key)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, api.ErrorResponse("deleted skill not found"))
+		return
+	}
+
+	if err != nil {
+		log.Println("Error:", err)
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to get skill"))
+		return
+	}
+
+	expectedVersion, ok := matchVersion(c, skill)
+	if !ok {
+		return
+	}
+
+	operationID, err := h.skillQueue.PublishSkill(c.Request.Context(), RestoreSkillAction, &key, expectedVersion, nil)
+	if err != nil {
+		log.Println("Error:", err)
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to restore skill"))
+		return
+	}
+
+	h.accepted(c, http.StatusOK, operationID, key, "restoring skill already in progress")
+}
+
+// accepted points the caller at the operation that tracks the queued message.
+// When the caller asked to wait, it responds with the outcome instead, and
+// falls back to 202 Accepted if the consumer has not finished in time.
+func (h skillHandler) accepted(c *gin.Context, status int, operationID string, key string, message string) {
+	if wait := waitDuration(c); wait > 0 && h.waiter != nil {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), wait)
+		defer cancel()
+
+		op, err := h.waiter.Wait(ctx, operationID)
+		if err == nil {
+			h.completed(c, status, op, key)
+			return
+		}
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			log.Println("Error:", err)
+		}
+		status = http.StatusAccepted
+	}
+
+	c.Header("Location", operation.Location(operationID))
+	c.JSON(status, api.MessageDataResponse(message, ResponseAccepted{
+		OperationID: operationID,
+		Status:      string(operation.PendingStatus),
+	}))
+}
+
+func (h skillHandler) completed(c *gin.Context, status int, op *operation.Operation, key string) {
+	c.Header("Location", operation.Location(op.ID))
+
+	if op.Status == operation.FailedStatus {
+		c.JSON(http.StatusUnprocessableEntity, api.ErrorResponse(op.Reason))
+		return
+	}
+
+	if op.Status == operation.ConflictStatus {
+		c.JSON(http.StatusPreconditionFailed, api.ErrorResponse(op.Reason))
+		return
+	}
+
+	if op.Status == operation.NotFoundStatus {
+		c.JSON(http.StatusNotFound, api.ErrorResponse(op.Reason))
+		return
+	}
+
+	if op.Action == string(DeleteSkillAction) {
+		c.JSON(status, api.MessageResponse("skill deleted"))
+		return
+	}
+
+	skill, err := h.skillStorage.GetSkill(c.Request.Context(), key)
+	if err != nil {
+		log.Println("Error:", err)
+		c.JSON(http.StatusInternalServerError, api.ErrorResponse("not be able to get skill"))
+		return
+	}
+
+	c.Header("ETag", etag(skill.Version))
+	c.JSON(status, api.SuccessResponse(ResponseSkill{
+		Key:         skill.Key,
+		Name:        skill.Name,
+		Description: skill.Description,
+		Logo:        skill.Logo,
+		Tags:        skill.Tags,
+	}))
 }
@@ -1,6 +1,7 @@
 package skill
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -9,8 +10,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"skill-api-kafka/api"
+	"skill-api-kafka/operation"
 	"strings"
 	"testing"
+	"time"
 )
 
 type testSkill struct {
@@ -68,7 +72,7 @@ func TestGetSkillHandler(t *testing.T) {
 			c, r := gin.CreateTestContext(res)
 			c.Request = httptest.NewRequest(http.MethodGet, tt.url, nil)
 
-			h := NewSkillHandler(tt.mockStorage, nil)
+			h := NewSkillHandler(tt.mockStorage, nil, nil)
 			r.GET("/skills/:key", h.GetSkill) // Call to a handler method
 			r.ServeHTTP(res, c.Request)
 
@@ -103,7 +107,7 @@ func TestGetSkillsHandler(t *testing.T) {
 			name:           "get skills success",
 			url:            "/skills",
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"status": "success", "data": [{"key": "python", "name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png", "tags": ["programming", "scripting", "web", "data science"]}, {"key": "go", "name": "Go", "description": "Go is an open source programming language that makes it easy to build simple, reliable, and efficient software.", "logo": "https://blog.golang.org/go-brand/Go-Logo/SVG/Go-Logo_Blue.svg", "tags": ["programming", "web", "cloud", "concurrency"]}]}`,
+			expectedBody:   `{"status": "success", "data": [{"key": "python", "name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png", "tags": ["programming", "scripting", "web", "data science"]}, {"key": "go", "name": "Go", "description": "Go is an open source programming language that makes it easy to build simple, reliable, and efficient software.", "logo": "https://blog.golang.org/go-brand/Go-Logo/SVG/Go-Logo_Blue.svg", "tags": ["programming", "web", "cloud", "concurrency"]}], "page": {"total": 2}}`,
 			mockStorage: &mockSkillStorage{
 				skills: []Skill{
 					{
@@ -127,11 +131,56 @@ func TestGetSkillsHandler(t *testing.T) {
 			name:           "empty skills success",
 			url:            "/skills",
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"status": "success", "data": []}`,
+			expectedBody:   `{"status": "success", "data": [], "page": {"total": 0}}`,
 			mockStorage: &mockSkillStorage{
 				skills: []Skill{},
 			},
 		},
+		{
+			name:           "page with more skills returns next cursor",
+			url:            "/skills?limit=1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "success", "data": [{"key": "go", "name": "Go", "description": "", "logo": "", "tags": ["programming"]}], "page": {"total": 1, "next_cursor": "eyJzIjoia2V5IiwidiI6ImdvIiwiayI6ImdvIn0"}}`,
+			mockStorage: &mockSkillStorage{
+				skills: []Skill{{Key: "go", Name: "Go", Tags: []string{"programming"}}},
+				next:   &Cursor{Sort: SortByKey, Value: "go", Key: "go"},
+			},
+		},
+		{
+			name:           "invalid limit",
+			url:            "/skills?limit=0",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status": "error", "message": "limit must be between 1 and 100"}`,
+			mockStorage:    &mockSkillStorage{},
+		},
+		{
+			name:           "invalid sort",
+			url:            "/skills?sort=logo",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status": "error", "message": "sort must be one of key, name or updated_at"}`,
+			mockStorage:    &mockSkillStorage{},
+		},
+		{
+			name:           "invalid order",
+			url:            "/skills?order=up",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status": "error", "message": "order must be asc or desc"}`,
+			mockStorage:    &mockSkillStorage{},
+		},
+		{
+			name:           "invalid tag match",
+			url:            "/skills?tags=go&tag_match=some",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status": "error", "message": "tag_match must be any or all"}`,
+			mockStorage:    &mockSkillStorage{},
+		},
+		{
+			name:           "cursor of another sort",
+			url:            "/skills?sort=name&cursor=eyJzIjoia2V5IiwidiI6ImdvIiwiayI6ImdvIn0",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status": "error", "message": "cursor is invalid for this sort"}`,
+			mockStorage:    &mockSkillStorage{},
+		},
 		{
 			name:           "database connection error",
 			url:            "/skills",
@@ -151,7 +200,7 @@ func TestGetSkillsHandler(t *testing.T) {
 			c, r := gin.CreateTestContext(res)
 			c.Request = httptest.NewRequest(http.MethodGet, tt.url, nil)
 
-			h := NewSkillHandler(tt.mockStorage, nil)
+			h := NewSkillHandler(tt.mockStorage, nil, nil)
 			r.GET("/skills", h.GetSkills) // Call to a handler method
 			r.ServeHTTP(res, c.Request)
 
@@ -180,58 +229,52 @@ func TestGetSkillsHandler(t *testing.T) {
 	}
 }
 
-func TestCreateSkillHandler(t *testing.T) {
+func TestSearchSkillsHandler(t *testing.T) {
 	tests := []testSkill{
 		{
-			name:           "create skill success",
-			url:            "/skills",
-			payload:        `{"key": "python", "name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png", "tags": ["programming", "scripting", "web", "data science"]}`,
-			expectedStatus: http.StatusCreated,
-			expectedBody:   `{"status": "success", "message": "creating skill already in progress"}`,
+			name:           "search success",
+			url:            "/skills/search?q=containers",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "success", "data": [{"key": "docker", "name": "Docker", "description": "Run apps in containers", "logo": "", "tags": ["devops"], "rank": 0.6, "snippet": "Docker Run apps in <mark>containers</mark> devops"}]}`,
+			mockStorage: &mockSkillStorage{
+				searchResults: []SearchResult{
+					{
+						Skill:   Skill{Key: "docker", Name: "Docker", Description: "Run apps in containers", Tags: []string{"devops"}},
+						Rank:    0.6,
+						Snippet: "Docker Run apps in <mark>containers</mark> devops",
+					},
+				},
+			},
+		},
+		{
+			name:           "no match",
+			url:            "/skills/search?q=cobol",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "success", "data": []}`,
 			mockStorage:    &mockSkillStorage{},
-			mockSkillQueue: &mockSkillQueue{},
 		},
 		{
-			name:           "invalid request",
-			url:            "/skills",
-			payload:        `{"key": "python", "name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png"}`,
+			name:           "missing query",
+			url:            "/skills/search?q=%20",
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"status": "error", "message": "invalid request"}`,
+			expectedBody:   `{"status": "error", "message": "q is required"}`,
 			mockStorage:    &mockSkillStorage{},
-			mockSkillQueue: &mockSkillQueue{},
 		},
 		{
-			name:           "skill already exists",
-			url:            "/skills",
-			payload:        `{"key": "python", "name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png", "tags": ["programming", "scripting", "web", "data science"]}`,
-			expectedStatus: http.StatusConflict,
-			expectedBody:   `{"status": "error", "message": "skill already exists"}`,
-			mockStorage: &mockSkillStorage{
-				skill: &Skill{
-					Key: "python",
-				},
-			},
-			mockSkillQueue: &mockSkillQueue{},
+			name:           "invalid limit",
+			url:            "/skills/search?q=go&limit=500",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status": "error", "message": "limit must be between 1 and 100"}`,
+			mockStorage:    &mockSkillStorage{},
 		},
 		{
 			name:           "database connection error",
-			url:            "/skills",
-			payload:        `{"key": "python", "name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png", "tags": ["programming", "scripting", "web", "data science"]}`,
+			url:            "/skills/search?q=go",
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"status": "error", "message": "not be able to get skill"}`,
+			expectedBody:   `{"status": "error", "message": "not be able to search skills"}`,
 			mockStorage: &mockSkillStorage{
 				errGet: sql.ErrConnDone,
 			},
-			mockSkillQueue: &mockSkillQueue{},
-		},
-		{
-			name:           "publish skill error",
-			url:            "/skills",
-			payload:        `{"key": "python", "name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png", "tags": ["programming", "scripting", "web", "data science"]}`,
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"status": "error", "message": "not be able to create skill"}`,
-			mockStorage:    &mockSkillStorage{},
-			mockSkillQueue: &mockSkillQueue{errPublish: errors.New("publish error")},
 		},
 	}
 
@@ -241,11 +284,11 @@ func TestCreateSkillHandler(t *testing.T) {
 
 			res := httptest.NewRecorder()
 			c, r := gin.CreateTestContext(res)
-			c.Request = httptest.NewRequest(http.MethodPost, tt.url, nil)
-			c.Request.Body = ioutil.NopCloser(strings.NewReader(tt.payload))
+			c.Request = httptest.NewRequest(http.MethodGet, tt.url, nil)
 
-			h := NewSkillHandler(tt.mockStorage, tt.mockSkillQueue)
-			r.POST("/skills", h.CreateSkill) // Call to a handler method
+			h := NewSkillHandler(tt.mockStorage, nil, nil)
+			r.GET("/skills/:key", h.GetSkill)
+			r.GET("/skills/search", h.SearchSkills)
 			r.ServeHTTP(res, c.Request)
 
 			// Assert response
@@ -273,64 +316,32 @@ func TestCreateSkillHandler(t *testing.T) {
 	}
 }
 
-func TestUpdateSkillHandler(t *testing.T) {
+func TestGetTagsHandler(t *testing.T) {
 	tests := []testSkill{
 		{
-			name:           "update skill success",
-			url:            "/skills/python",
-			payload:        `{"name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png", "tags": ["programming", "scripting", "web", "data science"]}`,
+			name:           "get tags success",
+			url:            "/tags",
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"status": "success", "message": "updating skill already in progress"}`,
+			expectedBody:   `{"status": "success", "data": [{"tag": "programming", "count": 2}, {"tag": "cloud", "count": 1}]}`,
 			mockStorage: &mockSkillStorage{
-				skill: &Skill{
-					Key: "python",
-				},
+				tags: []TagCount{{Tag: "programming", Count: 2}, {Tag: "cloud", Count: 1}},
 			},
-			mockSkillQueue: &mockSkillQueue{},
 		},
 		{
-			name:           "invalid request",
-			url:            "/skills/python",
-			payload:        `{"name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png"}`,
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"status": "error", "message": "invalid request"}`,
+			name:           "empty tags success",
+			url:            "/tags",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "success", "data": []}`,
 			mockStorage:    &mockSkillStorage{},
-			mockSkillQueue: &mockSkillQueue{},
-		},
-		{
-			name:           "not exist skill",
-			url:            "/skills/python3",
-			payload:        `{"name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png", "tags": ["programming", "scripting", "web", "data science"]}`,
-			expectedStatus: http.StatusNotFound,
-			expectedBody:   `{"status": "error", "message": "skill not found"}`,
-			mockStorage: &mockSkillStorage{
-				errGet: sql.ErrNoRows,
-			},
-			mockSkillQueue: &mockSkillQueue{},
 		},
 		{
 			name:           "database connection error",
-			url:            "/skills/python4",
-			payload:        `{"name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png", "tags": ["programming", "scripting", "web", "data science"]}`,
+			url:            "/tags",
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"status": "error", "message": "not be able to get skill"}`,
+			expectedBody:   `{"status": "error", "message": "not be able to get tags"}`,
 			mockStorage: &mockSkillStorage{
 				errGet: sql.ErrConnDone,
 			},
-			mockSkillQueue: &mockSkillQueue{},
-		},
-		{
-			name:           "publish skill error",
-			url:            "/skills/python5",
-			payload:        `{"name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png", "tags": ["programming", "scripting", "web", "data science"]}`,
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"status": "error", "message": "not be able to update skill"}`,
-			mockStorage: &mockSkillStorage{
-				skill: &Skill{
-					Key: "python5",
-				},
-			},
-			mockSkillQueue: &mockSkillQueue{errPublish: errors.New("publish error")},
 		},
 	}
 
@@ -340,11 +351,10 @@ func TestUpdateSkillHandler(t *testing.T) {
 
 			res := httptest.NewRecorder()
 			c, r := gin.CreateTestContext(res)
-			c.Request = httptest.NewRequest(http.MethodPut, tt.url, nil)
-			c.Request.Body = ioutil.NopCloser(strings.NewReader(tt.payload))
+			c.Request = httptest.NewRequest(http.MethodGet, tt.url, nil)
 
-			h := NewSkillHandler(tt.mockStorage, tt.mockSkillQueue)
-			r.PUT("/skills/:key", h.UpdateSkill) // Call to a handler method
+			h := NewSkillHandler(tt.mockStorage, nil, nil)
+			r.GET("/tags", h.GetTags)
 			r.ServeHTTP(res, c.Request)
 
 			// Assert response
@@ -372,51 +382,32 @@ func TestUpdateSkillHandler(t *testing.T) {
 	}
 }
 
-func TestDeleteSkillHandler(t *testing.T) {
+func TestGetTagSkillsHandler(t *testing.T) {
 	tests := []testSkill{
 		{
-			name:           "delete skill success",
-			url:            "/skills/python",
+			name:           "get tag skills success",
+			url:            "/tags/cloud/skills",
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"status": "success", "message": "deleting skill already in progress"}`,
+			expectedBody:   `{"status": "success", "data": [{"key": "go", "name": "Go", "description": "", "logo": "", "tags": ["programming", "cloud"]}], "page": {"total": 1}}`,
 			mockStorage: &mockSkillStorage{
-				skill: &Skill{
-					Key: "python",
-				},
+				skills: []Skill{{Key: "go", Name: "Go", Tags: []string{"programming", "cloud"}}},
 			},
-			mockSkillQueue: &mockSkillQueue{},
 		},
 		{
-			name:           "not exist skill",
-			url:            "/skills/python3",
-			expectedStatus: http.StatusNotFound,
-			expectedBody:   `{"status": "error", "message": "skill not found"}`,
-			mockStorage: &mockSkillStorage{
-				errGet: sql.ErrNoRows,
-			},
-			mockSkillQueue: &mockSkillQueue{},
+			name:           "invalid sort",
+			url:            "/tags/cloud/skills?sort=logo",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status": "error", "message": "sort must be one of key, name or updated_at"}`,
+			mockStorage:    &mockSkillStorage{},
 		},
 		{
 			name:           "database connection error",
-			url:            "/skills/python4",
+			url:            "/tags/cloud/skills",
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"status": "error", "message": "not be able to get skill"}`,
+			expectedBody:   `{"status": "error", "message": "not be able to get skills"}`,
 			mockStorage: &mockSkillStorage{
 				errGet: sql.ErrConnDone,
 			},
-			mockSkillQueue: &mockSkillQueue{},
-		},
-		{
-			name:           "publish skill error",
-			url:            "/skills/python5",
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"status": "error", "message": "not be able to delete skill"}`,
-			mockStorage: &mockSkillStorage{
-				skill: &Skill{
-					Key: "python5",
-				},
-			},
-			mockSkillQueue: &mockSkillQueue{errPublish: errors.New("publish error")},
 		},
 	}
 
@@ -426,10 +417,10 @@ func TestDeleteSkillHandler(t *testing.T) {
 
 			res := httptest.NewRecorder()
 			c, r := gin.CreateTestContext(res)
-			c.Request = httptest.NewRequest(http.MethodDelete, tt.url, nil)
+			c.Request = httptest.NewRequest(http.MethodGet, tt.url, nil)
 
-			h := NewSkillHandler(tt.mockStorage, tt.mockSkillQueue)
-			r.DELETE("/skills/:key", h.DeleteSkill) // Call to a handler method
+			h := NewSkillHandler(tt.mockStorage, nil, nil)
+			r.GET("/tags/:tag/skills", h.GetTagSkills)
 			r.ServeHTTP(res, c.Request)
 
 			// Assert response
@@ -455,66 +446,85 @@ func TestDeleteSkillHandler(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("should list skills carrying only the tag in the path", func(t *testing.T) {
+		// Arrange
+		gin.SetMode(gin.TestMode)
+		res := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(res)
+		storage := &mockSkillStorage{}
+		h := NewSkillHandler(storage, nil, nil)
+		r.GET("/tags/:tag/skills", h.GetTagSkills)
+
+		// Act
+		r.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/tags/data%20science/skills?tags=go&tag_match=all", nil))
+
+		// Assert
+		if !reflect.DeepEqual(storage.listQuery.Tags, []string{"data science"}) || storage.listQuery.AllTags || storage.listQuery.Deleted {
+			t.Errorf("expected live skills tagged data science, got %+v", storage.listQuery)
+		}
+	})
 }
 
-func TestUpdateSkillNameHandler(t *testing.T) {
+func TestGetSkillHistoryHandler(t *testing.T) {
+	occurredAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	history := []HistoryEntry{
+		{ID: 3, Action: "update_description", Before: []byte(`{"key": "go", "description": "Golang"}`), After: []byte(`{"key": "go", "description": "The Go language"}`), Actor: "alice", MessageID: "msg-3", OccurredAt: occurredAt},
+		{ID: 2, Action: "update_name", Before: []byte(`{"key": "go", "name": "go"}`), After: []byte(`{"key": "go", "name": "Go"}`), Actor: "bob", MessageID: "msg-2", OccurredAt: occurredAt},
+		{ID: 1, Action: "create", After: []byte(`{"key": "go", "name": "go"}`), Actor: "bob", MessageID: "msg-1", OccurredAt: occurredAt},
+	}
+
 	tests := []testSkill{
 		{
-			name:           "update skill name success",
-			url:            "/skills/python/name",
-			payload:        `{"name": "Python"}`,
+			name:           "get history success",
+			url:            "/skills/go/history",
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"status": "success", "message": "updating skill name already in progress"}`,
-			mockStorage: &mockSkillStorage{
-				skill: &Skill{
-					Key: "python",
-				},
-			},
-			mockSkillQueue: &mockSkillQueue{},
+			expectedBody:   `{"status": "success", "data": {"entries": [{"id": 3, "action": "update_description", "before": {"key": "go", "description": "Golang"}, "after": {"key": "go", "description": "The Go language"}, "actor": "alice", "message_id": "msg-3", "occurred_at": "2024-01-02T03:04:05Z"}, {"id": 2, "action": "update_name", "before": {"key": "go", "name": "go"}, "after": {"key": "go", "name": "Go"}, "actor": "bob", "message_id": "msg-2", "occurred_at": "2024-01-02T03:04:05Z"}, {"id": 1, "action": "create", "before": null, "after": {"key": "go", "name": "go"}, "actor": "bob", "message_id": "msg-1", "occurred_at": "2024-01-02T03:04:05Z"}]}}`,
+			mockStorage:    &mockSkillStorage{history: history},
 		},
 		{
-			name:           "invalid request",
-			url:            "/skills/python/name",
-			payload:        `{"name": ""}`,
+			name:           "first page returns cursor of next page",
+			url:            "/skills/go/history?limit=1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "success", "data": {"entries": [{"id": 3, "action": "update_description", "before": {"key": "go", "description": "Golang"}, "after": {"key": "go", "description": "The Go language"}, "actor": "alice", "message_id": "msg-3", "occurred_at": "2024-01-02T03:04:05Z"}], "next_before": 3}}`,
+			mockStorage:    &mockSkillStorage{history: history},
+		},
+		{
+			name:           "last page has no cursor",
+			url:            "/skills/go/history?limit=1&before=2",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "success", "data": {"entries": [{"id": 1, "action": "create", "before": null, "after": {"key": "go", "name": "go"}, "actor": "bob", "message_id": "msg-1", "occurred_at": "2024-01-02T03:04:05Z"}]}}`,
+			mockStorage:    &mockSkillStorage{history: history},
+		},
+		{
+			name:           "empty history success",
+			url:            "/skills/go/history",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "success", "data": {"entries": []}}`,
+			mockStorage:    &mockSkillStorage{},
+		},
+		{
+			name:           "invalid limit",
+			url:            "/skills/go/history?limit=101",
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"status": "error", "message": "invalid request"}`,
+			expectedBody:   `{"status": "error", "message": "limit must be between 1 and 100 and before a positive id"}`,
 			mockStorage:    &mockSkillStorage{},
-			mockSkillQueue: &mockSkillQueue{},
 		},
 		{
-			name:           "not exist skill",
-			url:            "/skills/python3/name",
-			payload:        `{"name": "Python"}`,
-			expectedStatus: http.StatusNotFound,
-			expectedBody:   `{"status": "error", "message": "skill not found"}`,
-			mockStorage: &mockSkillStorage{
-				errGet: sql.ErrNoRows,
-			},
-			mockSkillQueue: &mockSkillQueue{},
+			name:           "invalid before",
+			url:            "/skills/go/history?before=abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status": "error", "message": "limit must be between 1 and 100 and before a positive id"}`,
+			mockStorage:    &mockSkillStorage{},
 		},
 		{
 			name:           "database connection error",
-			url:            "/skills/python4/name",
-			payload:        `{"name": "Python"}`,
+			url:            "/skills/go/history",
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"status": "error", "message": "not be able to get skill"}`,
+			expectedBody:   `{"status": "error", "message": "not be able to get skill history"}`,
 			mockStorage: &mockSkillStorage{
 				errGet: sql.ErrConnDone,
 			},
-			mockSkillQueue: &mockSkillQueue{},
-		},
-		{
-			name:           "publish skill error",
-			url:            "/skills/python5/name",
-			payload:        `{"name": "Python"}`,
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"status": "error", "message": "not be able to update skill name"}`,
-			mockStorage: &mockSkillStorage{
-				skill: &Skill{
-					Key: "python5",
-				},
-			},
-			mockSkillQueue: &mockSkillQueue{errPublish: errors.New("publish error")},
 		},
 	}
 
@@ -524,11 +534,10 @@ func TestUpdateSkillNameHandler(t *testing.T) {
 
 			res := httptest.NewRecorder()
 			c, r := gin.CreateTestContext(res)
-			c.Request = httptest.NewRequest(http.MethodPut, tt.url, nil)
-			c.Request.Body = ioutil.NopCloser(strings.NewReader(tt.payload))
+			c.Request = httptest.NewRequest(http.MethodGet, tt.url, nil)
 
-			h := NewSkillHandler(tt.mockStorage, tt.mockSkillQueue)
-			r.PUT("/skills/:key/name", h.UpdateName) // Call to a handler method
+			h := NewSkillHandler(tt.mockStorage, nil, nil)
+			r.GET("/skills/:key/history", h.GetSkillHistory)
 			r.ServeHTTP(res, c.Request)
 
 			// Assert response
@@ -556,63 +565,155 @@ func TestUpdateSkillNameHandler(t *testing.T) {
 	}
 }
 
-func TestUpdateSkillDescriptionHandler(t *testing.T) {
-	tests := []testSkill{
+func TestGetDeletedSkillsHandler(t *testing.T) {
+	deletedAt := time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		url            string
+		admin          bool
+		expectedStatus int
+		expectedBody   string
+		mockStorage    *mockSkillStorage
+	}{
 		{
-			name:           "update skill description success",
-			url:            "/skills/python/description",
-			payload:        `{"description": "Python is a programming language that lets you work quickly and integrate systems more effectively."}`,
+			name:           "get deleted skills success",
+			url:            "/skills?deleted=true",
+			admin:          true,
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"status": "success", "message": "updating skill description already in progress"}`,
+			expectedBody:   `{"status": "success", "data": [{"key": "python", "name": "Python", "description": "", "logo": "", "tags": ["programming"], "version": 3, "deleted_at": "2024-07-01T10:00:00Z"}], "page": {"total": 1}}`,
 			mockStorage: &mockSkillStorage{
-				skill: &Skill{
-					Key: "python",
-				},
+				deletedSkills: []Skill{{Key: "python", Name: "Python", Tags: []string{"programming"}, Version: 3, DeletedAt: &deletedAt}},
 			},
-			mockSkillQueue: &mockSkillQueue{},
 		},
 		{
-			name:           "invalid request",
-			url:            "/skills/python/description",
-			payload:        `{"description": ""}`,
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"status": "error", "message": "invalid request"}`,
+			name:           "only admins can list deleted skills",
+			url:            "/skills?deleted=true",
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"status": "error", "message": "only admins can list deleted skills"}`,
 			mockStorage:    &mockSkillStorage{},
-			mockSkillQueue: &mockSkillQueue{},
 		},
 		{
-			name:           "not exist skill",
-			url:            "/skills/python3/description",
-			payload:        `{"description": "Python is a programming language that lets you work quickly and integrate systems more effectively."}`,
-			expectedStatus: http.StatusNotFound,
-			expectedBody:   `{"status": "error", "message": "skill not found"}`,
-			mockStorage: &mockSkillStorage{
-				errGet: sql.ErrNoRows,
-			},
-			mockSkillQueue: &mockSkillQueue{},
+			name:           "invalid deleted",
+			url:            "/skills?deleted=yes",
+			admin:          true,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status": "error", "message": "deleted must be true or false"}`,
+			mockStorage:    &mockSkillStorage{},
 		},
 		{
 			name:           "database connection error",
-			url:            "/skills/python4/description",
-			payload:        `{"description": "Python is a programming language that lets you work quickly and integrate systems more effectively."}`,
+			url:            "/skills?deleted=true",
+			admin:          true,
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"status": "error", "message": "not be able to get skill"}`,
+			expectedBody:   `{"status": "error", "message": "not be able to get deleted skills"}`,
 			mockStorage: &mockSkillStorage{
 				errGet: sql.ErrConnDone,
 			},
-			mockSkillQueue: &mockSkillQueue{},
 		},
-		{
-			name:           "publish skill error",
-			url:            "/skills/python5/description",
-			payload:        `{"description": "Python is a programming language that lets you work quickly and integrate systems more effectively."}`,
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"status": "error", "message": "not be able to update skill description"}`,
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			res := httptest.NewRecorder()
+			c, r := gin.CreateTestContext(res)
+			c.Request = httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.admin {
+				c.Request = c.Request.WithContext(api.WithAdmin(c.Request.Context()))
+			}
+
+			h := NewSkillHandler(tt.mockStorage, nil, nil)
+			r.GET("/skills", h.GetSkills)
+			r.ServeHTTP(res, c.Request)
+
+			// Assert response
+			if status := res.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			// Parse and compare JSON
+			var actual, expectedJSON map[string]interface{}
+			if err := json.Unmarshal(res.Body.Bytes(), &actual); err != nil {
+				t.Fatalf("could not unmarshal response body: %v", err)
+			}
+
+			if err := json.Unmarshal([]byte(tt.expectedBody), &expectedJSON); err != nil {
+				t.Fatalf("could not unmarshal expected JSON: %v", err)
+			}
+
+			// Assert response body
+			if !reflect.DeepEqual(expectedJSON, actual) {
+				t.Errorf("handler returned unexpected body: got %v want %v", actual, expectedJSON)
+			}
+		})
+	}
+}
+
+func TestCreateSkillHandler(t *testing.T) {
+	tests := []testSkill{
+		{
+			name:           "create skill success",
+			url:            "/skills",
+			payload:        `{"key": "python", "name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png", "tags": ["programming", "scripting", "web", "data science"]}`,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"status": "success", "message": "creating skill already in progress", "data": {"operation_id": "op-1", "status": "pending"}}`,
+			mockStorage:    &mockSkillStorage{},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "invalid request",
+			url:            "/skills",
+			payload:        `{"key": "python", "name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status": "error", "message": "invalid request"}`,
+			mockStorage:    &mockSkillStorage{},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "skill already exists",
+			url:            "/skills",
+			payload:        `{"key": "python", "name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png", "tags": ["programming", "scripting", "web", "data science"]}`,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status": "error", "message": "skill already exists"}`,
 			mockStorage: &mockSkillStorage{
 				skill: &Skill{
-					Key: "python5",
+					Key: "python",
+				},
+			},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "database connection error",
+			url:            "/skills",
+			payload:        `{"key": "python", "name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png", "tags": ["programming", "scripting", "web", "data science"]}`,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"status": "error", "message": "not be able to get skill"}`,
+			mockStorage: &mockSkillStorage{
+				errGet: sql.ErrConnDone,
+			},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "skill is deleted",
+			url:            "/skills",
+			payload:        `{"key": "python", "name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png", "tags": ["programming", "scripting", "web", "data science"]}`,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status": "error", "message": "skill is deleted, restore it instead"}`,
+			mockStorage: &mockSkillStorage{
+				deletedSkill: &Skill{
+					Key: "python",
 				},
 			},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "publish skill error",
+			url:            "/skills",
+			payload:        `{"key": "python", "name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png", "tags": ["programming", "scripting", "web", "data science"]}`,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"status": "error", "message": "not be able to create skill"}`,
+			mockStorage:    &mockSkillStorage{},
 			mockSkillQueue: &mockSkillQueue{errPublish: errors.New("publish error")},
 		},
 	}
@@ -623,11 +724,11 @@ func TestUpdateSkillDescriptionHandler(t *testing.T) {
 
 			res := httptest.NewRecorder()
 			c, r := gin.CreateTestContext(res)
-			c.Request = httptest.NewRequest(http.MethodPut, tt.url, nil)
+			c.Request = httptest.NewRequest(http.MethodPost, tt.url, nil)
 			c.Request.Body = ioutil.NopCloser(strings.NewReader(tt.payload))
 
-			h := NewSkillHandler(tt.mockStorage, tt.mockSkillQueue)
-			r.PUT("/skills/:key/description", h.UpdateDescription) // Call to a handler method
+			h := NewSkillHandler(tt.mockStorage, tt.mockSkillQueue, nil)
+			r.POST("/skills", h.CreateSkill) // Call to a handler method
 			r.ServeHTTP(res, c.Request)
 
 			// Assert response
@@ -655,14 +756,14 @@ func TestUpdateSkillDescriptionHandler(t *testing.T) {
 	}
 }
 
-func TestUpdateSkillLogoHandler(t *testing.T) {
+func TestUpdateSkillHandler(t *testing.T) {
 	tests := []testSkill{
 		{
-			name:           "update skill logo success",
-			url:            "/skills/python/logo",
-			payload:        `{"logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png"}`,
+			name:           "update skill success",
+			url:            "/skills/python",
+			payload:        `{"name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png", "tags": ["programming", "scripting", "web", "data science"]}`,
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"status": "success", "message": "updating skill logo already in progress"}`,
+			expectedBody:   `{"status": "success", "message": "updating skill already in progress", "data": {"operation_id": "op-1", "status": "pending"}}`,
 			mockStorage: &mockSkillStorage{
 				skill: &Skill{
 					Key: "python",
@@ -672,8 +773,8 @@ func TestUpdateSkillLogoHandler(t *testing.T) {
 		},
 		{
 			name:           "invalid request",
-			url:            "/skills/python/logo",
-			payload:        `{"logo": ""}`,
+			url:            "/skills/python",
+			payload:        `{"name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png"}`,
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   `{"status": "error", "message": "invalid request"}`,
 			mockStorage:    &mockSkillStorage{},
@@ -681,8 +782,8 @@ func TestUpdateSkillLogoHandler(t *testing.T) {
 		},
 		{
 			name:           "not exist skill",
-			url:            "/skills/python3/logo",
-			payload:        `{"logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png"}`,
+			url:            "/skills/python3",
+			payload:        `{"name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png", "tags": ["programming", "scripting", "web", "data science"]}`,
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   `{"status": "error", "message": "skill not found"}`,
 			mockStorage: &mockSkillStorage{
@@ -692,8 +793,8 @@ func TestUpdateSkillLogoHandler(t *testing.T) {
 		},
 		{
 			name:           "database connection error",
-			url:            "/skills/python4/logo",
-			payload:        `{"logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png"}`,
+			url:            "/skills/python4",
+			payload:        `{"name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png", "tags": ["programming", "scripting", "web", "data science"]}`,
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   `{"status": "error", "message": "not be able to get skill"}`,
 			mockStorage: &mockSkillStorage{
@@ -703,10 +804,10 @@ func TestUpdateSkillLogoHandler(t *testing.T) {
 		},
 		{
 			name:           "publish skill error",
-			url:            "/skills/python5/logo",
-			payload:        `{"logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png"}`,
+			url:            "/skills/python5",
+			payload:        `{"name": "Python", "description": "Python is a programming language that lets you work quickly and integrate systems more effectively.", "logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png", "tags": ["programming", "scripting", "web", "data science"]}`,
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"status": "error", "message": "not be able to update skill logo"}`,
+			expectedBody:   `{"status": "error", "message": "not be able to update skill"}`,
 			mockStorage: &mockSkillStorage{
 				skill: &Skill{
 					Key: "python5",
@@ -723,10 +824,11 @@ func TestUpdateSkillLogoHandler(t *testing.T) {
 			res := httptest.NewRecorder()
 			c, r := gin.CreateTestContext(res)
 			c.Request = httptest.NewRequest(http.MethodPut, tt.url, nil)
+			c.Request.Header.Set("If-Match", "*")
 			c.Request.Body = ioutil.NopCloser(strings.NewReader(tt.payload))
 
-			h := NewSkillHandler(tt.mockStorage, tt.mockSkillQueue)
-			r.PUT("/skills/:key/logo", h.UpdateLogo) // Call to a handler method
+			h := NewSkillHandler(tt.mockStorage, tt.mockSkillQueue, nil)
+			r.PUT("/skills/:key", h.UpdateSkill) // Call to a handler method
 			r.ServeHTTP(res, c.Request)
 
 			// Assert response
@@ -754,14 +856,100 @@ func TestUpdateSkillLogoHandler(t *testing.T) {
 	}
 }
 
-func TestUpdateSkillTagsHandler(t *testing.T) {
+func TestDeleteSkillHandler(t *testing.T) {
 	tests := []testSkill{
 		{
-			name:           "update skill tags success",
-			url:            "/skills/python/tags",
-			payload:        `{"tags": ["programming", "scripting", "web", "data science"]}`,
+			name:           "delete skill success",
+			url:            "/skills/python",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "success", "message": "deleting skill already in progress", "data": {"operation_id": "op-1", "status": "pending"}}`,
+			mockStorage: &mockSkillStorage{
+				skill: &Skill{
+					Key: "python",
+				},
+			},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "not exist skill",
+			url:            "/skills/python3",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status": "error", "message": "skill not found"}`,
+			mockStorage: &mockSkillStorage{
+				errGet: sql.ErrNoRows,
+			},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "database connection error",
+			url:            "/skills/python4",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"status": "error", "message": "not be able to get skill"}`,
+			mockStorage: &mockSkillStorage{
+				errGet: sql.ErrConnDone,
+			},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "publish skill error",
+			url:            "/skills/python5",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"status": "error", "message": "not be able to delete skill"}`,
+			mockStorage: &mockSkillStorage{
+				skill: &Skill{
+					Key: "python5",
+				},
+			},
+			mockSkillQueue: &mockSkillQueue{errPublish: errors.New("publish error")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			res := httptest.NewRecorder()
+			c, r := gin.CreateTestContext(res)
+			c.Request = httptest.NewRequest(http.MethodDelete, tt.url, nil)
+			c.Request.Header.Set("If-Match", "*")
+
+			h := NewSkillHandler(tt.mockStorage, tt.mockSkillQueue, nil)
+			r.DELETE("/skills/:key", h.DeleteSkill) // Call to a handler method
+			r.ServeHTTP(res, c.Request)
+
+			// Assert response
+			if status := res.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			// Parse and compare JSON
+			var actual, expectedJSON map[string]interface{}
+			err := json.Unmarshal(res.Body.Bytes(), &actual)
+			if err != nil {
+				t.Fatalf("could not unmarshal response body: %v", err)
+			}
+
+			err = json.Unmarshal([]byte(tt.expectedBody), &expectedJSON)
+			if err != nil {
+				t.Fatalf("could not unmarshal expected JSON: %v", err)
+			}
+
+			// Assert response body
+			if !reflect.DeepEqual(expectedJSON, actual) {
+				t.Errorf("handler returned unexpected body: got %v want %v", actual, expectedJSON)
+			}
+		})
+	}
+}
+
+func TestUpdateSkillNameHandler(t *testing.T) {
+	tests := []testSkill{
+		{
+			name:           "update skill name success",
+			url:            "/skills/python/name",
+			payload:        `{"name": "Python"}`,
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"status": "success", "message": "updating skill tags already in progress"}`,
+			expectedBody:   `{"status": "success", "message": "updating skill name already in progress", "data": {"operation_id": "op-1", "status": "pending"}}`,
 			mockStorage: &mockSkillStorage{
 				skill: &Skill{
 					Key: "python",
@@ -771,8 +959,8 @@ func TestUpdateSkillTagsHandler(t *testing.T) {
 		},
 		{
 			name:           "invalid request",
-			url:            "/skills/python/tags",
-			payload:        `{"tags": ""}`,
+			url:            "/skills/python/name",
+			payload:        `{"name": ""}`,
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   `{"status": "error", "message": "invalid request"}`,
 			mockStorage:    &mockSkillStorage{},
@@ -780,8 +968,8 @@ func TestUpdateSkillTagsHandler(t *testing.T) {
 		},
 		{
 			name:           "not exist skill",
-			url:            "/skills/python3/tags",
-			payload:        `{"tags": ["programming", "scripting", "web", "data science"]}`,
+			url:            "/skills/python3/name",
+			payload:        `{"name": "Python"}`,
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   `{"status": "error", "message": "skill not found"}`,
 			mockStorage: &mockSkillStorage{
@@ -791,8 +979,8 @@ func TestUpdateSkillTagsHandler(t *testing.T) {
 		},
 		{
 			name:           "database connection error",
-			url:            "/skills/python4/tags",
-			payload:        `{"tags": ["programming", "scripting", "web", "data science"]}`,
+			url:            "/skills/python4/name",
+			payload:        `{"name": "Python"}`,
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   `{"status": "error", "message": "not be able to get skill"}`,
 			mockStorage: &mockSkillStorage{
@@ -802,10 +990,10 @@ func TestUpdateSkillTagsHandler(t *testing.T) {
 		},
 		{
 			name:           "publish skill error",
-			url:            "/skills/python5/tags",
-			payload:        `{"tags": ["programming", "scripting", "web", "data science"]}`,
+			url:            "/skills/python5/name",
+			payload:        `{"name": "Python"}`,
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"status": "error", "message": "not be able to update skill tags"}`,
+			expectedBody:   `{"status": "error", "message": "not be able to update skill name"}`,
 			mockStorage: &mockSkillStorage{
 				skill: &Skill{
 					Key: "python5",
@@ -822,10 +1010,11 @@ func TestUpdateSkillTagsHandler(t *testing.T) {
 			res := httptest.NewRecorder()
 			c, r := gin.CreateTestContext(res)
 			c.Request = httptest.NewRequest(http.MethodPut, tt.url, nil)
+			c.Request.Header.Set("If-Match", "*")
 			c.Request.Body = ioutil.NopCloser(strings.NewReader(tt.payload))
 
-			h := NewSkillHandler(tt.mockStorage, tt.mockSkillQueue)
-			r.PUT("/skills/:key/tags", h.UpdateTags) // Call to a handler method
+			h := NewSkillHandler(tt.mockStorage, tt.mockSkillQueue, nil)
+			r.PUT("/skills/:key/name", h.UpdateName) // Call to a handler method
 			r.ServeHTTP(res, c.Request)
 
 			// Assert response
@@ -852,3 +1041,895 @@ func TestUpdateSkillTagsHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateSkillDescriptionHandler(t *testing.T) {
+	tests := []testSkill{
+		{
+			name:           "update skill description success",
+			url:            "/skills/python/description",
+			payload:        `{"description": "Python is a programming language that lets you work quickly and integrate systems more effectively."}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "success", "message": "updating skill description already in progress", "data": {"operation_id": "op-1", "status": "pending"}}`,
+			mockStorage: &mockSkillStorage{
+				skill: &Skill{
+					Key: "python",
+				},
+			},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "invalid request",
+			url:            "/skills/python/description",
+			payload:        `{"description": ""}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status": "error", "message": "invalid request"}`,
+			mockStorage:    &mockSkillStorage{},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "not exist skill",
+			url:            "/skills/python3/description",
+			payload:        `{"description": "Python is a programming language that lets you work quickly and integrate systems more effectively."}`,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status": "error", "message": "skill not found"}`,
+			mockStorage: &mockSkillStorage{
+				errGet: sql.ErrNoRows,
+			},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "database connection error",
+			url:            "/skills/python4/description",
+			payload:        `{"description": "Python is a programming language that lets you work quickly and integrate systems more effectively."}`,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"status": "error", "message": "not be able to get skill"}`,
+			mockStorage: &mockSkillStorage{
+				errGet: sql.ErrConnDone,
+			},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "publish skill error",
+			url:            "/skills/python5/description",
+			payload:        `{"description": "Python is a programming language that lets you work quickly and integrate systems more effectively."}`,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"status": "error", "message": "not be able to update skill description"}`,
+			mockStorage: &mockSkillStorage{
+				skill: &Skill{
+					Key: "python5",
+				},
+			},
+			mockSkillQueue: &mockSkillQueue{errPublish: errors.New("publish error")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			res := httptest.NewRecorder()
+			c, r := gin.CreateTestContext(res)
+			c.Request = httptest.NewRequest(http.MethodPut, tt.url, nil)
+			c.Request.Header.Set("If-Match", "*")
+			c.Request.Body = ioutil.NopCloser(strings.NewReader(tt.payload))
+
+			h := NewSkillHandler(tt.mockStorage, tt.mockSkillQueue, nil)
+			r.PUT("/skills/:key/description", h.UpdateDescription) // Call to a handler method
+			r.ServeHTTP(res, c.Request)
+
+			// Assert response
+			if status := res.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			// Parse and compare JSON
+			var actual, expectedJSON map[string]interface{}
+			err := json.Unmarshal(res.Body.Bytes(), &actual)
+			if err != nil {
+				t.Fatalf("could not unmarshal response body: %v", err)
+			}
+
+			err = json.Unmarshal([]byte(tt.expectedBody), &expectedJSON)
+			if err != nil {
+				t.Fatalf("could not unmarshal expected JSON: %v", err)
+			}
+
+			// Assert response body
+			if !reflect.DeepEqual(expectedJSON, actual) {
+				t.Errorf("handler returned unexpected body: got %v want %v", actual, expectedJSON)
+			}
+		})
+	}
+}
+
+func TestUpdateSkillLogoHandler(t *testing.T) {
+	tests := []testSkill{
+		{
+			name:           "update skill logo success",
+			url:            "/skills/python/logo",
+			payload:        `{"logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "success", "message": "updating skill logo already in progress", "data": {"operation_id": "op-1", "status": "pending"}}`,
+			mockStorage: &mockSkillStorage{
+				skill: &Skill{
+					Key: "python",
+				},
+			},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "invalid request",
+			url:            "/skills/python/logo",
+			payload:        `{"logo": ""}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status": "error", "message": "invalid request"}`,
+			mockStorage:    &mockSkillStorage{},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "not exist skill",
+			url:            "/skills/python3/logo",
+			payload:        `{"logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png"}`,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status": "error", "message": "skill not found"}`,
+			mockStorage: &mockSkillStorage{
+				errGet: sql.ErrNoRows,
+			},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "database connection error",
+			url:            "/skills/python4/logo",
+			payload:        `{"logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png"}`,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"status": "error", "message": "not be able to get skill"}`,
+			mockStorage: &mockSkillStorage{
+				errGet: sql.ErrConnDone,
+			},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "publish skill error",
+			url:            "/skills/python5/logo",
+			payload:        `{"logo": "https://upload.wikimedia.org/wikipedia/commons/thumb/c/c3/Python-logo-notext.svg/1200px-Python-logo-notext.svg.png"}`,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"status": "error", "message": "not be able to update skill logo"}`,
+			mockStorage: &mockSkillStorage{
+				skill: &Skill{
+					Key: "python5",
+				},
+			},
+			mockSkillQueue: &mockSkillQueue{errPublish: errors.New("publish error")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			res := httptest.NewRecorder()
+			c, r := gin.CreateTestContext(res)
+			c.Request = httptest.NewRequest(http.MethodPut, tt.url, nil)
+			c.Request.Header.Set("If-Match", "*")
+			c.Request.Body = ioutil.NopCloser(strings.NewReader(tt.payload))
+
+			h := NewSkillHandler(tt.mockStorage, tt.mockSkillQueue, nil)
+			r.PUT("/skills/:key/logo", h.UpdateLogo) // Call to a handler method
+			r.ServeHTTP(res, c.Request)
+
+			// Assert response
+			if status := res.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			// Parse and compare JSON
+			var actual, expectedJSON map[string]interface{}
+			err := json.Unmarshal(res.Body.Bytes(), &actual)
+			if err != nil {
+				t.Fatalf("could not unmarshal response body: %v", err)
+			}
+
+			err = json.Unmarshal([]byte(tt.expectedBody), &expectedJSON)
+			if err != nil {
+				t.Fatalf("could not unmarshal expected JSON: %v", err)
+			}
+
+			// Assert response body
+			if !reflect.DeepEqual(expectedJSON, actual) {
+				t.Errorf("handler returned unexpected body: got %v want %v", actual, expectedJSON)
+			}
+		})
+	}
+}
+
+func TestUpdateSkillTagsHandler(t *testing.T) {
+	tests := []testSkill{
+		{
+			name:           "update skill tags success",
+			url:            "/skills/python/tags",
+			payload:        `{"tags": ["programming", "scripting", "web", "data science"]}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "success", "message": "updating skill tags already in progress", "data": {"operation_id": "op-1", "status": "pending"}}`,
+			mockStorage: &mockSkillStorage{
+				skill: &Skill{
+					Key: "python",
+				},
+			},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "invalid request",
+			url:            "/skills/python/tags",
+			payload:        `{"tags": ""}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status": "error", "message": "invalid request"}`,
+			mockStorage:    &mockSkillStorage{},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "not exist skill",
+			url:            "/skills/python3/tags",
+			payload:        `{"tags": ["programming", "scripting", "web", "data science"]}`,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status": "error", "message": "skill not found"}`,
+			mockStorage: &mockSkillStorage{
+				errGet: sql.ErrNoRows,
+			},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "database connection error",
+			url:            "/skills/python4/tags",
+			payload:        `{"tags": ["programming", "scripting", "web", "data science"]}`,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"status": "error", "message": "not be able to get skill"}`,
+			mockStorage: &mockSkillStorage{
+				errGet: sql.ErrConnDone,
+			},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "publish skill error",
+			url:            "/skills/python5/tags",
+			payload:        `{"tags": ["programming", "scripting", "web", "data science"]}`,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"status": "error", "message": "not be able to update skill tags"}`,
+			mockStorage: &mockSkillStorage{
+				skill: &Skill{
+					Key: "python5",
+				},
+			},
+			mockSkillQueue: &mockSkillQueue{errPublish: errors.New("publish error")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			res := httptest.NewRecorder()
+			c, r := gin.CreateTestContext(res)
+			c.Request = httptest.NewRequest(http.MethodPut, tt.url, nil)
+			c.Request.Header.Set("If-Match", "*")
+			c.Request.Body = ioutil.NopCloser(strings.NewReader(tt.payload))
+
+			h := NewSkillHandler(tt.mockStorage, tt.mockSkillQueue, nil)
+			r.PUT("/skills/:key/tags", h.UpdateTags) // Call to a handler method
+			r.ServeHTTP(res, c.Request)
+
+			// Assert response
+			if status := res.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			// Parse and compare JSON
+			var actual, expectedJSON map[string]interface{}
+			err := json.Unmarshal(res.Body.Bytes(), &actual)
+			if err != nil {
+				t.Fatalf("could not unmarshal response body: %v", err)
+			}
+
+			err = json.Unmarshal([]byte(tt.expectedBody), &expectedJSON)
+			if err != nil {
+				t.Fatalf("could not unmarshal expected JSON: %v", err)
+			}
+
+			// Assert response body
+			if !reflect.DeepEqual(expectedJSON, actual) {
+				t.Errorf("handler returned unexpected body: got %v want %v", actual, expectedJSON)
+			}
+		})
+	}
+}
+
+func TestEditTagsHandler(t *testing.T) {
+	handlers := []struct {
+		action     SkillAction
+		url        string
+		inProgress string
+		handler    func(h skillHandler) gin.HandlerFunc
+	}{
+		{AddTagsAction, "/skills/python/actions/add_tags", "adding skill tags already in progress", func(h skillHandler) gin.HandlerFunc { return h.AddTags }},
+		{RemoveTagsAction, "/skills/python/actions/remove_tags", "removing skill tags already in progress", func(h skillHandler) gin.HandlerFunc { return h.RemoveTags }},
+	}
+
+	for _, hh := range handlers {
+		tests := []struct {
+			name            string
+			payload         string
+			ifMatch         string
+			expectedStatus  int
+			expectedBody    string
+			expectedVersion int64
+			mockStorage     *mockSkillStorage
+			mockSkillQueue  *mockSkillQueue
+		}{
+			{
+				name:           "without If-Match",
+				payload:        `{"tags": ["web"]}`,
+				expectedStatus: http.StatusOK,
+				expectedBody:   `{"status": "success", "message": "` + hh.inProgress + `", "data": {"operation_id": "op-1", "status": "pending"}}`,
+				mockStorage:    &mockSkillStorage{skill: &Skill{Key: "python", Version: 4}},
+				mockSkillQueue: &mockSkillQueue{},
+			},
+			{
+				name:            "with matching If-Match",
+				payload:         `{"tags": ["web"]}`,
+				ifMatch:         `"4"`,
+				expectedStatus:  http.StatusOK,
+				expectedBody:    `{"status": "success", "message": "` + hh.inProgress + `", "data": {"operation_id": "op-1", "status": "pending"}}`,
+				expectedVersion: 4,
+				mockStorage:     &mockSkillStorage{skill: &Skill{Key: "python", Version: 4}},
+				mockSkillQueue:  &mockSkillQueue{},
+			},
+			{
+				name:           "with stale If-Match",
+				payload:        `{"tags": ["web"]}`,
+				ifMatch:        `"3"`,
+				expectedStatus: http.StatusPreconditionFailed,
+				expectedBody:   `{"status": "error", "message": "skill has been modified"}`,
+				mockStorage:    &mockSkillStorage{skill: &Skill{Key: "python", Version: 4}},
+				mockSkillQueue: &mockSkillQueue{},
+			},
+			{
+				name:           "invalid request",
+				payload:        `{"tags": "web"}`,
+				expectedStatus: http.StatusBadRequest,
+				expectedBody:   `{"status": "error", "message": "invalid request"}`,
+				mockStorage:    &mockSkillStorage{},
+				mockSkillQueue: &mockSkillQueue{},
+			},
+			{
+				name:           "not exist skill",
+				payload:        `{"tags": ["web"]}`,
+				expectedStatus: http.StatusNotFound,
+				expectedBody:   `{"status": "error", "message": "skill not found"}`,
+				mockStorage:    &mockSkillStorage{errGet: sql.ErrNoRows},
+				mockSkillQueue: &mockSkillQueue{},
+			},
+			{
+				name:           "publish skill error",
+				payload:        `{"tags": ["web"]}`,
+				expectedStatus: http.StatusInternalServerError,
+				expectedBody:   `{"status": "error", "message": "not be able to update skill tags"}`,
+				mockStorage:    &mockSkillStorage{skill: &Skill{Key: "python", Version: 4}},
+				mockSkillQueue: &mockSkillQueue{errPublish: errors.New("publish error")},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(string(hh.action)+" "+tt.name, func(t *testing.T) {
+				gin.SetMode(gin.TestMode)
+
+				res := httptest.NewRecorder()
+				c, r := gin.CreateTestContext(res)
+				c.Request = httptest.NewRequest(http.MethodPatch, hh.url, strings.NewReader(tt.payload))
+				if tt.ifMatch != "" {
+					c.Request.Header.Set("If-Match", tt.ifMatch)
+				}
+
+				h := NewSkillHandler(tt.mockStorage, tt.mockSkillQueue, nil)
+				r.PATCH("/skills/:key/actions/"+string(hh.action), hh.handler(h))
+				r.ServeHTTP(res, c.Request)
+
+				// Assert response
+				if status := res.Code; status != tt.expectedStatus {
+					t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+				}
+
+				// Parse and compare JSON
+				var actual, expectedJSON map[string]interface{}
+				if err := json.Unmarshal(res.Body.Bytes(), &actual); err != nil {
+					t.Fatalf("could not unmarshal response body: %v", err)
+				}
+
+				if err := json.Unmarshal([]byte(tt.expectedBody), &expectedJSON); err != nil {
+					t.Fatalf("could not unmarshal expected JSON: %v", err)
+				}
+
+				// Assert response body
+				if !reflect.DeepEqual(expectedJSON, actual) {
+					t.Errorf("handler returned unexpected body: got %v want %v", actual, expectedJSON)
+				}
+
+				if tt.expectedStatus == http.StatusOK && (tt.mockSkillQueue.action != hh.action || tt.mockSkillQueue.expectedVersion != tt.expectedVersion) {
+					t.Errorf("expected %s expecting version %d, got %s expecting %d", hh.action, tt.expectedVersion, tt.mockSkillQueue.action, tt.mockSkillQueue.expectedVersion)
+				}
+			})
+		}
+	}
+}
+
+func TestImportSkillsHandler(t *testing.T) {
+	python := `{"key": "python", "name": "Python", "description": "Python language", "logo": "python.svg", "tags": ["backend"]}`
+	golang := `{"key": "go", "name": "Go", "description": "Go language", "logo": "go.svg", "tags": []}`
+
+	tests := []struct {
+		name           string
+		url            string
+		contentType    string
+		payload        string
+		expectedStatus int
+		expectedBody   string
+		published      []SkillAction
+		mockStorage    *mockSkillStorage
+		mockSkillQueue *mockSkillQueue
+	}{
+		{
+			name:           "import json",
+			url:            "/skills/import",
+			contentType:    "application/json",
+			payload:        `[` + python + `,` + golang + `]`,
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   `{"status": "success", "message": "importing skills already in progress", "data": {"total": 2, "created": 2, "overwritten": 0, "skipped": 0, "errors": []}}`,
+			published:      []SkillAction{CreateSkillAction, CreateSkillAction},
+			mockStorage:    &mockSkillStorage{},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "import csv reporting invalid rows",
+			url:            "/skills/import?format=csv",
+			payload:        "key,name,description,logo,tags\ngo,Go,Go language,go.svg,backend;cloud\npython,,Python language,python.svg,\n",
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   `{"status": "success", "message": "importing skills already in progress", "data": {"total": 2, "created": 1, "overwritten": 0, "skipped": 0, "errors": [{"row": 2, "key": "python", "error": "name is required"}]}}`,
+			published:      []SkillAction{CreateSkillAction},
+			mockStorage:    &mockSkillStorage{},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "skip existing skills",
+			url:            "/skills/import?on_conflict=skip",
+			contentType:    "application/x-ndjson",
+			payload:        python + "\n" + golang + "\n",
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   `{"status": "success", "message": "importing skills already in progress", "data": {"total": 2, "created": 1, "overwritten": 0, "skipped": 1, "errors": []}}`,
+			published:      []SkillAction{CreateSkillAction},
+			mockStorage:    &mockSkillStorage{existing: map[string]bool{"python": false}},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "overwrite existing skills",
+			url:            "/skills/import?on_conflict=overwrite",
+			contentType:    "application/x-ndjson",
+			payload:        python + "\n" + golang + "\n",
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   `{"status": "success", "message": "importing skills already in progress", "data": {"total": 2, "created": 1, "overwritten": 1, "skipped": 0, "errors": []}}`,
+			published:      []SkillAction{UpdateSkillAction, CreateSkillAction},
+			mockStorage:    &mockSkillStorage{existing: map[string]bool{"python": false}},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "fail on existing skills",
+			url:            "/skills/import",
+			contentType:    "application/x-ndjson",
+			payload:        python + "\n" + golang + "\n",
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status": "error", "message": "some skills already exist, nothing was imported", "data": {"total": 2, "created": 0, "overwritten": 0, "skipped": 0, "errors": [{"row": 1, "key": "python", "error": "skill already exists"}]}}`,
+			mockStorage:    &mockSkillStorage{existing: map[string]bool{"python": false}},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "nothing to import",
+			url:            "/skills/import?on_conflict=skip",
+			contentType:    "application/json",
+			payload:        `[` + python + `]`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"status": "error", "message": "no skills to import", "data": {"total": 1, "created": 0, "overwritten": 0, "skipped": 1, "errors": []}}`,
+			mockStorage:    &mockSkillStorage{existing: map[string]bool{"python": false}},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "unknown format",
+			url:            "/skills/import",
+			contentType:    "text/plain",
+			payload:        python,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status": "error", "message": "format must be json, ndjson or csv"}`,
+			mockStorage:    &mockSkillStorage{},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "invalid conflict mode",
+			url:            "/skills/import?format=json&on_conflict=merge",
+			payload:        `[` + python + `]`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status": "error", "message": "on_conflict must be skip, overwrite or fail"}`,
+			mockStorage:    &mockSkillStorage{},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "malformed import",
+			url:            "/skills/import?format=csv",
+			payload:        "key,name\npython,Python\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status": "error", "message": "invalid import: csv header must name the key, name, description, logo and tags columns"}`,
+			mockStorage:    &mockSkillStorage{},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "import too large",
+			url:            "/skills/import?format=ndjson",
+			payload:        strings.Repeat(" ", maxImportBytes+1),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   `{"status": "error", "message": "import is too large"}`,
+			mockStorage:    &mockSkillStorage{},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "get skills error",
+			url:            "/skills/import?format=json",
+			payload:        `[` + python + `]`,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"status": "error", "message": "not be able to get skills"}`,
+			mockStorage:    &mockSkillStorage{errGet: errors.New("database error")},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "publish skill error",
+			url:            "/skills/import?format=json",
+			payload:        `[` + python + `]`,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"status": "error", "message": "not be able to import skills", "data": {"total": 1, "created": 0, "overwritten": 0, "skipped": 0, "errors": [{"row": 1, "key": "python", "error": "not be able to publish skill"}]}}`,
+			mockStorage:    &mockSkillStorage{},
+			mockSkillQueue: &mockSkillQueue{errPublish: errors.New("publish error")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			res := httptest.NewRecorder()
+			c, r := gin.CreateTestContext(res)
+			c.Request = httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.payload))
+			if tt.contentType != "" {
+				c.Request.Header.Set("Content-Type", tt.contentType)
+			}
+
+			h := NewSkillHandler(tt.mockStorage, tt.mockSkillQueue, nil)
+			r.POST("/skills/import", h.ImportSkills)
+			r.ServeHTTP(res, c.Request)
+
+			// Assert response
+			if status := res.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			// Parse and compare JSON
+			var actual, expectedJSON map[string]interface{}
+			if err := json.Unmarshal(res.Body.Bytes(), &actual); err != nil {
+				t.Fatalf("could not unmarshal response body: %v", err)
+			}
+
+			if err := json.Unmarshal([]byte(tt.expectedBody), &expectedJSON); err != nil {
+				t.Fatalf("could not unmarshal expected JSON: %v", err)
+			}
+
+			// The batch id is new on every import, it is checked against the
+			// one rows were published under instead.
+			var batchID any
+			if data, ok := actual["data"].(map[string]interface{}); ok {
+				batchID = data["batch_id"]
+				delete(data, "batch_id")
+			}
+
+			// Assert response body
+			if !reflect.DeepEqual(expectedJSON, actual) {
+				t.Errorf("handler returned unexpected body: got %v want %v", actual, expectedJSON)
+			}
+
+			if !reflect.DeepEqual(tt.mockSkillQueue.published, tt.published) {
+				t.Errorf("expected published %v, got %v", tt.published, tt.mockSkillQueue.published)
+			}
+
+			if tt.expectedStatus == http.StatusAccepted {
+				if batchID == nil || batchID != tt.mockSkillQueue.batchID {
+					t.Errorf("expected batch_id %q, got %v", tt.mockSkillQueue.batchID, batchID)
+				}
+
+				if location := res.Header().Get("Location"); location != operation.BatchLocation(tt.mockSkillQueue.batchID) {
+					t.Errorf("expected Location %q, got %q", operation.BatchLocation(tt.mockSkillQueue.batchID), location)
+				}
+			}
+		})
+	}
+}
+
+func TestRestoreSkillHandler(t *testing.T) {
+	tests := []testSkill{
+		{
+			name:           "restore skill success",
+			url:            "/skills/python/actions/restore",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "success", "message": "restoring skill already in progress", "data": {"operation_id": "op-1", "status": "pending"}}`,
+			mockStorage: &mockSkillStorage{
+				deletedSkill: &Skill{
+					Key: "python",
+				},
+			},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "not deleted skill",
+			url:            "/skills/python/actions/restore",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status": "error", "message": "deleted skill not found"}`,
+			mockStorage:    &mockSkillStorage{},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "database connection error",
+			url:            "/skills/python/actions/restore",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"status": "error", "message": "not be able to get skill"}`,
+			mockStorage: &mockSkillStorage{
+				errGet: sql.ErrConnDone,
+			},
+			mockSkillQueue: &mockSkillQueue{},
+		},
+		{
+			name:           "publish skill error",
+			url:            "/skills/python/actions/restore",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"status": "error", "message": "not be able to restore skill"}`,
+			mockStorage: &mockSkillStorage{
+				deletedSkill: &Skill{
+					Key: "python",
+				},
+			},
+			mockSkillQueue: &mockSkillQueue{errPublish: errors.New("publish error")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			res := httptest.NewRecorder()
+			c, r := gin.CreateTestContext(res)
+			c.Request = httptest.NewRequest(http.MethodPost, tt.url, nil)
+			c.Request.Header.Set("If-Match", "*")
+
+			h := NewSkillHandler(tt.mockStorage, tt.mockSkillQueue, nil)
+			r.POST("/skills/:key/actions/restore", h.RestoreSkill)
+			r.ServeHTTP(res, c.Request)
+
+			// Assert response
+			if status := res.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			// Parse and compare JSON
+			var actual, expectedJSON map[string]interface{}
+			if err := json.Unmarshal(res.Body.Bytes(), &actual); err != nil {
+				t.Fatalf("could not unmarshal response body: %v", err)
+			}
+
+			if err := json.Unmarshal([]byte(tt.expectedBody), &expectedJSON); err != nil {
+				t.Fatalf("could not unmarshal expected JSON: %v", err)
+			}
+
+			// Assert response body
+			if !reflect.DeepEqual(expectedJSON, actual) {
+				t.Errorf("handler returned unexpected body: got %v want %v", actual, expectedJSON)
+			}
+		})
+	}
+}
+
+func TestWriteHandlerOperationLocation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	res := httptest.NewRecorder()
+	c, r := gin.CreateTestContext(res)
+	c.Request = httptest.NewRequest(http.MethodDelete, "/skills/python", nil)
+	c.Request.Header.Set("If-Match", "*")
+
+	h := NewSkillHandler(&mockSkillStorage{skill: &Skill{Key: "python"}}, &mockSkillQueue{}, nil)
+	r.DELETE("/skills/:key", h.DeleteSkill)
+	r.ServeHTTP(res, c.Request)
+
+	if got := res.Header().Get("Location"); got != "/api/v1/operations/op-1" {
+		t.Errorf("handler returned wrong location: got %q want %q", got, "/api/v1/operations/op-1")
+	}
+}
+
+func TestWriteHandlerWait(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		expectedBody   string
+		mockWaiter     *mockOperationWaiter
+	}{
+		{
+			name:           "return skill once applied",
+			url:            "/skills/python/actions/name?wait=5s",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "success", "data": {"key": "python", "name": "Python", "description": "", "logo": "", "tags": null}}`,
+			mockWaiter:     &mockOperationWaiter{operation: &operation.Operation{ID: "op-1", Action: "update_name", Status: operation.SucceededStatus}},
+		},
+		{
+			name:           "return consumer error",
+			url:            "/skills/python/actions/name?wait=5s",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"status": "error", "message": "invalid payload"}`,
+			mockWaiter:     &mockOperationWaiter{operation: &operation.Operation{ID: "op-1", Action: "update_name", Status: operation.FailedStatus, Reason: "invalid payload"}},
+		},
+		{
+			name:           "return conflict when skill moved on",
+			url:            "/skills/python/actions/name?wait=5s",
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedBody:   `{"status": "error", "message": "version conflict: skill python is at version 3, expected 2"}`,
+			mockWaiter:     &mockOperationWaiter{operation: &operation.Operation{ID: "op-1", Action: "update_name", Status: operation.ConflictStatus, Reason: "version conflict: skill python is at version 3, expected 2"}},
+		},
+		{
+			name:           "return not found when skill is gone",
+			url:            "/skills/python/actions/name?wait=5s",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"status": "error", "message": "skill not found: python"}`,
+			mockWaiter:     &mockOperationWaiter{operation: &operation.Operation{ID: "op-1", Action: "update_name", Status: operation.NotFoundStatus, Reason: "skill not found: python"}},
+		},
+		{
+			name:           "fall back to accepted on timeout",
+			url:            "/skills/python/actions/name?wait=5s",
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   `{"status": "success", "message": "updating skill name already in progress", "data": {"operation_id": "op-1", "status": "pending"}}`,
+			mockWaiter:     &mockOperationWaiter{err: context.DeadlineExceeded},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			res := httptest.NewRecorder()
+			c, r := gin.CreateTestContext(res)
+			c.Request = httptest.NewRequest(http.MethodPatch, tt.url, strings.NewReader(`{"name": "Python"}`))
+			c.Request.Header.Set("If-Match", "*")
+
+			h := NewSkillHandler(&mockSkillStorage{skill: &Skill{Key: "python", Name: "Python"}}, &mockSkillQueue{}, tt.mockWaiter)
+			r.PATCH("/skills/:key/actions/name", h.UpdateName)
+			r.ServeHTTP(res, c.Request)
+
+			// Assert response
+			if status := res.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			// Parse and compare JSON
+			var actual, expectedJSON map[string]interface{}
+			if err := json.Unmarshal(res.Body.Bytes(), &actual); err != nil {
+				t.Fatalf("could not unmarshal response body: %v", err)
+			}
+
+			if err := json.Unmarshal([]byte(tt.expectedBody), &expectedJSON); err != nil {
+				t.Fatalf("could not unmarshal expected JSON: %v", err)
+			}
+
+			// Assert response body
+			if !reflect.DeepEqual(expectedJSON, actual) {
+				t.Errorf("handler returned unexpected body: got %v want %v", actual, expectedJSON)
+			}
+		})
+	}
+}
+
+func TestGetSkillHandlerETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	res := httptest.NewRecorder()
+	c, r := gin.CreateTestContext(res)
+	c.Request = httptest.NewRequest(http.MethodGet, "/skills/python", nil)
+
+	h := NewSkillHandler(&mockSkillStorage{skill: &Skill{Key: "python", Version: 4}}, nil, nil)
+	r.GET("/skills/:key", h.GetSkill)
+	r.ServeHTTP(res, c.Request)
+
+	if got := res.Header().Get("ETag"); got != `"4"` {
+		t.Errorf("handler returned wrong etag: got %q want %q", got, `"4"`)
+	}
+}
+
+func TestWriteHandlerIfMatch(t *testing.T) {
+	tests := []struct {
+		name            string
+		ifMatch         string
+		expectedStatus  int
+		expectedBody    string
+		expectedVersion int64
+	}{
+		{
+			name:            "publish version the caller read",
+			ifMatch:         `"4"`,
+			expectedStatus:  http.StatusOK,
+			expectedBody:    `{"status": "success", "message": "updating skill name already in progress", "data": {"operation_id": "op-1", "status": "pending"}}`,
+			expectedVersion: 4,
+		},
+		{
+			name:           "publish without version for any match",
+			ifMatch:        "*",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "success", "message": "updating skill name already in progress", "data": {"operation_id": "op-1", "status": "pending"}}`,
+		},
+		{
+			name:           "require If-Match",
+			expectedStatus: http.StatusPreconditionRequired,
+			expectedBody:   `{"status": "error", "message": "If-Match header is required"}`,
+		},
+		{
+			name:           "reject stale version",
+			ifMatch:        `"3"`,
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedBody:   `{"status": "error", "message": "skill has been modified"}`,
+		},
+		{
+			name:           "reject malformed If-Match",
+			ifMatch:        "4",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"status": "error", "message": "invalid If-Match header"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			res := httptest.NewRecorder()
+			c, r := gin.CreateTestContext(res)
+			c.Request = httptest.NewRequest(http.MethodPatch, "/skills/python/actions/name", strings.NewReader(`{"name": "Python"}`))
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			queue := &mockSkillQueue{}
+			h := NewSkillHandler(&mockSkillStorage{skill: &Skill{Key: "python", Version: 4}}, queue, nil)
+			r.PATCH("/skills/:key/actions/name", h.UpdateName)
+			r.ServeHTTP(res, c.Request)
+
+			// Assert response
+			if status := res.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.expectedStatus)
+			}
+
+			if queue.expectedVersion != tt.expectedVersion {
+				t.Errorf("handler published wrong expected version: got %d want %d", queue.expectedVersion, tt.expectedVersion)
+			}
+
+			// Parse and compare JSON
+			var actual, expectedJSON map[string]interface{}
+			if err := json.Unmarshal(res.Body.Bytes(), &actual); err != nil {
+				t.Fatalf("could not unmarshal response body: %v", err)
+			}
+
+			if err := json.Unmarshal([]byte(tt.expectedBody), &expectedJSON); err != nil {
+				t.Fatalf("could not unmarshal expected JSON: %v", err)
+			}
+
+			// Assert response body
+			if !reflect.DeepEqual(expectedJSON, actual) {
+				t.Errorf("handler returned unexpected body: got %v want %v", actual, expectedJSON)
+			}
+		})
+	}
+}
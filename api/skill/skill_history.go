@@ -0,0 +1,88 @@
+package skill
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+)
+
+var errInvalidHistoryPage = errors.New("limit must be between 1 and 100 and before a positive id")
+
+// HistoryEntry is one change the consumer applied to a skill. Before and
+// After hold the skill as JSON and are nil when it did not exist.
+type HistoryEntry struct {
+	ID         int64
+	Action     string
+	Before     []byte
+	After      []byte
+	Actor      string
+	MessageID  string
+	OccurredAt time.Time
+}
+
+type ResponseHistoryEntry struct {
+	ID         int64           `json:"id"`
+	Action     string          `json:"action"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	Actor      string          `json:"actor"`
+	MessageID  string          `json:"message_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// ResponseHistory is a page of history, newest first. NextBefore is the
+// cursor for the following page and is absent on the last one.
+type ResponseHistory struct {
+	Entries    []ResponseHistoryEntry `json:"entries"`
+	NextBefore *int64                 `json:"next_before,omitempty"`
+}
+
+// historyPage reads ?limit= and ?before=<id> for paging through history. A
+// zero before starts from the newest entry.
+func historyPage(c *gin.Context) (before int64, limit int, err error) {
+	limit = defaultHistoryLimit
+	if v := c.Query("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxHistoryLimit {
+			return 0, 0, errInvalidHistoryPage
+		}
+	}
+
+	if v := c.Query("before"); v != "" {
+		before, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || before < 1 {
+			return 0, 0, errInvalidHistoryPage
+		}
+	}
+
+	return before, limit, nil
+}
+
+func responseHistory(entries []HistoryEntry, limit int) ResponseHistory {
+	res := ResponseHistory{Entries: make([]ResponseHistoryEntry, 0, len(entries))}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		next := entries[limit-1].ID
+		res.NextBefore = &next
+	}
+
+	for _, entry := range entries {
+		res.Entries = append(res.Entries, ResponseHistoryEntry{
+			ID:         entry.ID,
+			Action:     entry.Action,
+			Before:     entry.Before,
+			After:      entry.After,
+			Actor:      entry.Actor,
+			MessageID:  entry.MessageID,
+			OccurredAt: entry.OccurredAt,
+		})
+	}
+	return res
+}
@@ -0,0 +1,306 @@
+package skill
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"mime"
+	"strings"
+)
+
+const (
+	maxImportRows  = 1000
+	maxImportBytes = 10 << 20
+)
+
+const (
+	ImportFormatJSON   = "json"
+	ImportFormatNDJSON = "ndjson"
+	ImportFormatCSV    = "csv"
+)
+
+// What an import does with a row whose key is already taken.
+const (
+	ConflictSkip      = "skip"
+	ConflictOverwrite = "overwrite"
+	ConflictFail      = "fail"
+)
+
+var (
+	errInvalidImportFormat = errors.New("format must be json, ndjson or csv")
+	errInvalidConflictMode = errors.New("on_conflict must be skip, overwrite or fail")
+	errTooManyImportRows   = fmt.Errorf("an import holds at most %d skills", maxImportRows)
+	errEmptyImport         = errors.New("import has no skills")
+	errInvalidImportHeader = errors.New("csv header must name the key, name, description, logo and tags columns")
+)
+
+// csvColumns are the columns a CSV import must have, in any order. Tags are
+// separated by semicolons within their cell.
+var csvColumns = []string{"key", "name", "description", "logo", "tags"}
+
+// ImportRow is one skill read from an import. Rows are numbered from 1 in
+// the order of the file. Err is set when the row cannot be imported.
+type ImportRow struct {
+	Row   int
+	Skill CreateSkillRequest
+	Err   error
+}
+
+type ResponseImportError struct {
+	Row   int    `json:"row"`
+	Key   string `json:"key,omitempty"`
+	Error string `json:"error"`
+}
+
+// ResponseImport counts what became of every row. BatchID is set once rows
+// have been published.
+type ResponseImport struct {
+	BatchID     string                `json:"batch_id,omitempty"`
+	Total       int                   `json:"total"`
+	Created     int                   `json:"created"`
+	Overwritten int                   `json:"overwritten"`
+	Skipped     int                   `json:"skipped"`
+	Errors      []ResponseImportError `json:"errors"`
+}
+
+func (r *ResponseImport) fail(row ImportRow, err error) {
+	r.Errors = append(r.Errors, ResponseImportError{Row: row.Row, Key: row.Skill.Key, Error: err.Error()})
+}
+
+// importFormat reads the format from ?format=, or else from the content type
+// of the body.
+func importFormat(c *gin.Context) (string, error) {
+	if format := c.Query("format"); format != "" {
+		switch format {
+		case ImportFormatJSON, ImportFormatNDJSON, ImportFormatCSV:
+			return format, nil
+		}
+		return "", errInvalidImportFormat
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		return ImportFormatJSON, nil
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return ImportFormatNDJSON, nil
+	case "text/csv":
+		return ImportFormatCSV, nil
+	}
+	return "", errInvalidImportFormat
+}
+
+func conflictMode(c *gin.Context) (string, error) {
+	switch mode := c.DefaultQuery("on_conflict", ConflictFail); mode {
+	case ConflictSkip, ConflictOverwrite, ConflictFail:
+		return mode, nil
+	}
+	return "", errInvalidConflictMode
+}
+
+// readImport reads every row of r. An error is returned when the file as a
+// whole cannot be read, a row that is malformed or breaks the rules of
+// CreateSkillRequest only has its own Err set. A key seen before in the file
+// is an error on the later row.
+func readImport(r io.Reader, format string) ([]ImportRow, error) {
+	var rows []ImportRow
+	var err error
+	switch format {
+	case ImportFormatJSON:
+		rows, err = readJSONImport(r)
+	case ImportFormatNDJSON:
+		rows, err = readNDJSONImport(r)
+	case ImportFormatCSV:
+		rows, err = readCSVImport(r)
+	default:
+		return nil, errInvalidImportFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, errEmptyImport
+	}
+
+	seen := map[string]bool{}
+	for i, row := range rows {
+		if row.Err != nil {
+			continue
+		}
+
+		if err := row.Skill.Validate(); err != nil {
+			rows[i].Err = errors.New(strings.ReplaceAll(err.Error(), "\n", ", "))
+			continue
+		}
+
+		if seen[row.Skill.Key] {
+			rows[i].Err = errors.New("key appears earlier in the import")
+			continue
+		}
+		seen[row.Skill.Key] = true
+	}
+
+	return rows, nil
+}
+
+func readJSONImport(r io.Reader) ([]ImportRow, error) {
+	var raws []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raws); err != nil {
+		return nil, err
+	}
+
+	if len(raws) > maxImportRows {
+		return nil, errTooManyImportRows
+	}
+
+	rows := make([]ImportRow, 0, len(raws))
+	for i, raw := range raws {
+		rows = append(rows, jsonImportRow(i+1, raw))
+	}
+	return rows, nil
+}
+
+// readNDJSONImport reads one skill per line, numbering rows by line. Blank
+// lines are skipped.
+func readNDJSONImport(r io.Reader) ([]ImportRow, error) {
+	// Room for a line one byte longer than the body may be, so an oversized
+	// body is reported as such rather than as a line too long.
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportBytes+1)
+
+	rows := make([]ImportRow, 0)
+	for line := 1; scanner.Scan(); line++ {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		if len(rows) == maxImportRows {
+			return nil, errTooManyImportRows
+		}
+		rows = append(rows, jsonImportRow(line, raw))
+	}
+
+	return rows, scanner.Err()
+}
+
+func jsonImportRow(n int, raw []byte) ImportRow {
+	row := ImportRow{Row: n}
+	if err := json.Unmarshal(raw, &row.Skill); err != nil {
+		row.Err = errors.New("invalid skill: " + err.Error())
+	}
+	return row
+}
+
+// readCSVImport reads a header naming csvColumns and then one skill per
+// record, numbering rows from the first record after the header.
+func readCSVImport(r io.Reader) ([]ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errEmptyImport
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range csvColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, errInvalidImportHeader
+		}
+	}
+
+	rows := make([]ImportRow, 0)
+	for n := 1; ; n++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rows) == maxImportRows {
+			return nil, errTooManyImportRows
+		}
+
+		if len(record) != len(header) {
+			rows = append(rows, ImportRow{Row: n, Err: fmt.Errorf("expected %d fields, got %d", len(header), len(record))})
+			continue
+		}
+
+		rows = append(rows, ImportRow{Row: n, Skill: CreateSkillRequest{
+			Key:         record[columns["key"]],
+			Name:        record[columns["name"]],
+			Description: record[columns["description"]],
+			Logo:        record[columns["logo"]],
+			Tags:        csvTags(record[columns["tags"]]),
+		}})
+	}
+
+	return rows, nil
+}
+
+// csvTags splits a tags cell on semicolons. An empty cell is an empty list,
+// like "tags": [] in JSON.
+func csvTags(cell string) []string {
+	tags := make([]string, 0)
+	for _, tag := range strings.Split(cell, ";") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// importItem is a row about to be published, as a create or, when it
+// overwrites an existing skill, an update.
+type importItem struct {
+	row    ImportRow
+	action SkillAction
+}
+
+// planImport decides what to publish for each row, given which keys are
+// taken and whether their skill is deleted. A deleted skill is never
+// overwritten, it has to be restored first. conflict reports rows that made
+// an import in fail mode fail.
+func planImport(rows []ImportRow, existing map[string]bool, mode string) (items []importItem, res ResponseImport, conflict bool) {
+	res = ResponseImport{Total: len(rows), Errors: make([]ResponseImportError, 0)}
+	for _, row := range rows {
+		if row.Err != nil {
+			res.fail(row, row.Err)
+			continue
+		}
+
+		deleted, taken := existing[row.Skill.Key]
+		switch {
+		case !taken:
+			items = append(items, importItem{row: row, action: CreateSkillAction})
+		case mode == ConflictSkip:
+			res.Skipped++
+		case deleted:
+			res.fail(row, errors.New("skill is deleted, restore it instead"))
+			conflict = true
+		case mode == ConflictOverwrite:
+			items = append(items, importItem{row: row, action: UpdateSkillAction})
+		default:
+			res.fail(row, errors.New("skill already exists"))
+			conflict = true
+		}
+	}
+
+	return items, res, conflict && mode == ConflictFail
+}
@@ -0,0 +1,180 @@
+package skill
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadImport(t *testing.T) {
+	goSkill := CreateSkillRequest{Key: "go", Name: "Go", Description: "Go language", Logo: "go.svg", Tags: []string{"backend", "cloud"}}
+
+	tests := []struct {
+		name   string
+		format string
+		body   string
+		want   []ImportRow
+	}{
+		{
+			name:   "json array",
+			format: ImportFormatJSON,
+			body:   `[{"key": "go", "name": "Go", "description": "Go language", "logo": "go.svg", "tags": ["backend", "cloud"]}]`,
+			want:   []ImportRow{{Row: 1, Skill: goSkill}},
+		},
+		{
+			name:   "ndjson numbered by line",
+			format: ImportFormatNDJSON,
+			body:   "\n{\"key\": \"go\", \"name\": \"Go\", \"description\": \"Go language\", \"logo\": \"go.svg\", \"tags\": [\"backend\", \"cloud\"]}\n",
+			want:   []ImportRow{{Row: 2, Skill: goSkill}},
+		},
+		{
+			name:   "csv with columns in any order",
+			format: ImportFormatCSV,
+			body:   "name,key,tags,logo,description\nGo,go,backend; cloud,go.svg,Go language\n",
+			want:   []ImportRow{{Row: 1, Skill: goSkill}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got, err := readImport(strings.NewReader(tt.body), tt.format)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readImport() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("should report invalid rows by row", func(t *testing.T) {
+		// Arrange
+		body := "key,name,description,logo,tags\n" +
+			"go,Go,Go language,go.svg,backend\n" +
+			"python,,Python language,,\n" +
+			"go,Go again,Go language,go.svg,backend\n" +
+			"rust,Rust\n"
+
+		// Act
+		rows, err := readImport(strings.NewReader(body), ImportFormatCSV)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		var errs []string
+		for _, row := range rows {
+			if row.Err != nil {
+				errs = append(errs, row.Err.Error())
+			} else {
+				errs = append(errs, "")
+			}
+		}
+
+		want := []string{"", "name is required, logo is required", "key appears earlier in the import", "expected 5 fields, got 2"}
+		if !reflect.DeepEqual(errs, want) {
+			t.Errorf("expected row errors %q, got %q", want, errs)
+		}
+	})
+
+	t.Run("should report malformed json row", func(t *testing.T) {
+		// Act
+		rows, err := readImport(strings.NewReader(`[{"key": "go", "tags": "backend"}]`), ImportFormatJSON)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if len(rows) != 1 || rows[0].Err == nil || !strings.HasPrefix(rows[0].Err.Error(), "invalid skill: ") {
+			t.Errorf("expected invalid skill error, got %+v", rows)
+		}
+	})
+
+	t.Run("should reject file it cannot read", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			format string
+			body   string
+			want   error
+		}{
+			{name: "empty", format: ImportFormatJSON, body: `[]`, want: errEmptyImport},
+			{name: "csv without header", format: ImportFormatCSV, body: "", want: errEmptyImport},
+			{name: "csv missing column", format: ImportFormatCSV, body: "key,name,description,logo\n", want: errInvalidImportHeader},
+			{name: "too many rows", format: ImportFormatNDJSON, body: strings.Repeat("{}\n", maxImportRows+1), want: errTooManyImportRows},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if _, err := readImport(strings.NewReader(tt.body), tt.format); err != tt.want {
+					t.Errorf("expected %v, got %v", tt.want, err)
+				}
+			})
+		}
+	})
+}
+
+func TestPlanImport(t *testing.T) {
+	rows := []ImportRow{
+		{Row: 1, Skill: CreateSkillRequest{Key: "go"}},
+		{Row: 2, Skill: CreateSkillRequest{Key: "python"}},
+		{Row: 3, Skill: CreateSkillRequest{Key: "cobol"}},
+	}
+	existing := map[string]bool{"python": false, "cobol": true}
+
+	tests := []struct {
+		mode     string
+		actions  []SkillAction
+		skipped  int
+		errors   []ResponseImportError
+		conflict bool
+	}{
+		{
+			mode:    ConflictSkip,
+			actions: []SkillAction{CreateSkillAction},
+			skipped: 2,
+			errors:  []ResponseImportError{},
+		},
+		{
+			mode:    ConflictOverwrite,
+			actions: []SkillAction{CreateSkillAction, UpdateSkillAction},
+			errors:  []ResponseImportError{{Row: 3, Key: "cobol", Error: "skill is deleted, restore it instead"}},
+		},
+		{
+			mode:     ConflictFail,
+			actions:  []SkillAction{CreateSkillAction},
+			errors:   []ResponseImportError{{Row: 2, Key: "python", Error: "skill already exists"}, {Row: 3, Key: "cobol", Error: "skill is deleted, restore it instead"}},
+			conflict: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			// Act
+			items, res, conflict := planImport(rows, existing, tt.mode)
+
+			// Assert
+			var actions []SkillAction
+			for _, item := range items {
+				actions = append(actions, item.action)
+			}
+
+			if !reflect.DeepEqual(actions, tt.actions) {
+				t.Errorf("expected actions %v, got %v", tt.actions, actions)
+			}
+
+			if res.Total != 3 || res.Skipped != tt.skipped || !reflect.DeepEqual(res.Errors, tt.errors) {
+				t.Errorf("expected 3 rows, %d skipped and errors %+v, got %+v", tt.skipped, tt.errors, res)
+			}
+
+			if conflict != tt.conflict {
+				t.Errorf("expected conflict %v, got %v", tt.conflict, conflict)
+			}
+		})
+	}
+}
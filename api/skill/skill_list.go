@@ -0,0 +1,213 @@
+package skill
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+const (
+	SortByKey       = "key"
+	SortByName      = "name"
+	SortByUpdatedAt = "updated_at"
+)
+
+var (
+	errInvalidListLimit  = errors.New("limit must be between 1 and 100")
+	errInvalidListSort   = errors.New("sort must be one of key, name or updated_at")
+	errInvalidListOrder  = errors.New("order must be asc or desc")
+	errInvalidTagMatch   = errors.New("tag_match must be any or all")
+	errInvalidListCursor = errors.New("cursor is invalid for this sort")
+)
+
+// ListQuery selects one page of skills. Tags match skills having any of
+// them, or all of them with AllTags. KeyPrefix and Name are matched as a
+// prefix of the key and a case-insensitive substring of the name.
+type ListQuery struct {
+	Deleted   bool
+	Tags      []string
+	AllTags   bool
+	KeyPrefix string
+	Name      string
+	Sort      string
+	Desc      bool
+	Limit     int
+	Cursor    *Cursor
+}
+
+// Cursor is a position in a sorted listing: the sort value and key of the
+// skill next to it. A Prev cursor pages backward from that skill, otherwise
+// forward. It only makes sense for the sort it was issued for.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d,omitempty"`
+	Value string `json:"v"`
+	Key   string `json:"k"`
+	Prev  bool   `json:"p,omitempty"`
+}
+
+// SkillPage is one page of a listing in the order asked for. Total counts
+// every skill matching the filters, across all pages.
+type SkillPage struct {
+	Skills []Skill
+	Total  int
+	Next   *Cursor
+	Prev   *Cursor
+}
+
+type ResponsePage struct {
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// listQuery reads ?limit, ?cursor, ?tags (comma separated) with
+// ?tag_match=any|all, ?key_prefix, ?name, ?sort and ?order.
+func listQuery(c *gin.Context, deleted bool) (ListQuery, error) {
+	q := ListQuery{
+		Deleted:   deleted,
+		KeyPrefix: c.Query("key_prefix"),
+		Name:      c.Query("name"),
+		Sort:      c.DefaultQuery("sort", SortByKey),
+		Limit:     defaultListLimit,
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxListLimit {
+			return ListQuery{}, errInvalidListLimit
+		}
+		q.Limit = limit
+	}
+
+	switch q.Sort {
+	case SortByKey, SortByName, SortByUpdatedAt:
+	default:
+		return ListQuery{}, errInvalidListSort
+	}
+
+	switch c.DefaultQuery("order", "asc") {
+	case "asc":
+	case "desc":
+		q.Desc = true
+	default:
+		return ListQuery{}, errInvalidListOrder
+	}
+
+	for _, tag := range strings.Split(c.Query("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			q.Tags = append(q.Tags, tag)
+		}
+	}
+
+	switch c.DefaultQuery("tag_match", "any") {
+	case "any":
+	case "all":
+		q.AllTags = true
+	default:
+		return ListQuery{}, errInvalidTagMatch
+	}
+
+	if v := c.Query("cursor"); v != "" {
+		cursor, err := decodeCursor(v)
+		if err != nil || cursor.Sort != q.Sort || cursor.Desc != q.Desc {
+			return ListQuery{}, errInvalidListCursor
+		}
+		q.Cursor = cursor
+	}
+
+	return q, nil
+}
+
+func encodeCursor(cursor *Cursor) string {
+	if cursor == nil {
+		return ""
+	}
+	b, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(v string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(b, &cursor); err != nil {
+		return nil, err
+	}
+
+	if cursor.Sort == SortByUpdatedAt {
+		if _, err := time.Parse(time.RFC3339Nano, cursor.Value); err != nil {
+			return nil, err
+		}
+	}
+	return &cursor, nil
+}
+
+// sortValue is the value skill is sorted by, as it is kept in a cursor.
+func sortValue(skill Skill, sort string) string {
+	switch sort {
+	case SortByName:
+		return skill.Name
+	case SortByUpdatedAt:
+		return skill.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	default:
+		return skill.Key
+	}
+}
+
+// pageOf turns the rows fetched for q into a page. The rows are in the
+// direction of travel, so reversed when paging backward, and hold one row
+// past the limit when there is more to see in that direction.
+func pageOf(q ListQuery, rows []Skill, total int) SkillPage {
+	backward := q.Cursor != nil && q.Cursor.Prev
+
+	more := len(rows) > q.Limit
+	if more {
+		rows = rows[:q.Limit]
+	}
+
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	page := SkillPage{Skills: rows, Total: total}
+	if len(rows) == 0 {
+		return page
+	}
+
+	// Paging backward always leaves the cursor's skill after the page, and
+	// paging forward from a cursor always leaves it before.
+	if more || backward {
+		last := rows[len(rows)-1]
+		page.Next = &Cursor{Sort: q.Sort, Desc: q.Desc, Value: sortValue(last, q.Sort), Key: last.Key}
+	}
+
+	if backward && more || !backward && q.Cursor != nil {
+		first := rows[0]
+		page.Prev = &Cursor{Sort: q.Sort, Desc: q.Desc, Value: sortValue(first, q.Sort), Key: first.Key, Prev: true}
+	}
+
+	return page
+}
+
+func responsePage(page SkillPage) ResponsePage {
+	return ResponsePage{
+		Total:      page.Total,
+		NextCursor: encodeCursor(page.Next),
+		PrevCursor: encodeCursor(page.Prev),
+	}
+}
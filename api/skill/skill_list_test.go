@@ -0,0 +1,172 @@
+package skill
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want ListQuery
+	}{
+		{
+			name: "defaults",
+			url:  "/skills",
+			want: ListQuery{Sort: SortByKey, Limit: defaultListLimit},
+		},
+		{
+			name: "filters",
+			url:  "/skills?tags=web,%20cloud,&tag_match=all&key_prefix=go&name=lang",
+			want: ListQuery{Tags: []string{"web", "cloud"}, AllTags: true, KeyPrefix: "go", Name: "lang", Sort: SortByKey, Limit: defaultListLimit},
+		},
+		{
+			name: "sort and limit",
+			url:  "/skills?sort=updated_at&order=desc&limit=5",
+			want: ListQuery{Sort: SortByUpdatedAt, Desc: true, Limit: 5},
+		},
+		{
+			name: "cursor",
+			url:  "/skills?sort=name&cursor=" + encodeCursor(&Cursor{Sort: SortByName, Value: "Go", Key: "go", Prev: true}),
+			want: ListQuery{Sort: SortByName, Limit: defaultListLimit, Cursor: &Cursor{Sort: SortByName, Value: "Go", Key: "go", Prev: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, tt.url, nil)
+
+			got, err := listQuery(c, false)
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("listQuery() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("should reject cursor issued for another order", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/skills?order=desc&cursor="+encodeCursor(&Cursor{Sort: SortByKey, Value: "go", Key: "go"}), nil)
+
+		if _, err := listQuery(c, false); err != errInvalidListCursor {
+			t.Errorf("expected %s, got %v", errInvalidListCursor, err)
+		}
+	})
+
+	t.Run("should reject updated_at cursor without a time", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/skills?sort=updated_at&cursor="+encodeCursor(&Cursor{Sort: SortByUpdatedAt, Value: "yesterday", Key: "go"}), nil)
+
+		if _, err := listQuery(c, false); err != errInvalidListCursor {
+			t.Errorf("expected %s, got %v", errInvalidListCursor, err)
+		}
+	})
+}
+
+func TestPageOf(t *testing.T) {
+	skills := func(keys ...string) []Skill {
+		res := make([]Skill, 0, len(keys))
+		for _, key := range keys {
+			res = append(res, Skill{Key: key})
+		}
+		return res
+	}
+
+	keys := func(skills []Skill) []string {
+		res := make([]string, 0, len(skills))
+		for _, skill := range skills {
+			res = append(res, skill.Key)
+		}
+		return res
+	}
+
+	t.Run("should only have next cursor on first page", func(t *testing.T) {
+		// Arrange
+		q := ListQuery{Sort: SortByKey, Limit: 2}
+
+		// Act
+		page := pageOf(q, skills("a", "b", "c"), 5)
+
+		// Assert
+		if !reflect.DeepEqual(keys(page.Skills), []string{"a", "b"}) || page.Total != 5 {
+			t.Errorf("expected a, b of 5, got %v of %d", keys(page.Skills), page.Total)
+		}
+
+		if !reflect.DeepEqual(page.Next, &Cursor{Sort: SortByKey, Value: "b", Key: "b"}) {
+			t.Errorf("expected next cursor after b, got %+v", page.Next)
+		}
+
+		if page.Prev != nil {
+			t.Errorf("expected no prev cursor, got %+v", page.Prev)
+		}
+	})
+
+	t.Run("should only have prev cursor on last page", func(t *testing.T) {
+		// Arrange
+		q := ListQuery{Sort: SortByKey, Limit: 2, Cursor: &Cursor{Sort: SortByKey, Value: "d", Key: "d"}}
+
+		// Act
+		page := pageOf(q, skills("e"), 5)
+
+		// Assert
+		if page.Next != nil {
+			t.Errorf("expected no next cursor, got %+v", page.Next)
+		}
+
+		if !reflect.DeepEqual(page.Prev, &Cursor{Sort: SortByKey, Value: "e", Key: "e", Prev: true}) {
+			t.Errorf("expected prev cursor before e, got %+v", page.Prev)
+		}
+	})
+
+	t.Run("should put rows fetched backward back in order", func(t *testing.T) {
+		// Arrange
+		q := ListQuery{Sort: SortByKey, Limit: 2, Cursor: &Cursor{Sort: SortByKey, Value: "d", Key: "d", Prev: true}}
+
+		// Act
+		page := pageOf(q, skills("c", "b", "a"), 5)
+
+		// Assert
+		if !reflect.DeepEqual(keys(page.Skills), []string{"b", "c"}) {
+			t.Errorf("expected b, c, got %v", keys(page.Skills))
+		}
+
+		if page.Next == nil || page.Next.Key != "c" || page.Next.Prev {
+			t.Errorf("expected next cursor after c, got %+v", page.Next)
+		}
+
+		if page.Prev == nil || page.Prev.Key != "b" || !page.Prev.Prev {
+			t.Errorf("expected prev cursor before b, got %+v", page.Prev)
+		}
+	})
+
+	t.Run("should keep updated time in cursor", func(t *testing.T) {
+		// Arrange
+		updatedAt := time.Date(2024, 7, 1, 10, 0, 0, 123456000, time.UTC)
+		q := ListQuery{Sort: SortByUpdatedAt, Desc: true, Limit: 1}
+
+		// Act
+		page := pageOf(q, []Skill{{Key: "go", UpdatedAt: updatedAt}, {Key: "python"}}, 2)
+
+		// Assert
+		cursor, err := decodeCursor(encodeCursor(page.Next))
+		if err != nil {
+			t.Fatalf("expected cursor to decode, got %s", err)
+		}
+
+		if !reflect.DeepEqual(cursor, &Cursor{Sort: SortByUpdatedAt, Desc: true, Value: "2024-07-01T10:00:00.123456Z", Key: "go"}) {
+			t.Errorf("expected cursor after go, got %+v", cursor)
+		}
+	})
+}
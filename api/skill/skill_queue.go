@@ -1,57 +1,161 @@
 package skill
 
 import (
-	"encoding/json"
-	"github.com/IBM/sarama"
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"skill-api-kafka-contract/message"
+	"skill-api-kafka/api"
 	"skill-api-kafka/config"
+	"skill-api-kafka/metrics"
+	"skill-api-kafka/operation"
+	"skill-api-kafka/outbox"
+	"time"
 )
 
-type SkillAction string
+type SkillAction = message.SkillAction
 
 const (
-	CreateSkillAction SkillAction = "create"
-	UpdateSkillAction SkillAction = "update"
-	DeleteSkillAction SkillAction = "delete"
-	UpdateNameAction  SkillAction = "update_name"
-	UpdateDescAction  SkillAction = "update_desc"
-	UpdateLogoAction  SkillAction = "update_logo"
-	UpdateTagsAction  SkillAction = "update_tags"
+	CreateSkillAction = message.CreateSkillAction
+	UpdateSkillAction = message.UpdateSkillAction
+	DeleteSkillAction = message.DeleteSkillAction
+	UpdateNameAction  = message.UpdateNameAction
+	UpdateDescAction  = message.UpdateDescAction
+	UpdateLogoAction  = message.UpdateLogoAction
+	UpdateTagsAction  = message.UpdateTagsAction
+
+	RestoreSkillAction = message.RestoreSkillAction
+	AddTagsAction      = message.AddTagsAction
+	RemoveTagsAction   = message.RemoveTagsAction
+)
+
+const (
+	OperationIDHeader = message.OperationIDHeader
+	PayloadVersion    = message.PayloadVersion
 )
 
-type SkillQueuePayload struct {
-	Action  SkillAction `json:"action"`
-	Key     *string     `json:"key"`
-	Payload interface{} `json:"payload"`
+type SkillQueuePayload = message.SkillQueuePayload
+
+type Outbox interface {
+	Enqueue(msg outbox.Message) error
+}
+
+type OperationStorage interface {
+	CreateOperation(op operation.Operation) error
+}
+
+// PublishStorage runs fn with an outbox and operation storage bound to a
+// single transaction, committed when fn returns nil and rolled back
+// otherwise.
+type PublishStorage interface {
+	InTx(ctx context.Context, fn func(Outbox, OperationStorage) error) error
+}
+
+type publishStorage struct {
+	db *sql.DB
+}
+
+func NewPublishStorage(db *sql.DB) publishStorage {
+	return publishStorage{db: db}
+}
+
+func (s publishStorage) InTx(ctx context.Context, fn func(Outbox, OperationStorage) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(outbox.NewTxOutboxStorage(tx), operation.NewTxOperationStorage(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 type skillQueue struct {
-	producer sarama.SyncProducer
-	config   config.KafkaConfig
+	storage    PublishStorage
+	serializer message.Serializer
+	config     config.KafkaConfig
 }
 
-func NewSkillQueue(producer sarama.SyncProducer, config config.KafkaConfig) skillQueue {
+func NewSkillQueue(storage PublishStorage, serializer message.Serializer, config config.KafkaConfig) skillQueue {
 	return skillQueue{
-		producer: producer,
-		config:   config,
+		storage:    storage,
+		serializer: serializer,
+		config:     config,
 	}
 }
 
-func (q skillQueue) PublishSkill(action SkillAction, key *string, skillPayload interface{}) error {
+// PublishSkill writes the message to the outbox table, the relay publishes it
+// to Kafka afterward. Keying by skill sends every operation on one skill to
+// the same partition, so the consumer applies them in the order they were sent.
+// The returned operation ID travels with the message so the consumer can
+// record its outcome, and the request ID in ctx so its logs can be traced
+// back to the HTTP call. A non-zero expectedVersion makes the consumer reject
+// the change once the skill is at another version.
+func (q skillQueue) PublishSkill(ctx context.Context, action SkillAction, key *string, expectedVersion int64, skillPayload interface{}) (id string, err error) {
+	defer func() {
+		metrics.SkillPublished.WithLabelValues(string(action), metrics.Result(err)).Inc()
+	}()
+
 	payload := SkillQueuePayload{
-		Action:  action,
-		Key:     key,
-		Payload: skillPayload,
+		Version:         PayloadVersion,
+		MessageID:       uuid.NewString(),
+		Timestamp:       time.Now().UTC(),
+		Producer:        q.config.ProducerID,
+		Actor:           api.ActorFrom(ctx),
+		ExpectedVersion: expectedVersion,
+		Action:          action,
+		Key:             key,
+		Payload:         skillPayload,
 	}
 
-	message, err := json.Marshal(payload)
+	value, err := q.serializer.Marshal(payload)
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	requestID := api.RequestIDFrom(ctx)
+	op := operation.Operation{
+		ID:        uuid.NewString(),
+		Action:    string(action),
+		RequestID: requestID,
+		BatchID:   operation.BatchIDFrom(ctx),
+	}
+	if key != nil {
+		op.SkillKey = *key
+	}
+
+	headers := map[string]string{
+		OperationIDHeader:         op.ID,
+		message.ContentTypeHeader: q.serializer.ContentType(),
 	}
+	if requestID != "" {
+		headers[message.RequestIDHeader] = requestID
+	}
+	// The trace context is stored with the message so the relay, which sends
+	// it later from its own goroutine, can continue the request's trace.
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(headers))
+
+	// The operation and its message are written together, an operation
+	// without a message would stay pending forever.
+	err = q.storage.InTx(ctx, func(outboxes Outbox, operations OperationStorage) error {
+		if err := operations.CreateOperation(op); err != nil {
+			return err
+		}
 
-	_, _, err = q.producer.SendMessage(&sarama.ProducerMessage{
-		Topic: q.config.SkillTopic,
-		Value: sarama.StringEncoder(message),
+		return outboxes.Enqueue(outbox.Message{
+			Topic:   q.config.SkillTopic,
+			Key:     key,
+			Value:   value,
+			Headers: headers,
+		})
 	})
+	if err != nil {
+		return "", err
+	}
 
-	return err
+	return op.ID, nil
 }
@@ -0,0 +1,281 @@
+package skill
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"skill-api-kafka-contract/message"
+	"skill-api-kafka/api"
+	"skill-api-kafka/config"
+	"skill-api-kafka/metrics"
+	"skill-api-kafka/operation"
+	"skill-api-kafka/outbox"
+	"testing"
+)
+
+type mockOperationStorage struct {
+	operations []operation.Operation
+	err        error
+}
+
+func (m *mockOperationStorage) CreateOperation(op operation.Operation) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.operations = append(m.operations, op)
+	return nil
+}
+
+type mockOutbox struct {
+	messages []outbox.Message
+	err      error
+}
+
+func (m *mockOutbox) Enqueue(msg outbox.Message) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// mockPublishStorage keeps what fn wrote only when it returns nil, like a
+// transaction would.
+type mockPublishStorage struct {
+	outbox     *mockOutbox
+	operations *mockOperationStorage
+}
+
+func (m *mockPublishStorage) InTx(ctx context.Context, fn func(Outbox, OperationStorage) error) error {
+	o := &mockOutbox{err: m.outbox.err}
+	ops := &mockOperationStorage{err: m.operations.err}
+	if err := fn(o, ops); err != nil {
+		return err
+	}
+
+	m.outbox.messages = append(m.outbox.messages, o.messages...)
+	m.operations.operations = append(m.operations.operations, ops.operations...)
+	return nil
+}
+
+func TestPublishSkill(t *testing.T) {
+	t.Run("should write message keyed by skill key to outbox", func(t *testing.T) {
+		// Arrange
+		o := &mockOutbox{}
+		ops := &mockOperationStorage{}
+		q := NewSkillQueue(&mockPublishStorage{outbox: o, operations: ops}, message.JSONSerializer{}, config.KafkaConfig{SkillTopic: "skill_topic", ProducerID: "skill-api/test"})
+		key := "python"
+
+		// Act
+		id, err := q.PublishSkill(context.Background(), UpdateNameAction, &key, 3, UpdateSkillNameRequest{Name: "Python"})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if len(ops.operations) != 1 || ops.operations[0].ID != id || ops.operations[0].SkillKey != "python" {
+			t.Errorf("expected operation %s to be created for python, got %v", id, ops.operations)
+		}
+
+		if len(o.messages) != 1 {
+			t.Fatalf("expected 1 message, got %d", len(o.messages))
+		}
+
+		msg := o.messages[0]
+		if msg.Topic != "skill_topic" {
+			t.Errorf("expected topic skill_topic, got %s", msg.Topic)
+		}
+
+		if msg.Key == nil || *msg.Key != "python" {
+			t.Errorf("expected key python, got %v", msg.Key)
+		}
+
+		var payload SkillQueuePayload
+		if err := json.Unmarshal(msg.Value, &payload); err != nil {
+			t.Fatalf("expected json value, got %s", err)
+		}
+
+		if payload.Version != PayloadVersion || payload.MessageID == "" || payload.Timestamp.IsZero() || payload.Producer != "skill-api/test" {
+			t.Errorf("expected envelope version %d with message id, timestamp and producer, got %+v", PayloadVersion, payload)
+		}
+
+		if payload.Action != UpdateNameAction || payload.Key == nil || *payload.Key != "python" {
+			t.Errorf("expected update_name for python, got %s for %v", payload.Action, payload.Key)
+		}
+
+		if payload.ExpectedVersion != 3 {
+			t.Errorf("expected version 3 to be expected, got %d", payload.ExpectedVersion)
+		}
+
+		if data, _ := json.Marshal(payload.Payload); string(data) != `{"name":"Python"}` {
+			t.Errorf("expected payload %s, got %s", `{"name":"Python"}`, data)
+		}
+
+		if msg.Headers[message.ContentTypeHeader] != message.ContentTypeJSON {
+			t.Errorf("expected content type %s, got %s", message.ContentTypeJSON, msg.Headers[message.ContentTypeHeader])
+		}
+
+		if msg.Headers[OperationIDHeader] != id {
+			t.Errorf("expected operation id header %s, got %s", id, msg.Headers[OperationIDHeader])
+		}
+	})
+
+	t.Run("should attach request id to message and operation", func(t *testing.T) {
+		// Arrange
+		o := &mockOutbox{}
+		ops := &mockOperationStorage{}
+		q := NewSkillQueue(&mockPublishStorage{outbox: o, operations: ops}, message.JSONSerializer{}, config.KafkaConfig{SkillTopic: "skill_topic"})
+		key := "python"
+		ctx := api.WithRequestID(context.Background(), "req-1")
+
+		// Act
+		_, err := q.PublishSkill(ctx, DeleteSkillAction, &key, 0, nil)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if o.messages[0].Headers[message.RequestIDHeader] != "req-1" {
+			t.Errorf("expected request id header req-1, got %q", o.messages[0].Headers[message.RequestIDHeader])
+		}
+
+		if ops.operations[0].RequestID != "req-1" {
+			t.Errorf("expected operation request id req-1, got %q", ops.operations[0].RequestID)
+		}
+	})
+
+	t.Run("should record batch of the import on operation", func(t *testing.T) {
+		// Arrange
+		ops := &mockOperationStorage{}
+		q := NewSkillQueue(&mockPublishStorage{outbox: &mockOutbox{}, operations: ops}, message.JSONSerializer{}, config.KafkaConfig{SkillTopic: "skill_topic"})
+		key := "python"
+		ctx := operation.WithBatchID(context.Background(), "batch-1")
+
+		// Act
+		_, err := q.PublishSkill(ctx, DeleteSkillAction, &key, 0, nil)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if ops.operations[0].BatchID != "batch-1" {
+			t.Errorf("expected operation batch id batch-1, got %q", ops.operations[0].BatchID)
+		}
+	})
+
+	t.Run("should stamp actor of the request on message", func(t *testing.T) {
+		// Arrange
+		o := &mockOutbox{}
+		q := NewSkillQueue(&mockPublishStorage{outbox: o, operations: &mockOperationStorage{}}, message.JSONSerializer{}, config.KafkaConfig{SkillTopic: "skill_topic"})
+		key := "python"
+		ctx := api.WithActor(context.Background(), "alice")
+
+		// Act
+		_, err := q.PublishSkill(ctx, DeleteSkillAction, &key, 0, nil)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		got, err := message.Decode(o.messages[0].Value)
+		if err != nil {
+			t.Fatalf("expected message to decode, got %s", err)
+		}
+
+		if got.Actor != "alice" {
+			t.Errorf("expected actor alice, got %q", got.Actor)
+		}
+	})
+
+	t.Run("should attach trace context of the request to message", func(t *testing.T) {
+		// Arrange
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		t.Cleanup(func() { otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator()) })
+
+		o := &mockOutbox{}
+		q := NewSkillQueue(&mockPublishStorage{outbox: o, operations: &mockOperationStorage{}}, message.JSONSerializer{}, config.KafkaConfig{SkillTopic: "skill_topic"})
+		key := "python"
+		ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "DELETE /api/v1/skills/:key")
+		defer span.End()
+
+		// Act
+		_, err := q.PublishSkill(ctx, DeleteSkillAction, &key, 0, nil)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		want := "00-" + span.SpanContext().TraceID().String() + "-" + span.SpanContext().SpanID().String() + "-01"
+		if got := o.messages[0].Headers["traceparent"]; got != want {
+			t.Errorf("expected traceparent %s, got %s", want, got)
+		}
+	})
+
+	t.Run("should count published message by action", func(t *testing.T) {
+		// Arrange
+		q := NewSkillQueue(&mockPublishStorage{outbox: &mockOutbox{}, operations: &mockOperationStorage{}}, message.JSONSerializer{}, config.KafkaConfig{SkillTopic: "skill_topic"})
+		key := "python"
+		before := testutil.ToFloat64(metrics.SkillPublished.WithLabelValues(string(DeleteSkillAction), metrics.ResultSuccess))
+
+		// Act
+		_, err := q.PublishSkill(context.Background(), DeleteSkillAction, &key, 0, nil)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if got := testutil.ToFloat64(metrics.SkillPublished.WithLabelValues(string(DeleteSkillAction), metrics.ResultSuccess)) - before; got != 1 {
+			t.Errorf("expected 1 successful publish, got %v", got)
+		}
+	})
+
+	t.Run("should not write to outbox when operation cannot be created", func(t *testing.T) {
+		// Arrange
+		o := &mockOutbox{}
+		ops := &mockOperationStorage{err: errors.New("error")}
+		q := NewSkillQueue(&mockPublishStorage{outbox: o, operations: ops}, message.JSONSerializer{}, config.KafkaConfig{SkillTopic: "skill_topic"})
+		key := "python"
+
+		// Act
+		_, err := q.PublishSkill(context.Background(), DeleteSkillAction, &key, 0, nil)
+
+		// Assert
+		if err == nil {
+			t.Error("expected error to be not nil")
+		}
+
+		if len(o.messages) != 0 {
+			t.Errorf("expected no message in outbox, got %d", len(o.messages))
+		}
+	})
+
+	t.Run("should not leave operation pending when outbox write fails", func(t *testing.T) {
+		// Arrange
+		o := &mockOutbox{err: errors.New("error")}
+		ops := &mockOperationStorage{}
+		q := NewSkillQueue(&mockPublishStorage{outbox: o, operations: ops}, message.JSONSerializer{}, config.KafkaConfig{SkillTopic: "skill_topic"})
+		key := "python"
+
+		// Act
+		_, err := q.PublishSkill(context.Background(), DeleteSkillAction, &key, 0, nil)
+
+		// Assert
+		if err == nil {
+			t.Error("expected error to be not nil")
+		}
+
+		if len(ops.operations) != 0 {
+			t.Errorf("expected operation to be rolled back, got %d", len(ops.operations))
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package skill
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+var (
+	errMissingSearchQuery = errors.New("q is required")
+	errInvalidSearchLimit = errors.New("limit must be between 1 and 100")
+)
+
+// SearchResult is a skill matching a full text search. Snippet is the HTML
+// escaped text around the matched words, marked with <mark> tags, so it can
+// be rendered as is.
+type SearchResult struct {
+	Skill
+	Rank    float64
+	Snippet string
+}
+
+type ResponseSearchResult struct {
+	ResponseSkill
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+// searchQuery reads ?q=, in the web search syntax Postgres understands
+// (quoted phrases, or, -word), and ?limit=.
+func searchQuery(c *gin.Context) (q string, limit int, err error) {
+	q = strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		return "", 0, errMissingSearchQuery
+	}
+
+	limit = defaultSearchLimit
+	if v := c.Query("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxSearchLimit {
+			return "", 0, errInvalidSearchLimit
+		}
+	}
+
+	return q, limit, nil
+}
+
+func responseSearchResults(results []SearchResult) []ResponseSearchResult {
+	res := make([]ResponseSearchResult, 0, len(results))
+	for _, result := range results {
+		res = append(res, ResponseSearchResult{
+			ResponseSkill: ResponseSkill{
+				Key:         result.Key,
+				Name:        result.Name,
+				Description: result.Description,
+				Logo:        result.Logo,
+				Tags:        result.Tags,
+			},
+			Rank:    result.Rank,
+			Snippet: result.Snippet,
+		})
+	}
+	return res
+}
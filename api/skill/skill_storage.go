@@ -1,8 +1,18 @@
 package skill
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"skill-api-kafka/tracing"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Skill struct {
@@ -11,6 +21,9 @@ type Skill struct {
 	Description string
 	Logo        string
 	Tags        pq.StringArray
+	Version     int64
+	UpdatedAt   time.Time
+	DeletedAt   *time.Time
 }
 
 type skillStorage struct {
@@ -21,31 +34,267 @@ func NewSkillStorage(db *sql.DB) skillStorage {
 	return skillStorage{db: db}
 }
 
-func (s skillStorage) GetSkill(key string) (*Skill, error) {
+// GetSkill returns the skill with key unless it is deleted.
+func (s skillStorage) GetSkill(ctx context.Context, key string) (*Skill, error) {
+	return s.getSkill(ctx, "SkillStorage.GetSkill", key, false)
+}
+
+// GetDeletedSkill returns the skill with key only while it is in the trash.
+func (s skillStorage) GetDeletedSkill(ctx context.Context, key string) (*Skill, error) {
+	return s.getSkill(ctx, "SkillStorage.GetDeletedSkill", key, true)
+}
+
+func (s skillStorage) getSkill(ctx context.Context, name string, key string, deleted bool) (*Skill, error) {
+	ctx, span := startQuerySpan(ctx, name)
+	defer span.End()
+	span.SetAttributes(attribute.String("skill.key", key))
+
 	var skill Skill
-	result := s.db.QueryRow("SELECT key,name,description,logo,tags from skill where key = $1", key)
-	err := result.Scan(&skill.Key, &skill.Name, &skill.Description, &skill.Logo, &skill.Tags)
+	result := s.db.QueryRowContext(ctx, "SELECT key,name,description,logo,tags,version,updated_at,deleted_at from skill where key = $1 AND (deleted_at IS NOT NULL) = $2", key, deleted)
+	err := result.Scan(&skill.Key, &skill.Name, &skill.Description, &skill.Logo, &skill.Tags, &skill.Version, &skill.UpdatedAt, &skill.DeletedAt)
 	if err != nil {
+		// A missing skill is an answer, not a failure of the query.
+		if !errors.Is(err, sql.ErrNoRows) {
+			span.SetStatus(codes.Error, err.Error())
+		}
 		return nil, err
 	}
 
 	return &skill, nil
 }
 
-func (s skillStorage) GetSkills() ([]Skill, error) {
-	skills := make([]Skill, 0)
-	result, err := s.db.Query("SELECT key,name,description,logo,tags from skill")
+// ExistingKeys returns which of keys are taken by a skill, mapped to whether
+// that skill is deleted.
+func (s skillStorage) ExistingKeys(ctx context.Context, keys []string) (map[string]bool, error) {
+	ctx, span := startQuerySpan(ctx, "SkillStorage.ExistingKeys")
+	defer span.End()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT key, deleted_at IS NOT NULL FROM skill WHERE key = ANY($1)", pq.Array(keys))
 	if err != nil {
-		return make([]Skill, 0), err
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
 	}
-	for result.Next() {
+	defer rows.Close()
+
+	existing := map[string]bool{}
+	for rows.Next() {
+		var key string
+		var deleted bool
+		if err := rows.Scan(&key, &deleted); err != nil {
+			return nil, err
+		}
+		existing[key] = deleted
+	}
+
+	return existing, rows.Err()
+}
+
+// ListSkills returns the page of skills q asks for, live ones or those in
+// the trash, along with how many skills match its filters in total.
+func (s skillStorage) ListSkills(ctx context.Context, q ListQuery) (SkillPage, error) {
+	ctx, span := startQuerySpan(ctx, "SkillStorage.ListSkills")
+	defer span.End()
+
+	where, args := listFilter(q)
+
+	var total int
+	err := s.db.QueryRowContext(ctx, "SELECT count(*) FROM skill WHERE "+where, args...).Scan(&total)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return SkillPage{}, err
+	}
+
+	qry, args := listPage(q, where, args)
+	rows, err := s.db.QueryContext(ctx, qry, args...)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return SkillPage{}, err
+	}
+	defer rows.Close()
+
+	skills := make([]Skill, 0)
+	for rows.Next() {
 		var skill Skill
-		err := result.Scan(&skill.Key, &skill.Name, &skill.Description, &skill.Logo, &skill.Tags)
+		err := rows.Scan(&skill.Key, &skill.Name, &skill.Description, &skill.Logo, &skill.Tags, &skill.Version, &skill.UpdatedAt, &skill.DeletedAt)
 		if err != nil {
-			return make([]Skill, 0), err
+			return SkillPage{}, err
 		}
 		skills = append(skills, skill)
 	}
 
-	return skills, nil
+	if err := rows.Err(); err != nil {
+		return SkillPage{}, err
+	}
+	return pageOf(q, skills, total), nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// listFilter is the WHERE clause selecting the skills q matches, whatever
+// page they fall on.
+func listFilter(q ListQuery) (string, []any) {
+	args := []any{q.Deleted}
+	conds := []string{"(deleted_at IS NOT NULL) = $1"}
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if len(q.Tags) > 0 {
+		op := "&&"
+		if q.AllTags {
+			op = "@>"
+		}
+		conds = append(conds, "tags "+op+" "+arg(pq.Array(q.Tags)))
+	}
+
+	if q.KeyPrefix != "" {
+		conds = append(conds, "key LIKE "+arg(likeEscaper.Replace(q.KeyPrefix)+"%"))
+	}
+
+	if q.Name != "" {
+		conds = append(conds, "name ILIKE "+arg("%"+likeEscaper.Replace(q.Name)+"%"))
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+// listPage narrows where down to the page after, or before, q's cursor. The
+// key breaks ties between skills sharing a name or an update time. Rows come
+// in the direction of travel, with one more than the limit to tell whether
+// the listing goes on.
+func listPage(q ListQuery, where string, args []any) (string, []any) {
+	backward := q.Cursor != nil && q.Cursor.Prev
+
+	op, dir := ">", "ASC"
+	if q.Desc != backward {
+		op, dir = "<", "DESC"
+	}
+
+	order := "key " + dir
+	if q.Sort != SortByKey {
+		order = q.Sort + " " + dir + ", " + order
+	}
+
+	if q.Cursor != nil {
+		args = append(args, q.Cursor.Key)
+		cond := "key " + op + " $" + strconv.Itoa(len(args))
+		if q.Sort != SortByKey {
+			var value any = q.Cursor.Value
+			if q.Sort == SortByUpdatedAt {
+				// decodeCursor has already checked the time parses.
+				value, _ = time.Parse(time.RFC3339Nano, q.Cursor.Value)
+			}
+			args = append(args, value)
+			cond = "(" + q.Sort + ", key) " + op + " ($" + strconv.Itoa(len(args)) + ", $" + strconv.Itoa(len(args)-1) + ")"
+		}
+		where += " AND " + cond
+	}
+
+	args = append(args, q.Limit+1)
+	qry := "SELECT key,name,description,logo,tags,version,updated_at,deleted_at FROM skill WHERE " + where +
+		" ORDER BY " + order + " LIMIT $" + strconv.Itoa(len(args))
+	return qry, args
+}
+
+// snippetSource is the text of a skill a search snippet is cut from. It is
+// HTML escaped first, so the only markup in a snippet is the <mark> tags
+// ts_headline adds around matches.
+const snippetSource = `replace(replace(replace(replace(replace(name || ' ' || description || ' ' || array_to_string(tags, ' '),
+'&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;')`
+
+// SearchSkills returns up to limit live skills matching the web search query
+// q, most relevant first. Ranking follows the weights the consumer gives to
+// name, description and tags in the search column.
+func (s skillStorage) SearchSkills(ctx context.Context, q string, limit int) ([]SearchResult, error) {
+	ctx, span := startQuerySpan(ctx, "SkillStorage.SearchSkills")
+	defer span.End()
+
+	qry := `SELECT key,name,description,logo,tags,ts_rank(search, query) AS rank,
+ts_headline('english', ` + snippetSource + `, query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2')
+FROM skill, websearch_to_tsquery('english', $1) query
+WHERE deleted_at IS NULL AND search @@ query ORDER BY rank DESC, key LIMIT $2`
+	rows, err := s.db.QueryContext(ctx, qry, q, limit)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]SearchResult, 0)
+	for rows.Next() {
+		var result SearchResult
+		err := rows.Scan(&result.Key, &result.Name, &result.Description, &result.Logo, &result.Tags, &result.Rank, &result.Snippet)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// GetTags returns every distinct tag of live skills with the number of
+// skills carrying it, most used first. The counts come from skill_tag, which
+// the consumer keeps up to date as it writes tags, so the skills themselves
+// are not scanned.
+func (s skillStorage) GetTags(ctx context.Context) ([]TagCount, error) {
+	ctx, span := startQuerySpan(ctx, "SkillStorage.GetTags")
+	defer span.End()
+
+	qry := `SELECT tag, count FROM skill_tag WHERE count > 0 ORDER BY count DESC, tag`
+	rows, err := s.db.QueryContext(ctx, qry)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make([]TagCount, 0)
+	for rows.Next() {
+		var tag TagCount
+		if err := rows.Scan(&tag.Tag, &tag.Count); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// GetSkillHistory returns up to limit changes of the skill with key older
+// than the entry with id before, newest first. A zero before starts from the
+// newest change.
+func (s skillStorage) GetSkillHistory(ctx context.Context, key string, before int64, limit int) ([]HistoryEntry, error) {
+	ctx, span := startQuerySpan(ctx, "SkillStorage.GetSkillHistory")
+	defer span.End()
+	span.SetAttributes(attribute.String("skill.key", key))
+
+	qry := `SELECT id,action,before,after,actor,message_id,occurred_at FROM skill_history
+WHERE skill_key = $1 AND ($2::bigint = 0 OR id < $2) ORDER BY id DESC LIMIT $3`
+	rows, err := s.db.QueryContext(ctx, qry, key, before, limit)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]HistoryEntry, 0)
+	for rows.Next() {
+		var entry HistoryEntry
+		err := rows.Scan(&entry.ID, &entry.Action, &entry.Before, &entry.After, &entry.Actor, &entry.MessageID, &entry.OccurredAt)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func startQuerySpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracing.Tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL),
+	)
 }
@@ -0,0 +1,20 @@
+package skill
+
+// TagCount is a tag and how many live skills carry it.
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+type ResponseTag struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+func responseTags(tags []TagCount) []ResponseTag {
+	res := make([]ResponseTag, 0, len(tags))
+	for _, tag := range tags {
+		res = append(res, ResponseTag{Tag: tag.Tag, Count: tag.Count})
+	}
+	return res
+}
@@ -0,0 +1,74 @@
+package skill
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"skill-api-kafka/api"
+	"strconv"
+	"strings"
+)
+
+var errInvalidIfMatch = errors.New("invalid If-Match header")
+
+// etag is the entity tag of a skill at version. It is strong since any
+// change bumps the version.
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ifMatchVersion reads the version the caller expects from If-Match. A "*"
+// accepts any version and is returned as zero, like a message without an
+// expected version.
+func ifMatchVersion(v string) (int64, error) {
+	v = strings.TrimSpace(v)
+	if v == "*" {
+		return 0, nil
+	}
+
+	unquoted, err := strconv.Unquote(v)
+	if err != nil || !strings.HasPrefix(v, `"`) {
+		return 0, errInvalidIfMatch
+	}
+
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version < 1 {
+		return 0, errInvalidIfMatch
+	}
+	return version, nil
+}
+
+// matchVersion makes writes conditional on the version of the skill the
+// caller last read. It responds and returns false when If-Match is missing,
+// malformed or no longer matches skill. The version is checked again by the
+// consumer, since the skill can still change before the message is applied.
+func matchVersion(c *gin.Context, skill *Skill) (int64, bool) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		c.JSON(http.StatusPreconditionRequired, api.ErrorResponse("If-Match header is required"))
+		return 0, false
+	}
+
+	expected, err := ifMatchVersion(header)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.ErrorResponse(err.Error()))
+		return 0, false
+	}
+
+	if expected != 0 && expected != skill.Version {
+		c.Header("ETag", etag(skill.Version))
+		c.JSON(http.StatusPreconditionFailed, api.ErrorResponse("skill has been modified"))
+		return 0, false
+	}
+
+	return expected, true
+}
+
+// matchOptionalVersion is matchVersion for writes that merge with the skill
+// rather than replace part of it, where a missing If-Match means any version.
+func matchOptionalVersion(c *gin.Context, skill *Skill) (int64, bool) {
+	if c.GetHeader("If-Match") == "" {
+		return 0, true
+	}
+	return matchVersion(c, skill)
+}
@@ -0,0 +1,48 @@
+package skill
+
+import (
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxWait = 30 * time.Second
+
+// waitDuration reads how long the caller is willing to wait for the outcome
+// of a write, from either ?wait=5s or a "Prefer: wait=5" header (RFC 7240,
+// in seconds). It returns zero when the caller did not ask to wait.
+func waitDuration(c *gin.Context) time.Duration {
+	if v := c.Query("wait"); v != "" {
+		return parseWait(v)
+	}
+
+	for _, pref := range strings.Split(c.GetHeader("Prefer"), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pref), "=")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "wait") {
+			return parseWait(strings.TrimSpace(value))
+		}
+	}
+
+	return 0
+}
+
+func parseWait(v string) time.Duration {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		seconds, err := strconv.Atoi(v)
+		if err != nil {
+			return 0
+		}
+		d = time.Duration(seconds) * time.Second
+	}
+
+	if d < 0 {
+		return 0
+	}
+
+	if d > maxWait {
+		return maxWait
+	}
+	return d
+}
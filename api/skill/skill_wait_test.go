@@ -0,0 +1,41 @@
+package skill
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWaitDuration(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		prefer string
+		want   time.Duration
+	}{
+		{name: "no wait", url: "/skills", want: 0},
+		{name: "query duration", url: "/skills?wait=5s", want: 5 * time.Second},
+		{name: "query seconds", url: "/skills?wait=3", want: 3 * time.Second},
+		{name: "prefer header", url: "/skills", prefer: "wait=5", want: 5 * time.Second},
+		{name: "prefer header with other preferences", url: "/skills", prefer: "respond-async, wait=2", want: 2 * time.Second},
+		{name: "capped", url: "/skills?wait=10m", want: maxWait},
+		{name: "invalid", url: "/skills?wait=soon", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, tt.url, nil)
+			if tt.prefer != "" {
+				c.Request.Header.Set("Prefer", tt.prefer)
+			}
+
+			if got := waitDuration(c); got != tt.want {
+				t.Errorf("waitDuration() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
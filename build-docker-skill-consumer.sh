@@ -1,4 +1,4 @@
-docker build -t $SKILL_CONSUMER_REGISTRIES:$CI_COMMIT_SHORT_SHA -f  ./consumer/Dockerfile ./consumer
+docker build -t $SKILL_CONSUMER_REGISTRIES:$CI_COMMIT_SHORT_SHA -f ./consumer/Dockerfile .
 docker tag $SKILL_CONSUMER_REGISTRIES:$CI_COMMIT_SHORT_SHA $SKILL_CONSUMER_REGISTRIES:latest
 docker login $REGISTRIES -u $CI_REGISTRY_USER -p $CI_REGISTRY_PASSWORD
 docker image push --all-tags $SKILL_CONSUMER_REGISTRIES
\ No newline at end of file
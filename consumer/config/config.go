@@ -3,19 +3,70 @@ package config
 import (
 	"log"
 	"os"
+	"skill-api-kafka-contract/telemetry"
+	"strconv"
+	"time"
+)
+
+const (
+	OffsetResetEarliest  = "earliest"
+	OffsetResetLatest    = "latest"
+	OffsetResetTimestamp = "timestamp"
+)
+
+const (
+	TracingExporterNone   = telemetry.ExporterNone
+	TracingExporterStdout = telemetry.ExporterStdout
+	TracingExporterOTLP   = telemetry.ExporterOTLP
 )
 
 type Config struct {
-	PostgresURI string
-	Port        string
-	Kafka       KafkaConfig
+	PostgresURI       string
+	Port              string
+	SchemaRegistryDir string
+	Kafka             KafkaConfig
+	Retry             RetryConfig
+	Purge             PurgeConfig
+	Outbox            OutboxConfig
+	Tracing           TracingConfig
 }
 
 type KafkaConfig struct {
-	KafkaConsumer string
-	SkillTopic    string
+	KafkaConsumer   string
+	SkillTopic      string
+	ConsumerGroup   string
+	DeadLetterTopic string
+	ResultTopic     string
+	EventTopic      string
+	OffsetReset     string
+	OffsetTimestamp time.Time
 }
 
+type RetryConfig struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// PurgeConfig sets how long deleted skills stay restorable. A zero Retention
+// keeps them forever.
+type PurgeConfig struct {
+	Interval  time.Duration
+	Retention time.Duration
+}
+
+// OutboxConfig sets how often skill events are relayed to Kafka, and how
+// long sent ones are kept. A zero Retention keeps them forever.
+type OutboxConfig struct {
+	PollInterval  time.Duration
+	BatchSize     int
+	SweepInterval time.Duration
+	Retention     time.Duration
+}
+
+// TracingConfig is the telemetry config both services are set up with.
+type TracingConfig = telemetry.Config
+
 func Configuration() Config {
 	if os.Getenv("PORT") == "" {
 		log.Fatal("PORT is not set")
@@ -33,12 +84,168 @@ func Configuration() Config {
 		log.Fatal("KAFKA_SKILL_TOPIC is not set")
 	}
 
+	if os.Getenv("KAFKA_CONSUMER_GROUP") == "" {
+		log.Fatal("KAFKA_CONSUMER_GROUP is not set")
+	}
+
+	deadLetterTopic := os.Getenv("KAFKA_DEAD_LETTER_TOPIC")
+	if deadLetterTopic == "" {
+		deadLetterTopic = os.Getenv("KAFKA_SKILL_TOPIC") + "_dlq"
+	}
+
+	resultTopic := os.Getenv("KAFKA_RESULT_TOPIC")
+	if resultTopic == "" {
+		resultTopic = os.Getenv("KAFKA_SKILL_TOPIC") + "_result"
+	}
+
+	eventTopic := os.Getenv("KAFKA_EVENT_TOPIC")
+	if eventTopic == "" {
+		eventTopic = os.Getenv("KAFKA_SKILL_TOPIC") + "_event"
+	}
+
+	// The reset policy only applies to partitions the group has never
+	// committed an offset for, once committed the group resumes from there.
+	offsetReset := os.Getenv("KAFKA_CONSUMER_OFFSET_RESET")
+	if offsetReset == "" {
+		offsetReset = OffsetResetLatest
+	}
+
+	var offsetTimestamp time.Time
+	switch offsetReset {
+	case OffsetResetEarliest, OffsetResetLatest:
+	case OffsetResetTimestamp:
+		ts, err := time.Parse(time.RFC3339, os.Getenv("KAFKA_CONSUMER_OFFSET_TIMESTAMP"))
+		if err != nil {
+			log.Fatal("KAFKA_CONSUMER_OFFSET_TIMESTAMP must be an RFC3339 timestamp")
+		}
+		offsetTimestamp = ts
+	default:
+		log.Fatal("KAFKA_CONSUMER_OFFSET_RESET must be one of earliest, latest or timestamp")
+	}
+
+	retry := RetryConfig{
+		MaxAttempts:    5,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+	}
+
+	if v := os.Getenv("RETRY_MAX_ATTEMPTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			log.Fatal("RETRY_MAX_ATTEMPTS must be a positive number")
+		}
+		retry.MaxAttempts = n
+	}
+
+	if v := os.Getenv("RETRY_INITIAL_BACKOFF"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatal("RETRY_INITIAL_BACKOFF must be a positive duration")
+		}
+		retry.InitialBackoff = d
+	}
+
+	if v := os.Getenv("RETRY_MAX_BACKOFF"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < retry.InitialBackoff {
+			log.Fatal("RETRY_MAX_BACKOFF must be a duration no shorter than RETRY_INITIAL_BACKOFF")
+		}
+		retry.MaxBackoff = d
+	}
+
+	purge := PurgeConfig{
+		Interval:  time.Hour,
+		Retention: 30 * 24 * time.Hour,
+	}
+
+	if v := os.Getenv("PURGE_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatal("PURGE_INTERVAL must be a positive duration")
+		}
+		purge.Interval = d
+	}
+
+	if v := os.Getenv("PURGE_RETENTION"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			log.Fatal("PURGE_RETENTION must be a duration, 0 disables purging")
+		}
+		purge.Retention = d
+	}
+
+	outbox := OutboxConfig{
+		PollInterval:  500 * time.Millisecond,
+		BatchSize:     100,
+		SweepInterval: time.Hour,
+		Retention:     7 * 24 * time.Hour,
+	}
+
+	if v := os.Getenv("OUTBOX_POLL_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatal("OUTBOX_POLL_INTERVAL must be a positive duration")
+		}
+		outbox.PollInterval = d
+	}
+
+	if v := os.Getenv("OUTBOX_BATCH_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			log.Fatal("OUTBOX_BATCH_SIZE must be a positive number")
+		}
+		outbox.BatchSize = n
+	}
+
+	if v := os.Getenv("OUTBOX_SWEEP_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatal("OUTBOX_SWEEP_INTERVAL must be a positive duration")
+		}
+		outbox.SweepInterval = d
+	}
+
+	if v := os.Getenv("OUTBOX_RETENTION"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			log.Fatal("OUTBOX_RETENTION must be a duration, 0 keeps sent events")
+		}
+		outbox.Retention = d
+	}
+
+	// TRACING_FILE only applies to the stdout exporter, spans are written to
+	// stdout when it is empty.
+	tracing := TracingConfig{
+		Exporter: os.Getenv("TRACING_EXPORTER"),
+		File:     os.Getenv("TRACING_FILE"),
+	}
+	if tracing.Exporter == "" {
+		tracing.Exporter = TracingExporterNone
+	}
+
+	switch tracing.Exporter {
+	case TracingExporterNone, TracingExporterStdout, TracingExporterOTLP:
+	default:
+		log.Fatal("TRACING_EXPORTER must be one of none, stdout or otlp")
+	}
+
 	return Config{
-		PostgresURI: os.Getenv("POSTGRES_URI"),
-		Port:        os.Getenv("PORT"),
+		PostgresURI:       os.Getenv("POSTGRES_URI"),
+		Port:              os.Getenv("PORT"),
+		SchemaRegistryDir: os.Getenv("SCHEMA_REGISTRY_DIR"),
+		Retry:             retry,
+		Purge:             purge,
+		Outbox:            outbox,
+		Tracing:           tracing,
 		Kafka: KafkaConfig{
-			KafkaConsumer: os.Getenv("KAFKA_CONSUMER"),
-			SkillTopic:    os.Getenv("KAFKA_SKILL_TOPIC"),
+			KafkaConsumer:   os.Getenv("KAFKA_CONSUMER"),
+			SkillTopic:      os.Getenv("KAFKA_SKILL_TOPIC"),
+			ConsumerGroup:   os.Getenv("KAFKA_CONSUMER_GROUP"),
+			DeadLetterTopic: deadLetterTopic,
+			ResultTopic:     resultTopic,
+			EventTopic:      eventTopic,
+			OffsetReset:     offsetReset,
+			OffsetTimestamp: offsetTimestamp,
 		},
 	}
 }
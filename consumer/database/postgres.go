@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"net/url"
+	"strings"
 
 	_ "github.com/lib/pq"
 )
@@ -13,6 +15,29 @@ func Postgres(uri string) *sql.DB {
 	if err != nil {
 		log.Fatal("Fail to Connect to Database")
 	}
+
+	if err := db.Ping(); err != nil {
+		log.Fatal("Fail to Ping Database")
+	}
 	fmt.Println("Connected to Postgres")
 	return db
 }
+
+// WithSearchPath makes every connection opened with uri resolve unqualified
+// tables in schema first. It accepts both URL and key=value connection
+// strings, schema must be a plain identifier.
+func WithSearchPath(uri string, schema string) (string, error) {
+	if !strings.HasPrefix(uri, "postgres://") && !strings.HasPrefix(uri, "postgresql://") {
+		return uri + " search_path=" + schema, nil
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
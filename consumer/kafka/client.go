@@ -0,0 +1,21 @@
+package kafka
+
+import (
+	"github.com/IBM/sarama"
+	"log"
+	"skill-api-kafka-consumer/config"
+	"strings"
+)
+
+func Client(c config.KafkaConfig) (sarama.Client, func()) {
+	client, err := sarama.NewClient(strings.Split(c.KafkaConsumer, ","), sarama.NewConfig())
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	return client, func() {
+		if err := client.Close(); err != nil {
+			log.Fatalln(err)
+		}
+	}
+}
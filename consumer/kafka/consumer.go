@@ -2,74 +2,330 @@ package kafka
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"log"
+	"skill-api-kafka-consumer/config"
+	"skill-api-kafka-consumer/metrics"
+	"skill-api-kafka-consumer/operation"
 	"skill-api-kafka-consumer/skill"
+	"skill-api-kafka-contract/message"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
+var ErrNotAssigned = errors.New("consumer is not a member of its group")
+
 type Consumer struct {
-	broker    string
-	topic     string
-	consumer  sarama.Consumer
-	partition sarama.PartitionConsumer
+	broker          string
+	topic           string
+	groupID         string
+	offsetReset     string
+	offsetTimestamp time.Time
+	client          sarama.Client
+	group           sarama.ConsumerGroup
+	retry           config.RetryConfig
+	deadLetter      DeadLetterQueue
+	operations      []OperationRecorder
+	// claimed holds the partitions of the current session, nil while the
+	// consumer is not a member of its group.
+	claimed atomic.Pointer[map[string][]int32]
 }
 
-func NewConsumer(broker string, topic string) *Consumer {
-	consumer, err := sarama.NewConsumer([]string{broker}, sarama.NewConfig())
+// NewConsumer reports the outcome of every message carrying an operation ID
+// to each of the given recorders, in order.
+func NewConsumer(c config.KafkaConfig, retry config.RetryConfig, deadLetter DeadLetterQueue, operations ...OperationRecorder) *Consumer {
+	kafkaConfig := sarama.NewConfig()
+	kafkaConfig.Consumer.Return.Errors = true
+	kafkaConfig.Consumer.Offsets.AutoCommit.Enable = false
+	kafkaConfig.Consumer.Offsets.Initial = sarama.OffsetNewest
+	if c.OffsetReset == config.OffsetResetEarliest {
+		kafkaConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
+	}
+
+	client, err := sarama.NewClient(strings.Split(c.KafkaConsumer, ","), kafkaConfig)
 	if err != nil {
 		panic(err)
 	}
 
-	partition, err := consumer.ConsumePartition(topic, 0, sarama.OffsetNewest)
+	group, err := sarama.NewConsumerGroupFromClient(c.ConsumerGroup, client)
 	if err != nil {
 		panic(err)
 	}
 
 	return &Consumer{
-		consumer:  consumer,
-		partition: partition,
-		topic:     topic,
-		broker:    broker,
+		client:          client,
+		group:           group,
+		topic:           c.SkillTopic,
+		groupID:         c.ConsumerGroup,
+		offsetReset:     c.OffsetReset,
+		offsetTimestamp: c.OffsetTimestamp,
+		broker:          c.KafkaConsumer,
+		retry:           retry,
+		deadLetter:      deadLetter,
+		operations:      operations,
 	}
 }
 
 func (c *Consumer) Close() {
-	if err := c.consumer.Close(); err != nil {
+	if err := c.group.Close(); err != nil {
 		log.Fatal(err)
 	}
-}
 
-func (c *Consumer) ClosePartition() {
-	if err := c.partition.Close(); err != nil {
+	if err := c.client.Close(); err != nil {
 		log.Fatal(err)
 	}
 }
 
 func (c *Consumer) Run(ctx context.Context, h skill.SkillHandler) {
-	fmt.Printf("Consuming topic %s at %s.\n", c.topic, c.broker)
+	fmt.Printf("Consuming topic %s at %s with group %s.\n", c.topic, c.broker, c.groupID)
+
+	go func() {
+		for err := range c.group.Errors() {
+			log.Printf("Consumer group error: %s", err)
+		}
+	}()
 
-ConsumerLoop:
+	handler := groupHandler{handler: h, consumer: c, retry: c.retry, deadLetter: c.deadLetter, operations: c.operations}
 	for {
-		select {
-		case msg := <-c.partition.Messages():
+		// Consume blocks for the lifetime of a session and returns on every
+		// rebalance, so it has to be called again to rejoin the group.
+		if err := c.group.Consume(ctx, []string{c.topic}, handler); err != nil {
+			if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+				break
+			}
+			log.Printf("Error consuming topic: %s, error: %s", c.topic, err)
+		}
 
-			payload, err := h.ValidateSkillMessage(msg.Value)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	log.Print("Shutting down consumer...")
+}
+
+// resetToTimestamp moves every claimed partition the group has never
+// committed to the first offset produced at or after the configured time.
+// The offset is marked rather than reset, since a reset only ever moves an
+// offset back and an uncommitted partition has none yet. It is committed
+// before the claims start, so they start from it.
+func (c *Consumer) resetToTimestamp(session sarama.ConsumerGroupSession) error {
+	for topic, partitions := range session.Claims() {
+		for _, partition := range partitions {
+			committed, err := c.committedOffset(topic, partition)
 			if err != nil {
-				log.Printf("Error validating message at topic: %s, partition: %d, offset: %d, error: %s", msg.Topic, msg.Partition, msg.Offset, err)
-				continue
+				return err
 			}
 
-			if err := h.HandleSkill(payload); err != nil {
-				log.Printf("Error handling message at topic: %s, partition: %d, offset: %d, error: %s", msg.Topic, msg.Partition, msg.Offset, err)
+			if committed >= 0 {
 				continue
 			}
 
-			log.Printf("Successfully handled message at topic: %s, partition: %d, offset %d", msg.Topic, msg.Partition, msg.Offset)
+			offset, err := c.client.GetOffset(topic, partition, c.offsetTimestamp.UnixMilli())
+			if err != nil {
+				return err
+			}
+
+			if offset < 0 {
+				offset, err = c.client.GetOffset(topic, partition, sarama.OffsetNewest)
+				if err != nil {
+					return err
+				}
+			}
+
+			log.Printf("Starting topic: %s, partition: %d at offset %d from %s", topic, partition, offset, c.offsetTimestamp.Format(time.RFC3339))
+			session.MarkOffset(topic, partition, offset, "")
+		}
+	}
+
+	session.Commit()
+	return nil
+}
+
+func (c *Consumer) committedOffset(topic string, partition int32) (int64, error) {
+	coordinator, err := c.client.Coordinator(c.groupID)
+	if err != nil {
+		return 0, err
+	}
+
+	req := sarama.NewOffsetFetchRequest(c.client.Config().Version, c.groupID, map[string][]int32{topic: {partition}})
+	resp, err := coordinator.FetchOffset(req)
+	if err != nil {
+		return 0, err
+	}
+
+	block := resp.GetBlock(topic, partition)
+	if block == nil {
+		return -1, nil
+	}
+
+	if !errors.Is(block.Err, sarama.ErrNoError) {
+		return 0, block.Err
+	}
+
+	return block.Offset, nil
+}
+
+type groupHandler struct {
+	handler    skill.SkillHandler
+	consumer   *Consumer
+	retry      config.RetryConfig
+	deadLetter DeadLetterQueue
+	operations []OperationRecorder
+}
+
+func (g groupHandler) Setup(session sarama.ConsumerGroupSession) error {
+	log.Printf("Partitions assigned: %v, generation: %d", session.Claims(), session.GenerationID())
+
+	if g.consumer.offsetReset == config.OffsetResetTimestamp {
+		if err := g.consumer.resetToTimestamp(session); err != nil {
+			return err
+		}
+	}
+
+	claims := session.Claims()
+	g.consumer.claimed.Store(&claims)
+	return nil
+}
+
+func (g groupHandler) Cleanup(session sarama.ConsumerGroupSession) error {
+	log.Printf("Partitions revoked: %v, generation: %d", session.Claims(), session.GenerationID())
+	g.consumer.claimed.Store(nil)
+	return nil
+}
+
+func (g groupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+
+			metrics.ConsumerLag.WithLabelValues(msg.Topic, strconv.Itoa(int(msg.Partition))).Set(float64(lag(claim, msg)))
+			if err := g.handleMessage(session.Context(), msg); err != nil {
+				// Leaving the offset uncommitted and ending the session makes the
+				// group resume from the last committed offset, so the message is
+				// redelivered instead of lost.
+				return err
+			}
+
+			session.MarkMessage(msg, "")
+			session.Commit()
+
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
+
+// handleMessage only returns an error when the message was neither applied
+// nor dead-lettered, so its offset must not be committed. Transient failures
+// are retried with backoff first; once the retries run out, or the failure
+// is permanent, the message is moved to the dead-letter topic.
+func (g groupHandler) handleMessage(ctx context.Context, msg *sarama.ConsumerMessage) error {
+	spanCtx, span := startProcessSpan(ctx, msg)
+	defer span.End()
+
+	payload, err := g.handler.ValidateSkillMessage(messageHeader(msg, message.ContentTypeHeader), msg.Value)
+	if err != nil {
+		log.Printf("Error validating message at %s, error: %s", describe(msg), err)
+		span.SetStatus(codes.Error, err.Error())
+		return g.deadLetterMessage(msg, metrics.UnknownAction, StageValidate, 1, err)
+	}
+
+	action := string(payload.Action)
+	span.SetAttributes(attribute.String("skill.action", action))
+
+	// A rebalance must not abort a write half way, so the writes only carry
+	// the trace and the request ID. The session context still ends the
+	// backoff below.
+	handleCtx := skill.WithRequestID(context.WithoutCancel(spanCtx), messageHeader(msg, HeaderRequestID))
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		err := g.handler.HandleSkill(handleCtx, payload)
+		metrics.HandleDuration.WithLabelValues(action).Observe(time.Since(start).Seconds())
+		if err == nil {
+			log.Printf("Successfully handled message at %s", describe(msg))
+			metrics.MessagesHandled.WithLabelValues(action, metrics.ResultProcessed).Inc()
+			g.recordOutcome(msg, operation.SucceededStatus, "")
+			return nil
+		}
+
+		// A stale change is an expected outcome rather than a broken message,
+		// so it is reported to the caller instead of dead-lettered.
+		if errors.Is(err, skill.ErrVersionConflict) {
+			log.Printf("Rejected stale message at %s, error: %s", describe(msg), err)
+			metrics.MessagesHandled.WithLabelValues(action, metrics.ResultConflict).Inc()
+			g.recordOutcome(msg, operation.ConflictStatus, err.Error())
+			return nil
+		}
 
+		// So is a change to a skill that is not there, which must not be
+		// reported as applied.
+		if errors.Is(err, skill.ErrSkillNotFound) {
+			log.Printf("Rejected message for missing skill at %s, error: %s", describe(msg), err)
+			metrics.MessagesHandled.WithLabelValues(action, metrics.ResultNotFound).Inc()
+			g.recordOutcome(msg, operation.NotFoundStatus, err.Error())
+			return nil
+		}
+
+		log.Printf("Error handling message at %s, attempt: %d, error: %s", describe(msg), attempt, err)
+		span.RecordError(err, trace.WithAttributes(attribute.Int("attempt", attempt)))
+		if !skill.IsTransientError(err) || attempt >= g.retry.MaxAttempts {
+			span.SetStatus(codes.Error, err.Error())
+			return g.deadLetterMessage(msg, action, StageHandle, attempt, err)
+		}
+
+		select {
+		case <-time.After(backoff(g.retry, attempt)):
 		case <-ctx.Done():
-			log.Print("Shutting down consumer...")
-			break ConsumerLoop
+			return fmt.Errorf("handle message at %s: %w", describe(msg), ctx.Err())
 		}
 	}
 }
+
+func (g groupHandler) deadLetterMessage(msg *sarama.ConsumerMessage, action string, stage string, attempts int, cause error) error {
+	if err := g.deadLetter.Publish(msg, stage, attempts, cause); err != nil {
+		return fmt.Errorf("dead-letter message at %s: %w", describe(msg), err)
+	}
+
+	metrics.MessagesHandled.WithLabelValues(action, metrics.ResultFailed).Inc()
+
+	log.Printf("Dead-lettered message at %s, stage: %s", describe(msg), stage)
+	g.recordOutcome(msg, operation.FailedStatus, cause.Error())
+	return nil
+}
+
+// lag is how many messages of the claimed partition are still to be read
+// after msg.
+func lag(claim sarama.ConsumerGroupClaim, msg *sarama.ConsumerMessage) int64 {
+	n := claim.HighWaterMarkOffset() - msg.Offset - 1
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// CheckBrokers fetches fresh metadata for the skill topic, which fails when
+// no broker is reachable or the topic does not exist.
+func (c *Consumer) CheckBrokers(ctx context.Context) error {
+	return c.client.RefreshMetadata(c.topic)
+}
+
+// CheckAssignment fails while the consumer is between sessions, for example
+// during a rebalance. A member with no partitions is still ready, since a
+// group can have more members than the topic has partitions.
+func (c *Consumer) CheckAssignment(ctx context.Context) error {
+	if c.claimed.Load() == nil {
+		return ErrNotAssigned
+	}
+	return nil
+}
@@ -1,58 +1,531 @@
 package kafka
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
 	"github.com/IBM/sarama"
 	"github.com/IBM/sarama/mocks"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"golang.org/x/net/context"
+	"reflect"
+	"skill-api-kafka-consumer/config"
+	"skill-api-kafka-consumer/metrics"
+	"skill-api-kafka-consumer/operation"
 	"skill-api-kafka-consumer/skill"
+	"skill-api-kafka-contract/message"
 	"testing"
 	"time"
 )
 
 type handlerMock struct {
 	skill.SkillHandler
-	msg string
+	msg         string
+	contentType string
+	errs        []error
+	calls       int
+	ctx         context.Context
 }
 
-func (h *handlerMock) ValidateSkillMessage(msg []byte) (*skill.SkillQueuePayload, error) {
+func (h *handlerMock) ValidateSkillMessage(contentType string, msg []byte) (*skill.SkillQueuePayload, error) {
 	h.msg = string(msg)
-	return &skill.SkillQueuePayload{}, nil
+	h.contentType = contentType
+	return &skill.SkillQueuePayload{Action: skill.CreateSkillAction}, nil
 }
 
-func (h *handlerMock) HandleSkill(payload *skill.SkillQueuePayload) error {
+func (h *handlerMock) HandleSkill(ctx context.Context, payload *skill.SkillQueuePayload) error {
+	h.ctx = ctx
+	h.calls++
+	if h.calls <= len(h.errs) {
+		return h.errs[h.calls-1]
+	}
+	return nil
+}
+
+type operationsMock struct {
+	outcomes   map[string]string
+	requestIDs map[string]string
+}
+
+func (o *operationsMock) Record(outcome operation.Outcome) error {
+	if o.outcomes == nil {
+		o.outcomes = map[string]string{}
+		o.requestIDs = map[string]string{}
+	}
+	o.outcomes[outcome.ID] = outcome.Status
+	o.requestIDs[outcome.ID] = outcome.RequestID
 	return nil
 }
 
-func TestConsumer(t *testing.T) {
+var operationHeader = []*sarama.RecordHeader{
+	{Key: []byte(HeaderOperationID), Value: []byte("op-1")},
+	{Key: []byte(HeaderRequestID), Value: []byte("req-1")},
+}
+
+var testRetry = config.RetryConfig{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     time.Millisecond,
+}
+
+type sessionMock struct {
+	sarama.ConsumerGroupSession
+	ctx       context.Context
+	marked    []int64
+	offsets   map[int32]int64
+	committed int
+}
+
+func (s *sessionMock) Context() context.Context {
+	return s.ctx
+}
+
+func (s *sessionMock) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.marked = append(s.marked, msg.Offset)
+}
+
+func (s *sessionMock) MarkOffset(topic string, partition int32, offset int64, metadata string) {
+	if s.offsets == nil {
+		s.offsets = map[int32]int64{}
+	}
+	s.offsets[partition] = offset
+}
+
+func (s *sessionMock) Commit() {
+	s.committed++
+}
+
+func (s *sessionMock) Claims() map[string][]int32 {
+	return map[string][]int32{"skill": {0, 1}}
+}
+
+func (s *sessionMock) GenerationID() int32 {
+	return 1
+}
+
+type claimMock struct {
+	sarama.ConsumerGroupClaim
+	messages      chan *sarama.ConsumerMessage
+	highWaterMark int64
+}
+
+func (c *claimMock) Messages() <-chan *sarama.ConsumerMessage {
+	return c.messages
+}
+
+func (c *claimMock) HighWaterMarkOffset() int64 {
+	return c.highWaterMark
+}
+
+func TestConsumeClaim(t *testing.T) {
 	// Arrange
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
 	handler := &handlerMock{}
-	consumerMock := mocks.NewConsumer(t, nil)
+	session := &sessionMock{ctx: ctx}
+	claim := &claimMock{messages: make(chan *sarama.ConsumerMessage, 1), highWaterMark: 8}
+	headers := append([]*sarama.RecordHeader{{Key: []byte(message.ContentTypeHeader), Value: []byte(message.ContentTypeJSON)}}, operationHeader...)
+	claim.messages <- &sarama.ConsumerMessage{Topic: "skill", Partition: 1, Offset: 5, Value: []byte(`create`), Headers: headers}
+	close(claim.messages)
+
+	operations := &operationsMock{}
+	g := groupHandler{handler: handler, operations: []OperationRecorder{operations}}
+	processed := testutil.ToFloat64(metrics.MessagesHandled.WithLabelValues(string(skill.CreateSkillAction), metrics.ResultProcessed))
+
+	// Act
+	err := g.ConsumeClaim(session, claim)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if handler.msg != "create" {
+		t.Errorf("expected %q but got %q", "create", handler.msg)
+	}
+
+	if handler.contentType != message.ContentTypeJSON {
+		t.Errorf("expected content type %q but got %q", message.ContentTypeJSON, handler.contentType)
+	}
+
+	if len(session.marked) != 1 || session.marked[0] != 5 {
+		t.Errorf("expected offset 5 to be marked but got %v", session.marked)
+	}
+
+	if session.committed != 1 {
+		t.Errorf("expected 1 commit but got %d", session.committed)
+	}
+
+	if operations.outcomes["op-1"] != "succeeded" {
+		t.Errorf("expected operation to succeed but got %q", operations.outcomes["op-1"])
+	}
+
+	if operations.requestIDs["op-1"] != "req-1" {
+		t.Errorf("expected request id req-1 but got %q", operations.requestIDs["op-1"])
+	}
+
+	if got := testutil.ToFloat64(metrics.MessagesHandled.WithLabelValues(string(skill.CreateSkillAction), metrics.ResultProcessed)) - processed; got != 1 {
+		t.Errorf("expected 1 processed message but got %v", got)
+	}
+
+	if got := testutil.ToFloat64(metrics.ConsumerLag.WithLabelValues("skill", "1")); got != 2 {
+		t.Errorf("expected lag of 2 but got %v", got)
+	}
+}
+
+func TestConsumeClaimRetriesTransientError(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	handler := &handlerMock{errs: []error{sql.ErrConnDone, sql.ErrConnDone}}
+	session := &sessionMock{ctx: ctx}
+	claim := &claimMock{messages: make(chan *sarama.ConsumerMessage, 1)}
+	claim.messages <- &sarama.ConsumerMessage{Topic: "skill", Partition: 1, Offset: 5, Value: []byte(`create`)}
+	close(claim.messages)
 
-	// Set expectation for the partition
-	consumerMock.ExpectConsumePartition("skill", 0, sarama.OffsetOldest).YieldMessage(&sarama.ConsumerMessage{Value: []byte(`create`)})
+	g := groupHandler{handler: handler, retry: testRetry}
 
-	// Start consuming the partition
-	partition, err := consumerMock.ConsumePartition("skill", 0, sarama.OffsetOldest)
+	// Act
+	err := g.ConsumeClaim(session, claim)
+
+	// Assert
 	if err != nil {
-		t.Fatalf("Failed to start consuming partition: %v", err)
+		t.Fatalf("expected no error, got %s", err)
 	}
 
-	c := Consumer{
-		broker:    "localhost:9092",
-		topic:     "skill",
-		consumer:  consumerMock,
-		partition: partition,
+	if handler.calls != 3 {
+		t.Errorf("expected 3 calls but got %d", handler.calls)
 	}
-	defer c.Close()
+
+	if session.committed != 1 {
+		t.Errorf("expected 1 commit but got %d", session.committed)
+	}
+}
+
+func TestConsumeClaimDeadLettersAfterRetries(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		if got := headerValue(msg.Headers, HeaderAttempts); got != "3" {
+			t.Errorf("expected attempts to be 3 but got %q", got)
+		}
+		return nil
+	})
+	defer producer.Close()
+
+	handler := &handlerMock{errs: []error{sql.ErrConnDone, sql.ErrConnDone, sql.ErrConnDone}}
+	session := &sessionMock{ctx: ctx}
+	claim := &claimMock{messages: make(chan *sarama.ConsumerMessage, 1)}
+	claim.messages <- &sarama.ConsumerMessage{Topic: "skill", Partition: 1, Offset: 5, Value: []byte(`create`)}
+	close(claim.messages)
+
+	g := groupHandler{handler: handler, retry: testRetry, deadLetter: NewDeadLetterQueue(producer, "skill_dlq")}
 
 	// Act
-	c.Run(ctx, handler)
+	err := g.ConsumeClaim(session, claim)
 
 	// Assert
-	if handler.msg != "create" {
-		t.Errorf("expected %q but got %q", "create", handler.msg)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if handler.calls != 3 {
+		t.Errorf("expected 3 calls but got %d", handler.calls)
+	}
+
+	if session.committed != 1 {
+		t.Errorf("expected 1 commit but got %d", session.committed)
+	}
+}
+
+func TestHandleMessageStopsRetryingWhenSessionEnds(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	handler := &handlerMock{errs: []error{sql.ErrConnDone, sql.ErrConnDone, sql.ErrConnDone}}
+	g := groupHandler{handler: handler, retry: config.RetryConfig{MaxAttempts: 3, InitialBackoff: time.Minute, MaxBackoff: time.Minute}}
+
+	// Act
+	err := g.handleMessage(ctx, &sarama.ConsumerMessage{Topic: "skill", Partition: 1, Offset: 5, Value: []byte(`create`)})
+
+	// Assert
+	if err == nil {
+		t.Fatal("expected error to be not nil")
+	}
+
+	if handler.calls != 1 {
+		t.Errorf("expected 1 call but got %d", handler.calls)
+	}
+}
+
+func TestHandleMessageCarriesRequestID(t *testing.T) {
+	// Arrange
+	handler := &handlerMock{}
+	g := groupHandler{handler: handler, retry: config.RetryConfig{MaxAttempts: 1}}
+	msg := &sarama.ConsumerMessage{
+		Topic:   "skill",
+		Value:   []byte(`create`),
+		Headers: []*sarama.RecordHeader{{Key: []byte(HeaderRequestID), Value: []byte("req-1")}},
+	}
+
+	// Act
+	err := g.handleMessage(context.Background(), msg)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if got := skill.RequestIDFrom(handler.ctx); got != "req-1" {
+		t.Errorf("expected request id req-1 in handler context, got %q", got)
+	}
+}
+
+func TestConsumeClaimDeadLettersInvalidPayload(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		if msg.Topic != "skill_dlq" {
+			t.Errorf("expected topic %q but got %q", "skill_dlq", msg.Topic)
+		}
+		return nil
+	})
+	defer producer.Close()
+
+	handler := &handlerMock{errs: []error{skill.ErrorInvalidPayload}}
+	session := &sessionMock{ctx: ctx}
+	claim := &claimMock{messages: make(chan *sarama.ConsumerMessage, 1)}
+	claim.messages <- &sarama.ConsumerMessage{Topic: "skill", Partition: 1, Offset: 5, Value: []byte(`create`), Headers: operationHeader}
+	close(claim.messages)
+
+	operations := &operationsMock{}
+	g := groupHandler{handler: handler, deadLetter: NewDeadLetterQueue(producer, "skill_dlq"), operations: []OperationRecorder{operations}}
+
+	// Act
+	err := g.ConsumeClaim(session, claim)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if session.committed != 1 {
+		t.Errorf("expected 1 commit but got %d", session.committed)
+	}
+
+	if operations.outcomes["op-1"] != "failed" {
+		t.Errorf("expected operation to fail but got %q", operations.outcomes["op-1"])
+	}
+}
+
+func TestConsumeClaimRecordsVersionConflict(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	producer := mocks.NewSyncProducer(t, nil)
+	defer producer.Close()
+
+	handler := &handlerMock{errs: []error{fmt.Errorf("%w: skill go is at version 3, expected 2", skill.ErrVersionConflict)}}
+	session := &sessionMock{ctx: ctx}
+	claim := &claimMock{messages: make(chan *sarama.ConsumerMessage, 1)}
+	claim.messages <- &sarama.ConsumerMessage{Topic: "skill", Partition: 1, Offset: 5, Value: []byte(`update`), Headers: operationHeader}
+	close(claim.messages)
+
+	operations := &operationsMock{}
+	g := groupHandler{handler: handler, retry: testRetry, deadLetter: NewDeadLetterQueue(producer, "skill_dlq"), operations: []OperationRecorder{operations}}
+
+	// Act
+	err := g.ConsumeClaim(session, claim)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if handler.calls != 1 {
+		t.Errorf("expected conflict not to be retried but got %d calls", handler.calls)
+	}
+
+	if session.committed != 1 {
+		t.Errorf("expected 1 commit but got %d", session.committed)
+	}
+
+	if operations.outcomes["op-1"] != operation.ConflictStatus {
+		t.Errorf("expected operation to conflict but got %q", operations.outcomes["op-1"])
+	}
+}
+
+func TestConsumeClaimRecordsSkillNotFound(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	producer := mocks.NewSyncProducer(t, nil)
+	defer producer.Close()
+
+	handler := &handlerMock{errs: []error{fmt.Errorf("%w: go", skill.ErrSkillNotFound)}}
+	session := &sessionMock{ctx: ctx}
+	claim := &claimMock{messages: make(chan *sarama.ConsumerMessage, 1)}
+	claim.messages <- &sarama.ConsumerMessage{Topic: "skill", Partition: 1, Offset: 5, Value: []byte(`update`), Headers: operationHeader}
+	close(claim.messages)
+
+	operations := &operationsMock{}
+	g := groupHandler{handler: handler, retry: testRetry, deadLetter: NewDeadLetterQueue(producer, "skill_dlq"), operations: []OperationRecorder{operations}}
+
+	// Act
+	err := g.ConsumeClaim(session, claim)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if handler.calls != 1 {
+		t.Errorf("expected missing skill not to be retried but got %d calls", handler.calls)
+	}
+
+	if session.committed != 1 {
+		t.Errorf("expected 1 commit but got %d", session.committed)
+	}
+
+	if operations.outcomes["op-1"] != operation.NotFoundStatus {
+		t.Errorf("expected operation not to be found but got %q", operations.outcomes["op-1"])
+	}
+}
+
+func TestConsumeClaimDoesNotCommitWhenDeadLetterFails(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageAndFail(sarama.ErrOutOfBrokers)
+	defer producer.Close()
+
+	handler := &handlerMock{errs: []error{skill.ErrInvalidSkillAction}}
+	session := &sessionMock{ctx: ctx}
+	claim := &claimMock{messages: make(chan *sarama.ConsumerMessage, 1)}
+	claim.messages <- &sarama.ConsumerMessage{Topic: "skill", Partition: 1, Offset: 5, Value: []byte(`create`)}
+	close(claim.messages)
+
+	g := groupHandler{handler: handler, deadLetter: NewDeadLetterQueue(producer, "skill_dlq")}
+
+	// Act
+	err := g.ConsumeClaim(session, claim)
+
+	// Assert
+	if err == nil {
+		t.Fatal("expected error to be not nil")
+	}
+
+	if session.committed != 0 {
+		t.Errorf("expected nothing to be committed but got %d commits", session.committed)
+	}
+}
+
+func TestConsumeClaimStopsOnSessionDone(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	handler := &handlerMock{}
+	session := &sessionMock{ctx: ctx}
+	claim := &claimMock{messages: make(chan *sarama.ConsumerMessage)}
+
+	g := groupHandler{handler: handler}
+
+	// Act
+	err := g.ConsumeClaim(session, claim)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if handler.msg != "" {
+		t.Errorf("expected no message to be handled but got %q", handler.msg)
+	}
+}
+
+func TestCheckAssignment(t *testing.T) {
+	// Arrange
+	c := &Consumer{offsetReset: config.OffsetResetLatest}
+	g := groupHandler{consumer: c}
+	session := &sessionMock{ctx: context.Background()}
+
+	// Act
+	before := c.CheckAssignment(context.Background())
+	setupErr := g.Setup(session)
+	during := c.CheckAssignment(context.Background())
+	cleanupErr := g.Cleanup(session)
+	after := c.CheckAssignment(context.Background())
+
+	// Assert
+	if setupErr != nil || cleanupErr != nil {
+		t.Fatalf("expected no error, got %v and %v", setupErr, cleanupErr)
+	}
+
+	if !errors.Is(before, ErrNotAssigned) || !errors.Is(after, ErrNotAssigned) {
+		t.Errorf("expected not to be assigned outside a session, got %v and %v", before, after)
+	}
+
+	if during != nil {
+		t.Errorf("expected to be assigned during a session, got %s", during)
+	}
+}
+
+func TestSetupStartsUncommittedPartitionsAtTimestamp(t *testing.T) {
+	// Arrange
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("skill", 0, broker.BrokerID()).
+			SetLeader("skill", 1, broker.BrokerID()),
+		"FindCoordinatorRequest": sarama.NewMockFindCoordinatorResponse(t).
+			SetCoordinator(sarama.CoordinatorGroup, "skill-group", broker),
+		"OffsetFetchRequest": sarama.NewMockOffsetFetchResponse(t).
+			SetOffset("skill-group", "skill", 0, 7, "", sarama.ErrNoError).
+			SetOffset("skill-group", "skill", 1, -1, "", sarama.ErrNoError),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).
+			SetOffset("skill", 1, from.UnixMilli(), 42),
+	})
+
+	client, err := sarama.NewClient([]string{broker.Addr()}, sarama.NewConfig())
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	defer client.Close()
+
+	c := &Consumer{client: client, groupID: "skill-group", offsetReset: config.OffsetResetTimestamp, offsetTimestamp: from}
+	session := &sessionMock{ctx: context.Background()}
+
+	// Act
+	err = groupHandler{consumer: c}.Setup(session)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if want := map[int32]int64{1: 42}; !reflect.DeepEqual(session.offsets, want) {
+		t.Errorf("expected starting offsets %v, got %v", want, session.offsets)
+	}
+
+	if session.committed != 1 {
+		t.Errorf("expected starting offsets to be committed once, got %d", session.committed)
 	}
 }
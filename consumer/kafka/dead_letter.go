@@ -0,0 +1,84 @@
+package kafka
+
+import (
+	"github.com/IBM/sarama"
+	"strconv"
+)
+
+const (
+	HeaderOriginalTopic     = "x-original-topic"
+	HeaderOriginalPartition = "x-original-partition"
+	HeaderOriginalOffset    = "x-original-offset"
+	HeaderError             = "x-error"
+	HeaderFailureStage      = "x-failure-stage"
+	HeaderAttempts          = "x-attempts"
+)
+
+const (
+	StageValidate = "validate"
+	StageHandle   = "handle"
+)
+
+type DeadLetterQueue struct {
+	producer sarama.SyncProducer
+	topic    string
+}
+
+func NewDeadLetterQueue(producer sarama.SyncProducer, topic string) DeadLetterQueue {
+	return DeadLetterQueue{
+		producer: producer,
+		topic:    topic,
+	}
+}
+
+// Publish copies the failed message to the dead-letter topic unchanged and
+// describes the failure in headers, so it can be inspected and replayed onto
+// the skill topic as is.
+func (q DeadLetterQueue) Publish(msg *sarama.ConsumerMessage, stage string, attempts int, cause error) error {
+	headers := make([]sarama.RecordHeader, 0, len(msg.Headers)+6)
+	for _, h := range msg.Headers {
+		if isDeadLetterHeader(string(h.Key)) {
+			continue
+		}
+		headers = append(headers, *h)
+	}
+
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(HeaderOriginalTopic), Value: []byte(msg.Topic)},
+		sarama.RecordHeader{Key: []byte(HeaderOriginalPartition), Value: []byte(strconv.Itoa(int(msg.Partition)))},
+		sarama.RecordHeader{Key: []byte(HeaderOriginalOffset), Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		sarama.RecordHeader{Key: []byte(HeaderError), Value: []byte(cause.Error())},
+		sarama.RecordHeader{Key: []byte(HeaderFailureStage), Value: []byte(stage)},
+		sarama.RecordHeader{Key: []byte(HeaderAttempts), Value: []byte(strconv.Itoa(previousAttempts(msg) + attempts))},
+	)
+
+	dead := &sarama.ProducerMessage{
+		Topic:   q.topic,
+		Value:   sarama.ByteEncoder(msg.Value),
+		Headers: headers,
+	}
+	if msg.Key != nil {
+		dead.Key = sarama.ByteEncoder(msg.Key)
+	}
+
+	_, _, err := q.producer.SendMessage(dead)
+	return err
+}
+
+// previousAttempts reads the attempt count of a message that was replayed
+// from the dead-letter topic, so the count keeps growing across replays.
+func previousAttempts(msg *sarama.ConsumerMessage) int {
+	n, err := strconv.Atoi(messageHeader(msg, HeaderAttempts))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func isDeadLetterHeader(key string) bool {
+	switch key {
+	case HeaderOriginalTopic, HeaderOriginalPartition, HeaderOriginalOffset, HeaderError, HeaderFailureStage, HeaderAttempts:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package kafka
+
+import (
+	"errors"
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"testing"
+)
+
+func headerValue(headers []sarama.RecordHeader, key string) string {
+	for _, h := range headers {
+		if string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func TestDeadLetterQueuePublish(t *testing.T) {
+	t.Run("should publish original value with failure headers", func(t *testing.T) {
+		// Arrange
+		producer := mocks.NewSyncProducer(t, nil)
+		defer producer.Close()
+
+		var sent *sarama.ProducerMessage
+		producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+			sent = msg
+			return nil
+		})
+
+		q := NewDeadLetterQueue(producer, "skill_topic_dlq")
+
+		// Act
+		err := q.Publish(&sarama.ConsumerMessage{
+			Topic:     "skill_topic",
+			Partition: 2,
+			Offset:    42,
+			Value:     []byte(`{"action":"create"}`),
+		}, StageValidate, 1, errors.New("key is nil"))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		value, _ := sent.Value.Encode()
+		if string(value) != `{"action":"create"}` {
+			t.Errorf("expected original value but got %s", value)
+		}
+
+		want := map[string]string{
+			HeaderOriginalTopic:     "skill_topic",
+			HeaderOriginalPartition: "2",
+			HeaderOriginalOffset:    "42",
+			HeaderError:             "key is nil",
+			HeaderFailureStage:      StageValidate,
+			HeaderAttempts:          "1",
+		}
+		for key, v := range want {
+			if got := headerValue(sent.Headers, key); got != v {
+				t.Errorf("expected header %s to be %q but got %q", key, v, got)
+			}
+		}
+	})
+
+	t.Run("should keep counting attempts of a replayed message", func(t *testing.T) {
+		// Arrange
+		producer := mocks.NewSyncProducer(t, nil)
+		defer producer.Close()
+
+		var sent *sarama.ProducerMessage
+		producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+			sent = msg
+			return nil
+		})
+
+		q := NewDeadLetterQueue(producer, "skill_topic_dlq")
+
+		// Act
+		err := q.Publish(&sarama.ConsumerMessage{
+			Topic: "skill_topic",
+			Value: []byte(`{}`),
+			Headers: []*sarama.RecordHeader{
+				{Key: []byte(HeaderAttempts), Value: []byte("2")},
+			},
+		}, StageHandle, 1, errors.New("invalid payload"))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if got := headerValue(sent.Headers, HeaderAttempts); got != "3" {
+			t.Errorf("expected attempts to be 3 but got %q", got)
+		}
+	})
+}
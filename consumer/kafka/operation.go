@@ -0,0 +1,96 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/sarama"
+	"log"
+	"skill-api-kafka-consumer/operation"
+	"skill-api-kafka-contract/message"
+)
+
+const (
+	HeaderOperationID = message.OperationIDHeader
+	HeaderRequestID   = message.RequestIDHeader
+)
+
+type OperationRecorder interface {
+	Record(outcome operation.Outcome) error
+}
+
+type OperationResult struct {
+	OperationID string `json:"operation_id"`
+	RequestID   string `json:"request_id,omitempty"`
+	Status      string `json:"status"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// ResultQueue publishes operation outcomes to the result topic, where API
+// instances waiting on a write pick them up by operation ID.
+type ResultQueue struct {
+	producer sarama.SyncProducer
+	topic    string
+}
+
+func NewResultQueue(producer sarama.SyncProducer, topic string) ResultQueue {
+	return ResultQueue{
+		producer: producer,
+		topic:    topic,
+	}
+}
+
+func (q ResultQueue) Record(outcome operation.Outcome) error {
+	value, err := json.Marshal(OperationResult{
+		OperationID: outcome.ID,
+		RequestID:   outcome.RequestID,
+		Status:      outcome.Status,
+		Reason:      outcome.Reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, _, err = q.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: q.topic,
+		Key:   sarama.StringEncoder(outcome.ID),
+		Value: sarama.ByteEncoder(value),
+	})
+	return err
+}
+
+// recordOutcome reports the final outcome of a message to the operation the
+// API created for it. Failing to record is logged only, since the message
+// itself has already been applied or dead-lettered.
+func (g groupHandler) recordOutcome(msg *sarama.ConsumerMessage, status string, reason string) {
+	id := messageHeader(msg, HeaderOperationID)
+	if id == "" {
+		return
+	}
+
+	outcome := operation.Outcome{
+		ID:        id,
+		RequestID: messageHeader(msg, HeaderRequestID),
+		Status:    status,
+		Reason:    reason,
+	}
+	for _, recorder := range g.operations {
+		if err := recorder.Record(outcome); err != nil {
+			log.Printf("Error recording operation: %s, status: %s, %s, error: %s", id, status, describe(msg), err)
+		}
+	}
+}
+
+// describe identifies msg in log lines, including the request ID the API
+// received the write with so a write can be traced across both services.
+func describe(msg *sarama.ConsumerMessage) string {
+	return fmt.Sprintf("topic: %s, partition: %d, offset: %d, request_id: %s", msg.Topic, msg.Partition, msg.Offset, messageHeader(msg, HeaderRequestID))
+}
+
+func messageHeader(msg *sarama.ConsumerMessage, key string) string {
+	for _, h := range msg.Headers {
+		if string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
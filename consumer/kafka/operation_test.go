@@ -0,0 +1,41 @@
+package kafka
+
+import (
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"skill-api-kafka-consumer/operation"
+	"testing"
+)
+
+func TestResultQueueRecord(t *testing.T) {
+	// Arrange
+	producer := mocks.NewSyncProducer(t, nil)
+	defer producer.Close()
+
+	var sent *sarama.ProducerMessage
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		sent = msg
+		return nil
+	})
+
+	q := NewResultQueue(producer, "skill_topic_result")
+
+	// Act
+	err := q.Record(operation.Outcome{ID: "op-1", RequestID: "req-1", Status: "failed", Reason: "invalid payload"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	key, _ := sent.Key.Encode()
+	value, _ := sent.Value.Encode()
+	if sent.Topic != "skill_topic_result" || string(key) != "op-1" {
+		t.Errorf("expected op-1 on skill_topic_result but got %s on %s", key, sent.Topic)
+	}
+
+	want := `{"operation_id":"op-1","request_id":"req-1","status":"failed","reason":"invalid payload"}`
+	if string(value) != want {
+		t.Errorf("expected %s but got %s", want, value)
+	}
+}
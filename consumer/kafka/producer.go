@@ -0,0 +1,26 @@
+package kafka
+
+import (
+	"github.com/IBM/sarama"
+	"log"
+	"skill-api-kafka-consumer/config"
+	"strings"
+)
+
+func Producer(c config.KafkaConfig) (sarama.SyncProducer, func()) {
+	kafkaConfig := sarama.NewConfig()
+	kafkaConfig.Producer.Return.Successes = true
+	kafkaConfig.Producer.Return.Errors = true
+	kafkaConfig.Producer.RequiredAcks = sarama.WaitForAll
+
+	producer, err := sarama.NewSyncProducer(strings.Split(c.KafkaConsumer, ","), kafkaConfig)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	return producer, func() {
+		if err := producer.Close(); err != nil {
+			log.Fatalln(err)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package kafka
+
+import (
+	"math/rand"
+	"skill-api-kafka-consumer/config"
+	"time"
+)
+
+// backoff returns how long to wait before the given retry (starting at 1).
+// The delay doubles on every retry up to MaxBackoff, and a random half of it
+// is dropped so replicas retrying the same outage don't hit Postgres together.
+func backoff(c config.RetryConfig, retry int) time.Duration {
+	delay := c.InitialBackoff
+	for i := 1; i < retry && delay < c.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > c.MaxBackoff {
+		delay = c.MaxBackoff
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
@@ -0,0 +1,65 @@
+package kafka
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+)
+
+func setupTracing(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(sdktrace.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+	return recorder
+}
+
+func TestHandleMessageContinuesTrace(t *testing.T) {
+	// Arrange
+	recorder := setupTracing(t)
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	msg := &sarama.ConsumerMessage{
+		Topic:     "skill",
+		Partition: 1,
+		Offset:    5,
+		Value:     []byte(`create`),
+		Headers:   []*sarama.RecordHeader{{Key: []byte("traceparent"), Value: []byte(traceparent)}},
+	}
+
+	handler := &handlerMock{}
+	g := groupHandler{handler: handler}
+
+	// Act
+	err := g.handleMessage(context.Background(), msg)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	process := spans[0]
+	if process.SpanKind() != trace.SpanKindConsumer || process.Name() != "skill process" {
+		t.Errorf("expected consumer span skill process, got %s %s", process.SpanKind(), process.Name())
+	}
+
+	if process.Parent().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || process.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("expected span to continue the producer's trace, got parent %s", process.Parent().SpanID())
+	}
+
+	if trace.SpanContextFromContext(handler.ctx).SpanID() != process.SpanContext().SpanID() {
+		t.Errorf("expected handler to run inside the process span")
+	}
+}
@@ -3,20 +3,48 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
 	"os/signal"
 	"skill-api-kafka-consumer/config"
 	"skill-api-kafka-consumer/database"
 	"skill-api-kafka-consumer/kafka"
+	"skill-api-kafka-consumer/metrics"
+	"skill-api-kafka-consumer/operation"
+	"skill-api-kafka-consumer/outbox"
 	"skill-api-kafka-consumer/skill"
+	"skill-api-kafka-consumer/tracing"
+	"skill-api-kafka-contract/health"
+	"skill-api-kafka-contract/message"
+	"skill-api-kafka-contract/schema"
+	"skill-api-kafka-contract/telemetry"
 	"syscall"
 	"time"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		replayCommand(os.Args[2:])
+		return
+	}
+
 	c := config.Configuration()
 
+	shutdownTracing, err := telemetry.Setup(context.Background(), tracing.ServiceName, c.Tracing)
+	if err != nil {
+		log.Fatal("Error setting up tracing: ", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Println("Error flushing traces:", err)
+		}
+	}()
+
 	db := database.Postgres(c.PostgresURI)
 	defer func(db *sql.DB) {
 		err := db.Close()
@@ -26,24 +54,69 @@ func main() {
 		log.Println("Database connection closed")
 	}(db)
 
+	producer, closeProducer := kafka.Producer(c.Kafka)
+	defer closeProducer()
+
 	skillStorage := skill.NewSkillStorage(db)
 	skillService := skill.NewSkillService(skillStorage)
-	skillHandler := skill.NewSkillHandler(skillService)
 
-	consumer := kafka.NewConsumer(c.Kafka.KafkaConsumer, c.Kafka.SkillTopic)
+	registry, err := schema.Open(c.SchemaRegistryDir)
+	if err != nil {
+		log.Fatal("Error loading schema registry: ", err)
+	}
+
+	serializers, err := message.NewSerializers(registry)
+	if err != nil {
+		log.Fatal("Error checking skill schema: ", err)
+	}
+	serializers.Register(message.ContentTypeJSON, skill.NewJSONSerializer(skill.DefaultUpcasters()))
+
+	skillHandler := skill.NewSkillHandler(skillService, serializers)
+
+	deadLetter := kafka.NewDeadLetterQueue(producer, c.Kafka.DeadLetterTopic)
+	operationStorage := operation.NewOperationStorage(db)
+	results := kafka.NewResultQueue(producer, c.Kafka.ResultTopic)
+	consumer := kafka.NewConsumer(c.Kafka, c.Retry, deadLetter, operationStorage, results)
+
+	// The consumer has no API of its own, the listener only serves
+	// operational endpoints.
+	checker := health.NewChecker(2 * time.Second)
+	checker.Register("postgres", db.PingContext)
+	checker.Register("kafka", consumer.CheckBrokers)
+	checker.Register("assignment", consumer.CheckAssignment)
+	hh := health.NewHealthHandler(checker)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.HandleFunc("/healthz", hh.Liveness)
+	mux.HandleFunc("/readyz", hh.Readiness)
+	srv := &http.Server{
+		Addr:    ":" + c.Port,
+		Handler: mux,
+	}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Println("Error Serve:", err)
+		}
+	}()
 
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
+
+	go skill.NewPurger(skillStorage, c.Purge).Run(ctx)
+	go outbox.NewRelay(outbox.NewOutboxStorage(db), producer, c.Kafka.EventTopic, c.Outbox).Run(ctx)
 	go func() {
 		<-ctx.Done()
 
 		timeOut, cancelTimeout := context.WithTimeout(context.Background(), 3*time.Second)
 		defer cancelTimeout()
-		consumer.ClosePartition()
-		fmt.Println("Kafka Partition closed")
 		consumer.Close()
 		fmt.Println("Kafka Consumer closed")
 
+		if err := srv.Shutdown(timeOut); err != nil {
+			log.Println("Error shutting down server:", err)
+		}
+
 		<-timeOut.Done()
 
 		err := db.Close()
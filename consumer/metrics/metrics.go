@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+)
+
+const (
+	ResultProcessed = "processed"
+	ResultFailed    = "failed"
+	ResultConflict  = "conflict"
+	ResultNotFound  = "not_found"
+	ResultSent      = "sent"
+)
+
+// UnknownAction labels messages that failed before their action was known.
+const UnknownAction = "unknown"
+
+var (
+	// MessagesHandled counts every message once, when it is applied, rejected
+	// as a conflict or dead-lettered. Retries are not counted.
+	MessagesHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "skill_consumer_messages_total",
+		Help: "Skill messages consumed, by action and result.",
+	}, []string{"action", "result"})
+
+	HandleDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "skill_consumer_handle_duration_seconds",
+		Help:    "Time taken by each attempt to handle a skill message, by action.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"action"})
+
+	SkillsPurged = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "skill_consumer_skills_purged_total",
+		Help: "Deleted skills permanently removed from the trash.",
+	})
+
+	// EventsSent counts skill events the outbox relay handed to Kafka. A
+	// failed event stays pending and is counted again on every attempt.
+	EventsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "skill_consumer_events_sent_total",
+		Help: "Skill events sent from the outbox to Kafka, by result.",
+	}, []string{"result"})
+
+	EventsSwept = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "skill_consumer_events_swept_total",
+		Help: "Sent skill events deleted from the outbox after the retention.",
+	})
+
+	ConsumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "skill_consumer_lag",
+		Help: "Messages behind the end of the partition, as of the last message consumed.",
+	}, []string{"topic", "partition"})
+)
+
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
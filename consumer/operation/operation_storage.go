@@ -0,0 +1,43 @@
+package operation
+
+import "database/sql"
+
+const (
+	SucceededStatus = "succeeded"
+	FailedStatus    = "failed"
+	// ConflictStatus is a change rejected because the skill had moved on
+	// from the version it was made against.
+	ConflictStatus = "conflict"
+	// NotFoundStatus is a change to a skill that does not exist.
+	NotFoundStatus = "not_found"
+)
+
+// Outcome is the final result of a skill message, reported against the
+// operation the API created for it.
+type Outcome struct {
+	ID        string
+	RequestID string
+	Status    string
+	Reason    string
+}
+
+type operationStorage struct {
+	db *sql.DB
+}
+
+func NewOperationStorage(db *sql.DB) operationStorage {
+	return operationStorage{db: db}
+}
+
+// Record stores the outcome of an operation created by the API. The row is
+// inserted when missing so an outcome is never lost to a race with the API.
+// The request ID is only written on insert, the API already stored it
+// otherwise. An operation that already has its outcome keeps it, so a
+// message delivered again cannot turn a success into a failure.
+func (s operationStorage) Record(outcome Outcome) error {
+	qry := `INSERT INTO skill_operation (id, request_id, status, reason) VALUES ($1, $2, $3, $4)
+ON CONFLICT (id) DO UPDATE SET status = excluded.status, reason = excluded.reason, updated_at = CURRENT_TIMESTAMP
+WHERE skill_operation.status = 'pending'`
+	_, err := s.db.Exec(qry, outcome.ID, outcome.RequestID, outcome.Status, outcome.Reason)
+	return err
+}
@@ -0,0 +1,111 @@
+package operation
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func newMockDB() *sql.DB {
+	db, _ := sql.Open("sqlite", "file:operation?mode=memory&cache=shared")
+	q := `
+CREATE TABLE IF NOT EXISTS skill_operation (
+    id TEXT PRIMARY KEY,
+    action TEXT NOT NULL DEFAULT '',
+    skill_key TEXT NOT NULL DEFAULT '',
+    request_id TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending',
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+	db.Exec(q)
+	return db
+}
+
+func getStatus(db *sql.DB, id string) (string, string, string) {
+	var action, status, reason string
+	db.QueryRow("SELECT action, status, reason FROM skill_operation WHERE id = $1", id).Scan(&action, &status, &reason)
+	return action, status, reason
+}
+
+func getRequestID(db *sql.DB, id string) string {
+	var requestID string
+	db.QueryRow("SELECT request_id FROM skill_operation WHERE id = $1", id).Scan(&requestID)
+	return requestID
+}
+
+func TestStorageRecord(t *testing.T) {
+	t.Run("should update pending operation", func(t *testing.T) {
+		// Arrange
+		db := newMockDB()
+		defer db.Close()
+		db.Exec("INSERT INTO skill_operation (id, action, skill_key, request_id) VALUES ('op-1', 'create', 'go', 'req-1')")
+
+		storage := NewOperationStorage(db)
+
+		// Act
+		err := storage.Record(Outcome{ID: "op-1", RequestID: "req-other", Status: FailedStatus, Reason: "invalid payload"})
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		action, status, reason := getStatus(db, "op-1")
+		if action != "create" || status != FailedStatus || reason != "invalid payload" {
+			t.Errorf("got %s, %s, %s, want create, failed, invalid payload", action, status, reason)
+		}
+
+		if requestID := getRequestID(db, "op-1"); requestID != "req-1" {
+			t.Errorf("got request id %s, want req-1", requestID)
+		}
+	})
+
+	t.Run("should keep outcome of finished operation", func(t *testing.T) {
+		// Arrange
+		db := newMockDB()
+		defer db.Close()
+		db.Exec("INSERT INTO skill_operation (id, action, skill_key, status) VALUES ('op-3', 'create', 'go', 'succeeded')")
+
+		storage := NewOperationStorage(db)
+
+		// Act
+		err := storage.Record(Outcome{ID: "op-3", Status: FailedStatus, Reason: "duplicate key"})
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, status, reason := getStatus(db, "op-3"); status != SucceededStatus || reason != "" {
+			t.Errorf("got %s, %s, want succeeded with no reason", status, reason)
+		}
+	})
+
+	t.Run("should insert unknown operation", func(t *testing.T) {
+		// Arrange
+		db := newMockDB()
+		defer db.Close()
+
+		storage := NewOperationStorage(db)
+
+		// Act
+		err := storage.Record(Outcome{ID: "op-2", RequestID: "req-2", Status: SucceededStatus})
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, status, _ := getStatus(db, "op-2"); status != SucceededStatus {
+			t.Errorf("got status %s, want succeeded", status)
+		}
+
+		if requestID := getRequestID(db, "op-2"); requestID != "req-2" {
+			t.Errorf("got request id %s, want req-2", requestID)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package outbox
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"log"
+	"skill-api-kafka-consumer/config"
+	"skill-api-kafka-consumer/metrics"
+	"skill-api-kafka-consumer/tracing"
+	"strconv"
+	"time"
+)
+
+type Storage interface {
+	Dispatch(limit int, send func(Message) error) (int, error)
+	DeleteSent(before time.Time) (int64, error)
+}
+
+// Relay publishes the skill events the consumer wrote to the outbox to the
+// event topic.
+type Relay struct {
+	storage  Storage
+	producer sarama.SyncProducer
+	topic    string
+	config   config.OutboxConfig
+	now      func() time.Time
+}
+
+func NewRelay(storage Storage, producer sarama.SyncProducer, topic string, config config.OutboxConfig) Relay {
+	return Relay{
+		storage:  storage,
+		producer: producer,
+		topic:    topic,
+		config:   config,
+		now:      time.Now,
+	}
+}
+
+// Run publishes pending events until ctx is done. An event stays pending
+// until Kafka acknowledges it, so a change applied while the broker was down
+// is still announced once it is back. Sent events are swept once they are
+// older than the retention, unless it is zero.
+func (r Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	var sweep <-chan time.Time
+	if r.config.Retention > 0 {
+		sweepTicker := time.NewTicker(r.config.SweepInterval)
+		defer sweepTicker.Stop()
+		sweep = sweepTicker.C
+	}
+
+	for {
+		select {
+		case <-sweep:
+			if _, err := r.Sweep(); err != nil {
+				log.Println("Error sweeping event outbox:", err)
+			}
+		case <-ticker.C:
+			for {
+				n, err := r.Flush()
+				if err != nil {
+					log.Println("Error relaying event outbox:", err)
+					break
+				}
+
+				if n < r.config.BatchSize {
+					break
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r Relay) Flush() (int, error) {
+	return r.storage.Dispatch(r.config.BatchSize, r.send)
+}
+
+// Sweep deletes events sent longer ago than the retention. Sweeping is
+// idempotent, so every consumer instance can run it.
+func (r Relay) Sweep() (int64, error) {
+	n, err := r.storage.DeleteSent(r.now().Add(-r.config.Retention))
+	if err != nil {
+		return 0, err
+	}
+
+	if n > 0 {
+		log.Printf("Swept %d sent events", n)
+		metrics.EventsSwept.Add(float64(n))
+	}
+	return n, nil
+}
+
+// send continues the trace of the message whose change msg announces, so
+// downstream consumers of the event can too.
+func (r Relay) send(msg Message) error {
+	propagator := otel.GetTextMapPropagator()
+	ctx := propagator.Extract(context.Background(), propagation.MapCarrier(msg.Headers))
+	ctx, span := tracing.Tracer.Start(ctx, r.topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypePublish,
+			semconv.MessagingDestinationName(r.topic),
+		),
+	)
+	defer span.End()
+
+	carrier := propagation.MapCarrier{}
+	for k, v := range msg.Headers {
+		carrier[k] = v
+	}
+	propagator.Inject(ctx, carrier)
+
+	headers := make([]sarama.RecordHeader, 0, len(carrier))
+	for k, v := range carrier {
+		headers = append(headers, sarama.RecordHeader{Key: []byte(k), Value: []byte(v)})
+	}
+
+	// Keyed by skill, every event for one skill lands on the same partition
+	// in the order it happened.
+	partition, offset, err := r.producer.SendMessage(&sarama.ProducerMessage{
+		Topic:   r.topic,
+		Key:     sarama.StringEncoder(msg.Key),
+		Value:   sarama.ByteEncoder(msg.Value),
+		Headers: headers,
+	})
+	if err != nil {
+		metrics.EventsSent.WithLabelValues(metrics.ResultFailed).Inc()
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	metrics.EventsSent.WithLabelValues(metrics.ResultSent).Inc()
+
+	span.SetAttributes(
+		semconv.MessagingDestinationPartitionID(strconv.Itoa(int(partition))),
+		semconv.MessagingKafkaMessageOffset(int(offset)),
+	)
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/IBM/sarama"
+	"log"
+	"os"
+	"os/signal"
+	"skill-api-kafka-consumer/config"
+	"skill-api-kafka-consumer/database"
+	"skill-api-kafka-consumer/kafka"
+	"skill-api-kafka-consumer/replay"
+	"skill-api-kafka-consumer/skill"
+	"skill-api-kafka-contract/message"
+	"skill-api-kafka-contract/schema"
+	"syscall"
+	"time"
+)
+
+// replayCommand re-applies the skill topic to the database through the same
+// handler the consumer uses, which skips messages the target tables already
+// hold. It reads the same environment as the consumer.
+//
+//	skill_consumer replay [-from-offset N | -from-time T] [-to-offset N] [-to-time T]
+//	                      [-partition P] [-schema NAME] [-dry-run]
+func replayCommand(args []string) {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	partition := flags.Int("partition", -1, "only replay this partition, all partitions when -1")
+	fromOffset := flags.Int64("from-offset", replay.NoOffset, "first offset to replay, the oldest when not set")
+	toOffset := flags.Int64("to-offset", replay.NoOffset, "last offset to replay, the newest when not set")
+	fromTime := flags.String("from-time", "", "replay messages produced at or after this RFC3339 time")
+	toTime := flags.String("to-time", "", "replay messages produced at or before this RFC3339 time")
+	schemaFlag := flags.String("schema", "", "apply to the skill table in this schema, created when missing")
+	dryRun := flags.Bool("dry-run", false, "print the messages in the range without applying them")
+	flags.Parse(args)
+
+	options := replay.Options{
+		Partition:  int32(*partition),
+		FromOffset: *fromOffset,
+		ToOffset:   *toOffset,
+		FromTime:   parseTime("from-time", *fromTime),
+		ToTime:     parseTime("to-time", *toTime),
+		DryRun:     *dryRun,
+		Out:        os.Stdout,
+	}
+
+	if *schemaFlag != "" && !replay.ValidSchema(*schemaFlag) {
+		log.Fatal(replay.ErrInvalidSchema)
+	}
+
+	c := config.Configuration()
+	options.Topic = c.Kafka.SkillTopic
+
+	registry, err := schema.Open(c.SchemaRegistryDir)
+	if err != nil {
+		log.Fatal("Error loading schema registry: ", err)
+	}
+
+	serializers, err := message.NewSerializers(registry)
+	if err != nil {
+		log.Fatal("Error checking skill schema: ", err)
+	}
+	serializers.Register(message.ContentTypeJSON, skill.NewJSONSerializer(skill.DefaultUpcasters()))
+
+	// A dry run only decodes, so it never needs the database.
+	var skillService skill.SkillService
+	if !*dryRun {
+		skillService = replayService(c.PostgresURI, *schemaFlag)
+	}
+	skillHandler := skill.NewSkillHandler(skillService, serializers)
+
+	client, closeClient := kafka.Client(c.Kafka)
+	defer closeClient()
+
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		log.Fatal("Error creating consumer: ", err)
+	}
+	defer consumer.Close()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	summary, err := replay.NewReplayer(client, consumer, skillHandler, options).Run(ctx)
+	if options.DryRun {
+		fmt.Printf("Read %d messages from topic %s without applying them: %d decoded, %d failed\n", summary.Read, options.Topic, summary.Decoded, summary.Failed)
+	} else {
+		fmt.Printf("Replayed %d messages from topic %s: %d applied, %d failed\n", summary.Read, options.Topic, summary.Applied, summary.Failed)
+	}
+	if err != nil {
+		log.Fatal("Error replaying topic: ", err)
+	}
+}
+
+// replayService writes to the skill table of schema, or to the live one when
+// schema is empty. Events of a replay into schema stay in that schema's
+// outbox, which nothing relays. A replay into the live tables only applies
+// messages that were never applied, so the consumer announces those.
+func replayService(uri string, schemaName string) skill.SkillService {
+	if schemaName != "" {
+		db := database.Postgres(uri)
+		if err := replay.PrepareSchema(db, schemaName); err != nil {
+			log.Fatal("Error preparing schema: ", err)
+		}
+		db.Close()
+
+		var err error
+		uri, err = database.WithSearchPath(uri, schemaName)
+		if err != nil {
+			log.Fatal("Error configuring schema: ", err)
+		}
+	}
+
+	db := database.Postgres(uri)
+	return skill.NewSkillService(skill.NewSkillStorage(db))
+}
+
+func parseTime(name string, value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Fatalf("-%s must be an RFC3339 timestamp", name)
+	}
+	return t
+}
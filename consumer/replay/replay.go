@@ -0,0 +1,210 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/IBM/sarama"
+	"io"
+	"skill-api-kafka-consumer/skill"
+	"skill-api-kafka-contract/message"
+	"time"
+)
+
+// NoOffset leaves the corresponding bound of the range open.
+const NoOffset int64 = -1
+
+var ErrInvalidRange = errors.New("replay range must start at an offset or at a time, not both")
+
+// Offsets is the part of sarama.Client a replay needs to turn the requested
+// range into offsets.
+type Offsets interface {
+	Partitions(topic string) ([]int32, error)
+	GetOffset(topic string, partition int32, time int64) (int64, error)
+}
+
+type Options struct {
+	Topic string
+	// Partition limits the replay to one partition, -1 replays all of them.
+	Partition int32
+	// The range starts at FromOffset or FromTime, and at the oldest message
+	// when neither is set. It ends at ToOffset or ToTime inclusive, and at
+	// the last message present when the replay started otherwise.
+	FromOffset int64
+	ToOffset   int64
+	FromTime   time.Time
+	ToTime     time.Time
+	// DryRun decodes and prints every message in the range without applying
+	// any of them.
+	DryRun bool
+	Out    io.Writer
+}
+
+type Failure struct {
+	Partition int32
+	Offset    int64
+	Err       error
+}
+
+// Summary counts the messages of a replay. A dry run applies nothing, the
+// messages it could decode are counted as Decoded instead of Applied.
+type Summary struct {
+	Read     int
+	Applied  int
+	Decoded  int
+	Failed   int
+	Failures []Failure
+}
+
+type Replayer struct {
+	offsets  Offsets
+	consumer sarama.Consumer
+	handler  skill.SkillHandler
+	options  Options
+}
+
+// NewReplayer reads messages with consumer directly from their partitions,
+// outside of any consumer group, so a replay never moves the offsets the
+// running consumers committed.
+func NewReplayer(offsets Offsets, consumer sarama.Consumer, handler skill.SkillHandler, options Options) Replayer {
+	return Replayer{
+		offsets:  offsets,
+		consumer: consumer,
+		handler:  handler,
+		options:  options,
+	}
+}
+
+// Run replays the range one partition after the other. Messages for one
+// skill always share a partition, so they are applied in the order they
+// were produced. A message that fails is counted and skipped, it is neither
+// retried nor dead-lettered.
+func (r Replayer) Run(ctx context.Context) (Summary, error) {
+	var summary Summary
+	if r.options.FromOffset != NoOffset && !r.options.FromTime.IsZero() {
+		return summary, ErrInvalidRange
+	}
+
+	partitions, err := r.partitions()
+	if err != nil {
+		return summary, err
+	}
+
+	for _, partition := range partitions {
+		if err := r.replayPartition(ctx, partition, &summary); err != nil {
+			return summary, fmt.Errorf("replay partition %d: %w", partition, err)
+		}
+	}
+
+	return summary, nil
+}
+
+func (r Replayer) partitions() ([]int32, error) {
+	if r.options.Partition >= 0 {
+		return []int32{r.options.Partition}, nil
+	}
+	return r.offsets.Partitions(r.options.Topic)
+}
+
+func (r Replayer) replayPartition(ctx context.Context, partition int32, summary *Summary) error {
+	start, end, err := r.bounds(partition)
+	if err != nil {
+		return err
+	}
+
+	if start < 0 || start >= end {
+		return nil
+	}
+
+	pc, err := r.consumer.ConsumePartition(r.options.Topic, partition, start)
+	if err != nil {
+		return err
+	}
+	defer pc.Close()
+
+	for {
+		select {
+		case msg := <-pc.Messages():
+			if !r.options.ToTime.IsZero() && msg.Timestamp.After(r.options.ToTime) {
+				return nil
+			}
+
+			summary.Read++
+			r.replayMessage(ctx, msg, summary)
+
+			if msg.Offset >= end-1 {
+				return nil
+			}
+		case err := <-pc.Errors():
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// bounds resolves the range to [start, end) offsets within partition. The
+// end is fixed before reading, so messages produced during the replay are
+// left to the running consumers.
+func (r Replayer) bounds(partition int32) (int64, int64, error) {
+	end, err := r.offsets.GetOffset(r.options.Topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if r.options.ToOffset != NoOffset && r.options.ToOffset+1 < end {
+		end = r.options.ToOffset + 1
+	}
+
+	start := r.options.FromOffset
+	switch {
+	case !r.options.FromTime.IsZero():
+		// No message at or after FromTime is reported as -1, which leaves
+		// nothing to replay in this partition.
+		start, err = r.offsets.GetOffset(r.options.Topic, partition, r.options.FromTime.UnixMilli())
+	case start == NoOffset:
+		start, err = r.offsets.GetOffset(r.options.Topic, partition, sarama.OffsetOldest)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return start, end, nil
+}
+
+func (r Replayer) replayMessage(ctx context.Context, msg *sarama.ConsumerMessage, summary *Summary) {
+	payload, err := r.handler.ValidateSkillMessage(header(msg, message.ContentTypeHeader), msg.Value)
+	if err == nil && !r.options.DryRun {
+		err = r.handler.HandleSkill(ctx, payload)
+	}
+
+	if err != nil {
+		summary.Failed++
+		summary.Failures = append(summary.Failures, Failure{Partition: msg.Partition, Offset: msg.Offset, Err: err})
+		r.printf("partition: %d, offset: %d, failed: %s\n", msg.Partition, msg.Offset, err)
+		return
+	}
+
+	if r.options.DryRun {
+		summary.Decoded++
+		r.printf("partition: %d, offset: %d, timestamp: %s, action: %s, key: %s\n", msg.Partition, msg.Offset, msg.Timestamp.UTC().Format(time.RFC3339), payload.Action, *payload.Key)
+		return
+	}
+
+	summary.Applied++
+}
+
+func (r Replayer) printf(format string, args ...any) {
+	if r.options.Out != nil {
+		fmt.Fprintf(r.options.Out, format, args...)
+	}
+}
+
+func header(msg *sarama.ConsumerMessage, key string) string {
+	for _, h := range msg.Headers {
+		if string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
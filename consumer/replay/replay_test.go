@@ -0,0 +1,176 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"skill-api-kafka-consumer/skill"
+	"strings"
+	"testing"
+	"time"
+)
+
+type offsetsMock struct {
+	oldest int64
+	newest int64
+	atTime int64
+}
+
+func (o offsetsMock) Partitions(topic string) ([]int32, error) {
+	return []int32{0}, nil
+}
+
+func (o offsetsMock) GetOffset(topic string, partition int32, t int64) (int64, error) {
+	switch t {
+	case sarama.OffsetOldest:
+		return o.oldest, nil
+	case sarama.OffsetNewest:
+		return o.newest, nil
+	default:
+		return o.atTime, nil
+	}
+}
+
+type handlerMock struct {
+	skill.SkillHandler
+	handled []string
+	fail    map[string]error
+}
+
+func (h *handlerMock) ValidateSkillMessage(contentType string, msg []byte) (*skill.SkillQueuePayload, error) {
+	if string(msg) == "invalid" {
+		return nil, errors.New("message is invalid")
+	}
+	key := string(msg)
+	return &skill.SkillQueuePayload{Action: skill.UpdateNameAction, Key: &key}, nil
+}
+
+func (h *handlerMock) HandleSkill(ctx context.Context, payload *skill.SkillQueuePayload) error {
+	h.handled = append(h.handled, *payload.Key)
+	return h.fail[*payload.Key]
+}
+
+func yield(t *testing.T, offset int64, values ...string) *mocks.Consumer {
+	consumer := mocks.NewConsumer(t, nil)
+	pc := consumer.ExpectConsumePartition("skill", 0, offset)
+	for _, v := range values {
+		pc.YieldMessage(&sarama.ConsumerMessage{Value: []byte(v), Timestamp: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)})
+	}
+	return consumer
+}
+
+func TestReplayerRun(t *testing.T) {
+	t.Run("should apply every message up to the newest offset", func(t *testing.T) {
+		// Arrange
+		consumer := yield(t, 3, "go", "invalid", "python", "rust")
+		handler := &handlerMock{fail: map[string]error{"python": errors.New("duplicate key")}}
+		options := Options{Topic: "skill", Partition: -1, FromOffset: NoOffset, ToOffset: NoOffset}
+		r := NewReplayer(offsetsMock{oldest: 3, newest: 6}, consumer, handler, options)
+
+		// Act
+		summary, err := r.Run(context.Background())
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if strings.Join(handler.handled, ",") != "go,python" {
+			t.Errorf("expected go and python to be handled, got %v", handler.handled)
+		}
+
+		if summary.Read != 3 || summary.Applied != 1 || summary.Failed != 2 {
+			t.Errorf("expected 3 read, 1 applied, 2 failed, got %+v", summary)
+		}
+
+		if summary.Failures[0].Offset != 4 || summary.Failures[1].Offset != 5 {
+			t.Errorf("expected failures at offsets 4 and 5, got %+v", summary.Failures)
+		}
+	})
+
+	t.Run("should stop at the last offset of the range", func(t *testing.T) {
+		// Arrange
+		consumer := yield(t, 1, "go", "python", "rust")
+		handler := &handlerMock{}
+		options := Options{Topic: "skill", Partition: 0, FromOffset: 1, ToOffset: 2}
+		r := NewReplayer(offsetsMock{newest: 10}, consumer, handler, options)
+
+		// Act
+		summary, err := r.Run(context.Background())
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if summary.Applied != 2 || strings.Join(handler.handled, ",") != "go,python" {
+			t.Errorf("expected offsets 1 and 2 to be applied, got %v", handler.handled)
+		}
+	})
+
+	t.Run("should start at the first offset after from time", func(t *testing.T) {
+		// Arrange
+		consumer := yield(t, 7, "go")
+		handler := &handlerMock{}
+		options := Options{Topic: "skill", Partition: -1, FromOffset: NoOffset, ToOffset: NoOffset, FromTime: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)}
+		r := NewReplayer(offsetsMock{newest: 8, atTime: 7}, consumer, handler, options)
+
+		// Act
+		summary, err := r.Run(context.Background())
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if summary.Applied != 1 {
+			t.Errorf("expected 1 applied message, got %+v", summary)
+		}
+	})
+
+	t.Run("should print messages without applying them on dry run", func(t *testing.T) {
+		// Arrange
+		consumer := yield(t, 0, "go")
+		handler := &handlerMock{}
+		var out bytes.Buffer
+		options := Options{Topic: "skill", Partition: -1, FromOffset: NoOffset, ToOffset: NoOffset, DryRun: true, Out: &out}
+		r := NewReplayer(offsetsMock{newest: 1}, consumer, handler, options)
+
+		// Act
+		summary, err := r.Run(context.Background())
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if len(handler.handled) != 0 {
+			t.Errorf("expected nothing to be applied, got %v", handler.handled)
+		}
+
+		want := "partition: 0, offset: 0, timestamp: 2024-07-01T00:00:00Z, action: update_name, key: go\n"
+		if out.String() != want {
+			t.Errorf("expected %q, got %q", want, out.String())
+		}
+
+		if summary.Applied != 0 || summary.Decoded != 1 {
+			t.Errorf("expected 1 decoded and none applied, got %+v", summary)
+		}
+	})
+
+	t.Run("should reject a range starting at both an offset and a time", func(t *testing.T) {
+		// Arrange
+		options := Options{Topic: "skill", Partition: -1, FromOffset: 1, ToOffset: NoOffset, FromTime: time.Now()}
+		r := NewReplayer(offsetsMock{}, mocks.NewConsumer(t, nil), &handlerMock{}, options)
+
+		// Act
+		_, err := r.Run(context.Background())
+
+		// Assert
+		if !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("expected ErrInvalidRange, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package replay
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"regexp"
+)
+
+var ErrInvalidSchema = errors.New("schema must be a lowercase identifier")
+
+var schemaName = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+func ValidSchema(schema string) bool {
+	return schemaName.MatchString(schema)
+}
+
+// schemaTables are the tables the consumer writes a skill change to, which a
+// replay schema needs its own copy of.
+var schemaTables = []string{"skill", "skill_history", "skill_tag", "skill_event_outbox"}
+
+// PrepareSchema creates schema holding a table shaped like each live one in
+// schemaTables, unless it already exists. Replaying into it rebuilds the
+// skills without touching the live tables. The tag counts are taken from the
+// skills already in schema, never from the live ones, so a replay can carry
+// on from where an earlier one into the same schema stopped.
+func PrepareSchema(db *sql.DB, schema string) error {
+	if !ValidSchema(schema) {
+		return ErrInvalidSchema
+	}
+
+	name := pq.QuoteIdentifier(schema)
+	if _, err := db.Exec(`CREATE SCHEMA IF NOT EXISTS ` + name); err != nil {
+		return err
+	}
+
+	for _, table := range schemaTables {
+		qry := `CREATE TABLE IF NOT EXISTS ` + name + `.` + table + ` (LIKE public.` + table + ` INCLUDING ALL)`
+		if _, err := db.Exec(qry); err != nil {
+			return err
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM ` + name + `.skill_tag`); err != nil {
+		return err
+	}
+	qry := `INSERT INTO ` + name + `.skill_tag (tag, count)
+SELECT tag, count(DISTINCT key) FROM ` + name + `.skill, unnest(tags) AS tag WHERE deleted_at IS NULL GROUP BY tag`
+	if _, err := tx.Exec(qry); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,103 @@
+package replay
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"modernc.org/sqlite"
+	"skill-api-kafka-consumer/skill"
+	"skill-api-kafka-contract/message"
+	"testing"
+)
+
+// init stands in for the Postgres text search functions the skill storage
+// keeps the search column with.
+func init() {
+	for _, name := range []string{"to_tsvector", "array_to_string", "setweight"} {
+		sqlite.MustRegisterDeterministicScalarFunction(name, 2, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			return "", nil
+		})
+	}
+}
+
+// schemaTablesDDL is each table in schemaTables as sqlite can create it.
+var schemaTablesDDL = map[string]string{
+	"skill": `(key TEXT PRIMARY KEY, name TEXT NOT NULL DEFAULT '', description TEXT NOT NULL DEFAULT '',
+logo TEXT NOT NULL DEFAULT '', tags TEXT [] NOT NULL DEFAULT '{}', version INTEGER NOT NULL DEFAULT 1,
+updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, deleted_at TIMESTAMP, search TEXT NOT NULL DEFAULT '')`,
+	"skill_history": `(id INTEGER PRIMARY KEY AUTOINCREMENT, skill_key TEXT NOT NULL, action TEXT NOT NULL,
+before TEXT, after TEXT, actor TEXT NOT NULL DEFAULT '', message_id TEXT NOT NULL DEFAULT '',
+occurred_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP)`,
+	"skill_tag": `(tag TEXT PRIMARY KEY, count INTEGER NOT NULL DEFAULT 0)`,
+	"skill_event_outbox": `(id INTEGER PRIMARY KEY AUTOINCREMENT, key TEXT NOT NULL, value BLOB NOT NULL,
+headers TEXT NOT NULL DEFAULT '{}', created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, sent_at TIMESTAMP)`,
+}
+
+// newSchemaDB opens a database whose only tables are schemaTables in an
+// attached schema, which unqualified names resolve to like they do to the
+// replay schema once it is the search path.
+func newSchemaDB(t *testing.T, schema string) *sql.DB {
+	db, _ := sql.Open("sqlite", ":memory:")
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`ATTACH DATABASE ':memory:' AS ` + schema); err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range schemaTables {
+		ddl, ok := schemaTablesDDL[table]
+		if !ok {
+			t.Fatalf("no sqlite table for %s", table)
+		}
+		if _, err := db.Exec(`CREATE TABLE ` + schema + `.` + table + ` ` + ddl); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+// jsonHandler applies messages holding a bare JSON payload with the real
+// skill handler.
+type jsonHandler struct {
+	skill.SkillHandler
+}
+
+func (h jsonHandler) ValidateSkillMessage(contentType string, msg []byte) (*skill.SkillQueuePayload, error) {
+	var payload skill.SkillQueuePayload
+	if err := json.Unmarshal(msg, &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+func TestReplayIntoSchema(t *testing.T) {
+	t.Run("should create tagged skill in schema", func(t *testing.T) {
+		// Arrange
+		db := newSchemaDB(t, "replay_test")
+		service := skill.NewSkillService(skill.NewSkillStorage(db))
+		handler := jsonHandler{skill.NewSkillHandler(service, message.Serializers{})}
+		consumer := yield(t, 0, `{"version": 2, "message_id": "msg-1", "action": "create", "key": "go",
+"payload": {"key": "go", "name": "Go", "description": "Golang", "logo": "go.svg", "tags": ["backend", "go"]}}`)
+		options := Options{Topic: "skill", Partition: -1, FromOffset: NoOffset, ToOffset: NoOffset}
+		r := NewReplayer(offsetsMock{newest: 1}, consumer, handler, options)
+
+		// Act
+		summary, err := r.Run(context.Background())
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if summary.Applied != 1 {
+			t.Fatalf("expected 1 applied message, got %+v", summary)
+		}
+
+		var count int
+		db.QueryRow("SELECT count(*) FROM replay_test.skill_tag WHERE tag IN ('backend', 'go') AND count = 1").Scan(&count)
+		if count != 2 {
+			t.Errorf("expected backend and go to be counted in schema, got %d", count)
+		}
+	})
+}
@@ -1,53 +1,76 @@
 package skill
 
+import "context"
+
 type mockSkillService struct {
 	SkillService
 	err error
 }
 
-func (s mockSkillService) CreateSkill(payload SkillQueuePayload) error {
+func (s mockSkillService) CreateSkill(ctx context.Context, payload SkillQueuePayload) error {
+	if s.err != nil {
+		return s.err
+	}
+	return nil
+}
+
+func (s mockSkillService) UpdateSkill(ctx context.Context, payload SkillQueuePayload) error {
+	if s.err != nil {
+		return s.err
+	}
+	return nil
+}
+
+func (s mockSkillService) UpdateName(ctx context.Context, payload SkillQueuePayload) error {
+	if s.err != nil {
+		return s.err
+	}
+	return nil
+}
+
+func (s mockSkillService) UpdateDescription(ctx context.Context, payload SkillQueuePayload) error {
 	if s.err != nil {
 		return s.err
 	}
 	return nil
 }
 
-func (s mockSkillService) UpdateSkill(payload SkillQueuePayload) error {
+func (s mockSkillService) UpdateLogo(ctx context.Context, payload SkillQueuePayload) error {
 	if s.err != nil {
 		return s.err
 	}
 	return nil
 }
 
-func (s mockSkillService) UpdateName(payload SkillQueuePayload) error {
+func (s mockSkillService) UpdateTags(ctx context.Context, payload SkillQueuePayload) error {
 	if s.err != nil {
 		return s.err
 	}
 	return nil
 }
 
-func (s mockSkillService) UpdateDescription(payload SkillQueuePayload) error {
+func (s mockSkillService) AddTags(ctx context.Context, payload SkillQueuePayload) error {
 	if s.err != nil {
 		return s.err
 	}
 	return nil
 }
 
-func (s mockSkillService) UpdateLogo(payload SkillQueuePayload) error {
+func (s mockSkillService) RemoveTags(ctx context.Context, payload SkillQueuePayload) error {
 	if s.err != nil {
 		return s.err
 	}
 	return nil
 }
 
-func (s mockSkillService) UpdateTags(payload SkillQueuePayload) error {
+func (s mockSkillService) DeleteSkill(ctx context.Context, payload SkillQueuePayload) error {
 	if s.err != nil {
 		return s.err
 	}
 	return nil
 }
 
-func (s mockSkillService) DeleteSkill(payload SkillQueuePayload) error {
+func (s mockSkillService) RestoreSkill(ctx context.Context, payload SkillQueuePayload) error {
 	if s.err != nil {
 		return s.err
 	}
@@ -1,55 +1,181 @@
 package skill
 
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
 type mockSkillStorage struct {
 	SkillStorage
-	err error
+	err     error
+	skill   *Skill
+	deleted *Skill
+	history []HistoryEntry
+	// purged is how many skills PurgeDeleted reports, before what it was
+	// called with.
+	purged int64
+	before time.Time
+	// addedTags and removedTags are what AddTags and RemoveTags were last
+	// called with.
+	addedTags   []string
+	removedTags []string
+	// events are what EnqueueEvent was called with, unless eventErr is set.
+	events   []SkillEvent
+	eventErr error
+	// countedTags and uncountedTags are what CountTags was last called with.
+	countedTags   []string
+	uncountedTags []string
+}
+
+func (m *mockSkillStorage) GetSkill(ctx context.Context, key string) (*Skill, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.skill == nil {
+		return nil, sql.ErrNoRows
+	}
+	skill := *m.skill
+	return &skill, nil
+}
+
+func (m *mockSkillStorage) GetDeletedSkill(ctx context.Context, key string) (*Skill, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.deleted == nil {
+		return nil, sql.ErrNoRows
+	}
+	skill := *m.deleted
+	return &skill, nil
+}
+
+func (m *mockSkillStorage) CreateSkill(ctx context.Context, req CreateSkillRequest) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.skill = &Skill{Key: req.Key, Name: req.Name, Description: req.Description, Logo: req.Logo, Tags: req.Tags}
+	return nil
+}
+
+func (m *mockSkillStorage) UpdateSkill(ctx context.Context, id string, skill UpdateSkillRequest) error {
+	if m.err != nil {
+		return m.err
+	}
+	return nil
+}
+
+func (m *mockSkillStorage) UpdateName(ctx context.Context, key string, name string) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.skill != nil {
+		skill := *m.skill
+		skill.Name = name
+		m.skill = &skill
+	}
+	return nil
+}
+
+func (m *mockSkillStorage) UpdateDescription(ctx context.Context, key string, desc string) error {
+	if m.err != nil {
+		return m.err
+	}
+	return nil
 }
 
-func (m *mockSkillStorage) CreateSkill(req CreateSkillRequest) error {
+func (m *mockSkillStorage) UpdateLogo(ctx context.Context, key string, logo string) error {
 	if m.err != nil {
 		return m.err
 	}
 	return nil
 }
 
-func (m *mockSkillStorage) UpdateSkill(id string, skill UpdateSkillRequest) error {
+func (m *mockSkillStorage) UpdateTags(ctx context.Context, key string, tag []string) error {
 	if m.err != nil {
 		return m.err
 	}
 	return nil
 }
 
-func (m *mockSkillStorage) UpdateName(key string, name string) error {
+func (m *mockSkillStorage) AddTags(ctx context.Context, key string, tags []string) error {
 	if m.err != nil {
 		return m.err
 	}
+	m.addedTags = tags
 	return nil
 }
 
-func (m *mockSkillStorage) UpdateDescription(key string, desc string) error {
+func (m *mockSkillStorage) RemoveTags(ctx context.Context, key string, tags []string) error {
 	if m.err != nil {
 		return m.err
 	}
+	m.removedTags = tags
 	return nil
 }
 
-func (m *mockSkillStorage) UpdateLogo(key string, logo string) error {
+func (m *mockSkillStorage) DeleteSkill(ctx context.Context, key string) error {
 	if m.err != nil {
 		return m.err
 	}
+	m.deleted, m.skill = m.skill, nil
 	return nil
 }
 
-func (m *mockSkillStorage) UpdateTags(key string, tag []string) error {
+func (m *mockSkillStorage) RestoreSkill(ctx context.Context, key string) error {
 	if m.err != nil {
 		return m.err
 	}
+	if m.deleted != nil {
+		m.skill, m.deleted = m.deleted, nil
+	}
 	return nil
 }
 
-func (m *mockSkillStorage) DeleteSkill(key string) error {
+func (m *mockSkillStorage) RecordHistory(ctx context.Context, entry HistoryEntry) error {
 	if m.err != nil {
 		return m.err
 	}
+	m.history = append(m.history, entry)
 	return nil
 }
+
+func (m *mockSkillStorage) CountTags(ctx context.Context, added []string, removed []string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.countedTags, m.uncountedTags = added, removed
+	return nil
+}
+
+func (m *mockSkillStorage) HasMessage(ctx context.Context, id string) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	for _, entry := range m.history {
+		if entry.MessageID == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockSkillStorage) EnqueueEvent(ctx context.Context, event SkillEvent) error {
+	if m.eventErr != nil {
+		return m.eventErr
+	}
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *mockSkillStorage) InTx(ctx context.Context, fn func(SkillStorage) error) error {
+	return fn(m)
+}
+
+func (m *mockSkillStorage) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.before = before
+	return m.purged, nil
+}
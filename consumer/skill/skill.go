@@ -1,77 +1,46 @@
 package skill
 
 import (
-	"encoding/json"
 	"errors"
-	"log"
+	"skill-api-kafka-contract/message"
 )
 
-type SkillAction string
+// The Kafka message contract is shared with the API through the message
+// module, these aliases keep the names this package has always used.
+type (
+	SkillAction                   = message.SkillAction
+	SkillQueuePayload             = message.SkillQueuePayload
+	CreateSkillRequest            = message.CreateSkillRequest
+	UpdateSkillRequest            = message.UpdateSkillRequest
+	UpdateSkillNameRequest        = message.UpdateSkillNameRequest
+	UpdateSkillDescriptionRequest = message.UpdateSkillDescriptionRequest
+	UpdateSkillLogoRequest        = message.UpdateSkillLogoRequest
+	UpdateSkillTagsRequest        = message.UpdateSkillTagsRequest
+	AddSkillTagsRequest           = message.AddSkillTagsRequest
+	RemoveSkillTagsRequest        = message.RemoveSkillTagsRequest
+)
 
 const (
-	CreateSkillAction SkillAction = "create"
-	UpdateSkillAction SkillAction = "update"
-	DeleteSkillAction SkillAction = "delete"
-	UpdateNameAction  SkillAction = "update_name"
-	UpdateDescAction  SkillAction = "update_desc"
-	UpdateLogoAction  SkillAction = "update_logo"
-	UpdateTagsAction  SkillAction = "update_tags"
+	CreateSkillAction = message.CreateSkillAction
+	UpdateSkillAction = message.UpdateSkillAction
+	DeleteSkillAction = message.DeleteSkillAction
+	UpdateNameAction  = message.UpdateNameAction
+	UpdateDescAction  = message.UpdateDescAction
+	UpdateLogoAction  = message.UpdateLogoAction
+	UpdateTagsAction  = message.UpdateTagsAction
+
+	RestoreSkillAction = message.RestoreSkillAction
+	AddTagsAction      = message.AddTagsAction
+	RemoveTagsAction   = message.RemoveTagsAction
 )
 
 var (
-	ErrInvalidSkillAction = errors.New("invalid skill action")
+	ErrInvalidSkillAction = message.ErrInvalidSkillAction
 	ErrorInvalidPayload   = errors.New("invalid payload")
+	// ErrVersionConflict rejects a change made against a version of the
+	// skill that is no longer current.
+	ErrVersionConflict = errors.New("version conflict")
+	// ErrSkillNotFound rejects a change to a skill that does not exist, or
+	// is not in the state the change applies to.
+	ErrSkillNotFound = errors.New("skill not found")
 )
-
-type SkillQueuePayload struct {
-	Action  SkillAction `json:"action"`
-	Key     *string     `json:"key"`
-	Payload any         `json:"payload"`
-}
-
-type UpdateSkillRequest struct {
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	Logo        string   `json:"logo"`
-	Tags        []string `json:"tags"`
-}
-
-type CreateSkillRequest struct {
-	Key         string   `json:"key"`
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	Logo        string   `json:"logo"`
-	Tags        []string `json:"tags"`
-}
-
-type UpdateSkillNameRequest struct {
-	Name string `json:"name"`
-}
-
-type UpdateSkillDescriptionRequest struct {
-	Description string `json:"description"`
-}
-
-type UpdateSkillLogoRequest struct {
-	Logo string `json:"logo"`
-}
-
-type UpdateSkillTagsRequest struct {
-	Tags []string `json:"tags"`
-}
-
-func ConvertSkillType[t UpdateSkillTagsRequest | UpdateSkillLogoRequest | UpdateSkillDescriptionRequest | UpdateSkillNameRequest | UpdateSkillRequest | CreateSkillRequest](skill any) (*t, error) {
-	byteData, err := json.Marshal(skill)
-	if err != nil {
-		log.Println(err)
-		return nil, err
-	}
-
-	var payload *t
-	err = json.Unmarshal(byteData, &payload)
-	if err != nil {
-		return nil, err
-	}
-
-	return payload, nil
-}
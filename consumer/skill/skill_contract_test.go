@@ -0,0 +1,143 @@
+package skill
+
+import (
+	"context"
+	"skill-api-kafka-contract/message"
+	"skill-api-kafka-contract/schema"
+	"testing"
+)
+
+type recordingSkillService struct {
+	calls []string
+}
+
+func (s *recordingSkillService) CreateSkill(ctx context.Context, payload SkillQueuePayload) error {
+	s.calls = append(s.calls, "CreateSkill")
+	return nil
+}
+
+func (s *recordingSkillService) UpdateSkill(ctx context.Context, payload SkillQueuePayload) error {
+	s.calls = append(s.calls, "UpdateSkill")
+	return nil
+}
+
+func (s *recordingSkillService) UpdateName(ctx context.Context, payload SkillQueuePayload) error {
+	s.calls = append(s.calls, "UpdateName")
+	return nil
+}
+
+func (s *recordingSkillService) UpdateDescription(ctx context.Context, payload SkillQueuePayload) error {
+	s.calls = append(s.calls, "UpdateDescription")
+	return nil
+}
+
+func (s *recordingSkillService) UpdateLogo(ctx context.Context, payload SkillQueuePayload) error {
+	s.calls = append(s.calls, "UpdateLogo")
+	return nil
+}
+
+func (s *recordingSkillService) UpdateTags(ctx context.Context, payload SkillQueuePayload) error {
+	s.calls = append(s.calls, "UpdateTags")
+	return nil
+}
+
+func (s *recordingSkillService) AddTags(ctx context.Context, payload SkillQueuePayload) error {
+	s.calls = append(s.calls, "AddTags")
+	return nil
+}
+
+func (s *recordingSkillService) RemoveTags(ctx context.Context, payload SkillQueuePayload) error {
+	s.calls = append(s.calls, "RemoveTags")
+	return nil
+}
+
+func (s *recordingSkillService) DeleteSkill(ctx context.Context, payload SkillQueuePayload) error {
+	s.calls = append(s.calls, "DeleteSkill")
+	return nil
+}
+
+func (s *recordingSkillService) RestoreSkill(ctx context.Context, payload SkillQueuePayload) error {
+	s.calls = append(s.calls, "RestoreSkill")
+	return nil
+}
+
+// TestHandleSkillFollowsContract runs every message the API may publish
+// through validation and dispatch. A new action in the contract fails here
+// until the consumer handles it.
+func TestHandleSkillFollowsContract(t *testing.T) {
+	handled := map[string]SkillAction{}
+	registry, err := schema.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	protobuf, err := message.NewProtobufSerializer(registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, example := range message.Examples() {
+		t.Run(string(example.Action), func(t *testing.T) {
+			// Arrange
+			s := &recordingSkillService{}
+			h := NewSkillHandler(s, newSerializers(t))
+			data, err := message.Encode(example)
+			if err != nil {
+				t.Fatal(err)
+			}
+			protobufData, err := protobuf.Marshal(example)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Act
+			fromProtobuf, protobufErr := h.ValidateSkillMessage(protobuf.ContentType(), protobufData)
+			payload, err := h.ValidateSkillMessage(message.ContentTypeJSON, data)
+			if err != nil || protobufErr != nil {
+				t.Fatalf("expected message to be valid, got json %v, protobuf %v", err, protobufErr)
+			}
+			err = h.HandleSkill(context.Background(), payload)
+			if protobufErr := h.HandleSkill(context.Background(), fromProtobuf); protobufErr != nil {
+				t.Errorf("expected protobuf message to be handled, got %s", protobufErr)
+			}
+
+			// Assert
+			if err != nil {
+				t.Fatalf("expected action to be handled, got %s", err)
+			}
+
+			if len(s.calls) != 2 || s.calls[0] != s.calls[1] {
+				t.Fatalf("expected both formats to reach the same service call, got %v", s.calls)
+			}
+
+			if action, ok := handled[s.calls[0]]; ok {
+				t.Errorf("expected %s to have its own service call, %s already went to %s", example.Action, action, s.calls[0])
+			}
+			handled[s.calls[0]] = example.Action
+		})
+	}
+}
+
+func TestServiceDecodesContractPayloads(t *testing.T) {
+	for _, example := range message.Examples() {
+		t.Run(string(example.Action), func(t *testing.T) {
+			// Arrange
+			s := mockSkillStorage{skill: &Skill{Key: "go", Version: example.ExpectedVersion}}
+			switch example.Action {
+			case CreateSkillAction:
+				s = mockSkillStorage{}
+			case RestoreSkillAction:
+				s = mockSkillStorage{deleted: &Skill{Key: "go", Version: example.ExpectedVersion}}
+			}
+			service := NewSkillService(&s)
+			h := NewSkillHandler(service, newSerializers(t))
+
+			// Act
+			err := h.HandleSkill(context.Background(), &example)
+
+			// Assert
+			if err != nil {
+				t.Errorf("expected %s payload to be accepted, got %s", example.Action, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package skill
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+
+	"github.com/lib/pq"
+)
+
+// IsTransientError reports whether err may go away when the same message is
+// applied again, such as a dropped connection or a serialization failure.
+// Anything it does not recognise is treated as permanent.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, ErrorInvalidPayload) || errors.Is(err, ErrInvalidSkillAction) || errors.Is(err, ErrVersionConflict) || errors.Is(err, ErrSkillNotFound) {
+		return false
+	}
+
+	if errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Class() {
+		// connection exception, transaction rollback (serialization failure,
+		// deadlock), insufficient resources and operator intervention
+		case "08", "40", "53", "57":
+			return true
+		default:
+			return false
+		}
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
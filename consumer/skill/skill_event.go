@@ -0,0 +1,30 @@
+package skill
+
+import "time"
+
+// EventTypeHeader carries the type of an event, so consumers of the event
+// topic can pick the ones they need without decoding the rest.
+const EventTypeHeader = "x-event-type"
+
+type SkillEventType string
+
+const (
+	SkillCreatedEvent            SkillEventType = "SkillCreated"
+	SkillUpdatedEvent            SkillEventType = "SkillUpdated"
+	SkillNameChangedEvent        SkillEventType = "SkillNameChanged"
+	SkillDescriptionChangedEvent SkillEventType = "SkillDescriptionChanged"
+	SkillLogoChangedEvent        SkillEventType = "SkillLogoChanged"
+	SkillTagsChangedEvent        SkillEventType = "SkillTagsChanged"
+	SkillDeletedEvent            SkillEventType = "SkillDeleted"
+	SkillRestoredEvent           SkillEventType = "SkillRestored"
+)
+
+// SkillEvent is a fact about a change the consumer applied. Before is nil for
+// a created or restored skill and After is nil for a deleted one.
+type SkillEvent struct {
+	Type       SkillEventType `json:"type"`
+	Key        string         `json:"key"`
+	Before     *Skill         `json:"before"`
+	After      *Skill         `json:"after"`
+	OccurredAt time.Time      `json:"occurred_at"`
+}
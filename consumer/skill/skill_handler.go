@@ -1,70 +1,74 @@
 package skill
 
 import (
-	"encoding/json"
-	"errors"
+	"context"
+	"skill-api-kafka-contract/message"
 )
 
 type SkillService interface {
-	CreateSkill(payload SkillQueuePayload) error
-	UpdateSkill(payload SkillQueuePayload) error
-	UpdateName(payload SkillQueuePayload) error
-	UpdateDescription(payload SkillQueuePayload) error
-	UpdateLogo(payload SkillQueuePayload) error
-	UpdateTags(payload SkillQueuePayload) error
-	DeleteSkill(payload SkillQueuePayload) error
+	CreateSkill(ctx context.Context, payload SkillQueuePayload) error
+	UpdateSkill(ctx context.Context, payload SkillQueuePayload) error
+	UpdateName(ctx context.Context, payload SkillQueuePayload) error
+	UpdateDescription(ctx context.Context, payload SkillQueuePayload) error
+	UpdateLogo(ctx context.Context, payload SkillQueuePayload) error
+	UpdateTags(ctx context.Context, payload SkillQueuePayload) error
+	AddTags(ctx context.Context, payload SkillQueuePayload) error
+	RemoveTags(ctx context.Context, payload SkillQueuePayload) error
+	DeleteSkill(ctx context.Context, payload SkillQueuePayload) error
+	RestoreSkill(ctx context.Context, payload SkillQueuePayload) error
 }
 
 type SkillHandler interface {
-	HandleSkill(payload *SkillQueuePayload) error
-	ValidateSkillMessage(msg []byte) (*SkillQueuePayload, error)
+	HandleSkill(ctx context.Context, payload *SkillQueuePayload) error
+	ValidateSkillMessage(contentType string, msg []byte) (*SkillQueuePayload, error)
 }
 
 type skillHandler struct {
 	skillService SkillService
+	serializers  message.Serializers
 }
 
-func NewSkillHandler(skillService SkillService) skillHandler {
+func NewSkillHandler(skillService SkillService, serializers message.Serializers) skillHandler {
 	return skillHandler{
 		skillService: skillService,
+		serializers:  serializers,
 	}
 }
 
-func (h skillHandler) HandleSkill(payload *SkillQueuePayload) error {
+func (h skillHandler) HandleSkill(ctx context.Context, payload *SkillQueuePayload) error {
 	switch payload.Action {
 	case CreateSkillAction:
-		return h.skillService.CreateSkill(*payload)
+		return h.skillService.CreateSkill(ctx, *payload)
 	case UpdateSkillAction:
-		return h.skillService.UpdateSkill(*payload)
+		return h.skillService.UpdateSkill(ctx, *payload)
 	case DeleteSkillAction:
-		return h.skillService.DeleteSkill(*payload)
+		return h.skillService.DeleteSkill(ctx, *payload)
 	case UpdateNameAction:
-		return h.skillService.UpdateName(*payload)
+		return h.skillService.UpdateName(ctx, *payload)
 	case UpdateDescAction:
-		return h.skillService.UpdateDescription(*payload)
+		return h.skillService.UpdateDescription(ctx, *payload)
 	case UpdateLogoAction:
-		return h.skillService.UpdateLogo(*payload)
+		return h.skillService.UpdateLogo(ctx, *payload)
 	case UpdateTagsAction:
-		return h.skillService.UpdateTags(*payload)
+		return h.skillService.UpdateTags(ctx, *payload)
+	case AddTagsAction:
+		return h.skillService.AddTags(ctx, *payload)
+	case RemoveTagsAction:
+		return h.skillService.RemoveTags(ctx, *payload)
+	case RestoreSkillAction:
+		return h.skillService.RestoreSkill(ctx, *payload)
 	default:
 		return ErrInvalidSkillAction
 	}
 }
 
-func (h skillHandler) ValidateSkillMessage(msg []byte) (*SkillQueuePayload, error) {
-	var payload *SkillQueuePayload
-	err := json.Unmarshal(msg, &payload)
+// ValidateSkillMessage decodes the message with the serializer for its
+// content type, so HandleSkill only ever sees the current shape.
+func (h skillHandler) ValidateSkillMessage(contentType string, msg []byte) (*SkillQueuePayload, error) {
+	serializer, err := h.serializers.Lookup(contentType)
 	if err != nil {
 		return nil, err
 	}
 
-	if payload.Action == "" {
-		return nil, errors.New("action is empty")
-	}
-
-	if payload.Key == nil {
-		return nil, errors.New("key is nil")
-	}
-
-	return payload, nil
+	return serializer.Unmarshal(msg)
 }
@@ -1,18 +1,129 @@
 package skill
 
 import (
+	"context"
 	"errors"
+	"skill-api-kafka-contract/message"
+	"skill-api-kafka-contract/schema"
 	"testing"
 )
 
+func newSerializers(t *testing.T) message.Serializers {
+	t.Helper()
+
+	registry, err := schema.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serializers, err := message.NewSerializers(registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serializers.Register(message.ContentTypeJSON, NewJSONSerializer(DefaultUpcasters()))
+	return serializers
+}
+
 func TestValidateSkillMessageHandler(t *testing.T) {
 	t.Run("should be able to validate skill message", func(t *testing.T) {
 		// Arrange
 		s := mockSkillService{}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
+
+		// Act
+		_, err := h.ValidateSkillMessage(message.ContentTypeJSON, []byte(`{"version":2,"action":"create","key":"python","payload":{"key":"python","name":"Python","description":"Python","logo":"logo","tags":[]}}`))
+
+		// Assert
+		if err != nil {
+			t.Errorf("expected no error, got %s", err)
+		}
+	})
+
+	t.Run("should upcast message sent before the envelope", func(t *testing.T) {
+		// Arrange
+		s := mockSkillService{}
+		h := NewSkillHandler(s, newSerializers(t))
+
+		// Act
+		payload, err := h.ValidateSkillMessage(message.ContentTypeJSON, []byte(`{"action":"update_name","key":"python","payload":{"name":"Python"}}`))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if payload.Version != PayloadVersion || payload.Action != UpdateNameAction || *payload.Key != "python" {
+			t.Errorf("expected version %d update_name for python, got %+v", PayloadVersion, payload)
+		}
+	})
+
+	t.Run("should decode envelope of current version", func(t *testing.T) {
+		// Arrange
+		s := mockSkillService{}
+		h := NewSkillHandler(s, newSerializers(t))
+
+		// Act
+		payload, err := h.ValidateSkillMessage(message.ContentTypeJSON, []byte(`{"version":2,"message_id":"msg-1","timestamp":"2024-01-02T03:04:05Z","producer":"skill-api/a","action":"delete","key":"python"}`))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if payload.MessageID != "msg-1" || payload.Producer != "skill-api/a" || payload.Timestamp.IsZero() {
+			t.Errorf("expected envelope fields to be decoded, got %+v", payload)
+		}
+	})
+
+	t.Run("should not be able to perform when version is unsupported", func(t *testing.T) {
+		// Arrange
+		s := mockSkillService{}
+		h := NewSkillHandler(s, newSerializers(t))
+
+		// Act
+		_, err := h.ValidateSkillMessage(message.ContentTypeJSON, []byte(`{"version":99,"action":"create","key":"python"}`))
+
+		// Assert
+		if !errors.Is(err, ErrUnsupportedVersion) {
+			t.Errorf("expected %s, got %v", ErrUnsupportedVersion, err)
+		}
+	})
+
+	t.Run("should decode protobuf message", func(t *testing.T) {
+		// Arrange
+		s := mockSkillService{}
+		h := NewSkillHandler(s, newSerializers(t))
+		registry, _ := schema.Open("")
+		protobuf, _ := message.NewProtobufSerializer(registry)
+		key := "python"
+		data, _ := protobuf.Marshal(SkillQueuePayload{
+			Version: PayloadVersion,
+			Action:  UpdateTagsAction,
+			Key:     &key,
+			Payload: UpdateSkillTagsRequest{Tags: []string{"backend"}},
+		})
+
+		// Act
+		payload, err := h.ValidateSkillMessage(protobuf.ContentType(), data)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		req, ok := payload.Payload.(UpdateSkillTagsRequest)
+		if !ok || len(req.Tags) != 1 || req.Tags[0] != "backend" {
+			t.Errorf("expected typed tags request with backend, got %#v", payload.Payload)
+		}
+	})
+
+	t.Run("should treat message without content type as json", func(t *testing.T) {
+		// Arrange
+		s := mockSkillService{}
+		h := NewSkillHandler(s, newSerializers(t))
 
 		// Act
-		_, err := h.ValidateSkillMessage([]byte(`{"action":"create","key":"python"}`))
+		_, err := h.ValidateSkillMessage("", []byte(`{"action":"delete","key":"python"}`))
 
 		// Assert
 		if err != nil {
@@ -20,13 +131,27 @@ func TestValidateSkillMessageHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("should not be able to perform when content type is unsupported", func(t *testing.T) {
+		// Arrange
+		s := mockSkillService{}
+		h := NewSkillHandler(s, newSerializers(t))
+
+		// Act
+		_, err := h.ValidateSkillMessage("application/avro", []byte(`{}`))
+
+		// Assert
+		if !errors.Is(err, message.ErrUnsupportedContentType) {
+			t.Errorf("expected %s, got %v", message.ErrUnsupportedContentType, err)
+		}
+	})
+
 	t.Run("should not be able to perform when message is empty", func(t *testing.T) {
 		// Arrange
 		s := mockSkillService{}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
 
 		// Act
-		_, err := h.ValidateSkillMessage([]byte(``))
+		_, err := h.ValidateSkillMessage(message.ContentTypeJSON, []byte(``))
 
 		// Assert
 		if err == nil {
@@ -37,10 +162,10 @@ func TestValidateSkillMessageHandler(t *testing.T) {
 	t.Run("should not be able to perform when action is empty", func(t *testing.T) {
 		// Arrange
 		s := mockSkillService{}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
 
 		// Act
-		_, err := h.ValidateSkillMessage([]byte(`{"data" : "test"}`))
+		_, err := h.ValidateSkillMessage(message.ContentTypeJSON, []byte(`{"data" : "test"}`))
 
 		// Assert
 		if err.Error() != "action is empty" {
@@ -48,13 +173,27 @@ func TestValidateSkillMessageHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("should not be able to perform when payload does not match action", func(t *testing.T) {
+		// Arrange
+		s := mockSkillService{}
+		h := NewSkillHandler(s, newSerializers(t))
+
+		// Act
+		_, err := h.ValidateSkillMessage(message.ContentTypeJSON, []byte(`{"version":2,"action":"update_name","key":"python","payload":{"logo":"logo"}}`))
+
+		// Assert
+		if err == nil {
+			t.Error("expected error to be not nil")
+		}
+	})
+
 	t.Run("should not be able to perform without key", func(t *testing.T) {
 		// Arrange
 		s := mockSkillService{}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
 
 		// Act
-		_, err := h.ValidateSkillMessage([]byte(`{"action":"create"}`))
+		_, err := h.ValidateSkillMessage(message.ContentTypeJSON, []byte(`{"action":"create"}`))
 
 		// Assert
 		if err.Error() != "key is nil" {
@@ -67,10 +206,10 @@ func TestHandleSkill(t *testing.T) {
 	t.Run("should be able to handle skill", func(t *testing.T) {
 		// Arrange
 		s := mockSkillService{}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
 
 		// Act
-		err := h.HandleSkill(&SkillQueuePayload{
+		err := h.HandleSkill(context.Background(), &SkillQueuePayload{
 			Action:  CreateSkillAction,
 			Key:     nil,
 			Payload: nil,
@@ -85,10 +224,10 @@ func TestHandleSkill(t *testing.T) {
 	t.Run("should not be able to handle skill when action is invalid", func(t *testing.T) {
 		// Arrange
 		s := mockSkillService{}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
 
 		// Act
-		err := h.HandleSkill(&SkillQueuePayload{
+		err := h.HandleSkill(context.Background(), &SkillQueuePayload{
 			Action:  "invalid",
 			Key:     nil,
 			Payload: nil,
@@ -105,11 +244,11 @@ func TestHandleCreateSkill(t *testing.T) {
 	t.Run("should be able to create new skill", func(t *testing.T) {
 		// Arrange
 		s := mockSkillService{}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
 		key := "python"
 
 		// Act
-		err := h.HandleSkill(&SkillQueuePayload{
+		err := h.HandleSkill(context.Background(), &SkillQueuePayload{
 			Action: CreateSkillAction,
 			Key:    &key,
 			Payload: &CreateSkillRequest{
@@ -132,11 +271,11 @@ func TestHandleCreateSkill(t *testing.T) {
 		s := mockSkillService{
 			err: errors.New("error"),
 		}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
 		key := "python"
 
 		// Act
-		err := h.HandleSkill(&SkillQueuePayload{
+		err := h.HandleSkill(context.Background(), &SkillQueuePayload{
 			Action: CreateSkillAction,
 			Key:    &key,
 			Payload: &CreateSkillRequest{
@@ -159,11 +298,11 @@ func TestHandleUpdateSkill(t *testing.T) {
 	t.Run("should be able to update skill", func(t *testing.T) {
 		// Arrange
 		s := mockSkillService{}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
 		key := "python"
 
 		// Act
-		err := h.HandleSkill(&SkillQueuePayload{
+		err := h.HandleSkill(context.Background(), &SkillQueuePayload{
 			Action: UpdateSkillAction,
 			Key:    &key,
 			Payload: &UpdateSkillRequest{
@@ -185,11 +324,11 @@ func TestHandleUpdateSkill(t *testing.T) {
 		s := mockSkillService{
 			err: errors.New("error"),
 		}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
 		key := "python"
 
 		// Act
-		err := h.HandleSkill(&SkillQueuePayload{
+		err := h.HandleSkill(context.Background(), &SkillQueuePayload{
 			Action: UpdateSkillAction,
 			Key:    &key,
 			Payload: &UpdateSkillRequest{
@@ -211,11 +350,11 @@ func TestHandleUpdateName(t *testing.T) {
 	t.Run("should be able to update skill name", func(t *testing.T) {
 		// Arrange
 		s := mockSkillService{}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
 		key := "python"
 
 		// Act
-		err := h.HandleSkill(&SkillQueuePayload{
+		err := h.HandleSkill(context.Background(), &SkillQueuePayload{
 			Action: UpdateNameAction,
 			Key:    &key,
 			Payload: &UpdateSkillNameRequest{
@@ -234,11 +373,11 @@ func TestHandleUpdateName(t *testing.T) {
 		s := mockSkillService{
 			err: errors.New("error"),
 		}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
 		key := "python"
 
 		// Act
-		err := h.HandleSkill(&SkillQueuePayload{
+		err := h.HandleSkill(context.Background(), &SkillQueuePayload{
 			Action: UpdateNameAction,
 			Key:    &key,
 			Payload: &UpdateSkillNameRequest{
@@ -257,11 +396,11 @@ func TestHandleUpdateDescription(t *testing.T) {
 	t.Run("should be able to update skill description", func(t *testing.T) {
 		// Arrange
 		s := mockSkillService{}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
 		key := "python"
 
 		// Act
-		err := h.HandleSkill(&SkillQueuePayload{
+		err := h.HandleSkill(context.Background(), &SkillQueuePayload{
 			Action: UpdateDescAction,
 			Key:    &key,
 			Payload: &UpdateSkillDescriptionRequest{
@@ -280,11 +419,11 @@ func TestHandleUpdateDescription(t *testing.T) {
 		s := mockSkillService{
 			err: errors.New("error"),
 		}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
 		key := "python"
 
 		// Act
-		err := h.HandleSkill(&SkillQueuePayload{
+		err := h.HandleSkill(context.Background(), &SkillQueuePayload{
 			Action: UpdateDescAction,
 			Key:    &key,
 			Payload: &UpdateSkillDescriptionRequest{
@@ -303,11 +442,11 @@ func TestHandleUpdateLogo(t *testing.T) {
 	t.Run("should be able to update skill logo", func(t *testing.T) {
 		// Arrange
 		s := mockSkillService{}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
 		key := "python"
 
 		// Act
-		err := h.HandleSkill(&SkillQueuePayload{
+		err := h.HandleSkill(context.Background(), &SkillQueuePayload{
 			Action: UpdateLogoAction,
 			Key:    &key,
 			Payload: &UpdateSkillLogoRequest{
@@ -326,11 +465,11 @@ func TestHandleUpdateLogo(t *testing.T) {
 		s := mockSkillService{
 			err: errors.New("error"),
 		}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
 		key := "python"
 
 		// Act
-		err := h.HandleSkill(&SkillQueuePayload{
+		err := h.HandleSkill(context.Background(), &SkillQueuePayload{
 			Action: UpdateLogoAction,
 			Key:    &key,
 			Payload: &UpdateSkillLogoRequest{
@@ -349,11 +488,11 @@ func TestHandleUpdateTags(t *testing.T) {
 	t.Run("should be able to update skill tags", func(t *testing.T) {
 		// Arrange
 		s := mockSkillService{}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
 		key := "python"
 
 		// Act
-		err := h.HandleSkill(&SkillQueuePayload{
+		err := h.HandleSkill(context.Background(), &SkillQueuePayload{
 			Action: UpdateTagsAction,
 			Key:    &key,
 			Payload: &UpdateSkillTagsRequest{
@@ -372,11 +511,11 @@ func TestHandleUpdateTags(t *testing.T) {
 		s := mockSkillService{
 			err: errors.New("error"),
 		}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
 		key := "python"
 
 		// Act
-		err := h.HandleSkill(&SkillQueuePayload{
+		err := h.HandleSkill(context.Background(), &SkillQueuePayload{
 			Action: UpdateTagsAction,
 			Key:    &key,
 			Payload: &UpdateSkillTagsRequest{
@@ -391,15 +530,59 @@ func TestHandleUpdateTags(t *testing.T) {
 	})
 }
 
+func TestHandleTagEdits(t *testing.T) {
+	for _, action := range []SkillAction{AddTagsAction, RemoveTagsAction} {
+		t.Run("should be able to "+string(action), func(t *testing.T) {
+			// Arrange
+			s := mockSkillService{}
+			h := NewSkillHandler(s, newSerializers(t))
+			key := "python"
+
+			// Act
+			err := h.HandleSkill(context.Background(), &SkillQueuePayload{
+				Action:  action,
+				Key:     &key,
+				Payload: map[string]interface{}{"tags": []string{"tag1"}},
+			})
+
+			// Assert
+			if err != nil {
+				t.Errorf("expected no error, got %s", err)
+			}
+		})
+
+		t.Run("should error to "+string(action), func(t *testing.T) {
+			// Arrange
+			s := mockSkillService{
+				err: errors.New("error"),
+			}
+			h := NewSkillHandler(s, newSerializers(t))
+			key := "python"
+
+			// Act
+			err := h.HandleSkill(context.Background(), &SkillQueuePayload{
+				Action:  action,
+				Key:     &key,
+				Payload: map[string]interface{}{"tags": []string{"tag1"}},
+			})
+
+			// Assert
+			if err == nil || err.Error() != "error" {
+				t.Errorf("expected error, got %v", err)
+			}
+		})
+	}
+}
+
 func TestHandleDeleteSkill(t *testing.T) {
 	t.Run("should be able to delete skill", func(t *testing.T) {
 		// Arrange
 		s := mockSkillService{}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
 		key := "python"
 
 		// Act
-		err := h.HandleSkill(&SkillQueuePayload{
+		err := h.HandleSkill(context.Background(), &SkillQueuePayload{
 			Action: DeleteSkillAction,
 			Key:    &key,
 		})
@@ -415,11 +598,11 @@ func TestHandleDeleteSkill(t *testing.T) {
 		s := mockSkillService{
 			err: errors.New("error"),
 		}
-		h := NewSkillHandler(s)
+		h := NewSkillHandler(s, newSerializers(t))
 		key := "python"
 
 		// Act
-		err := h.HandleSkill(&SkillQueuePayload{
+		err := h.HandleSkill(context.Background(), &SkillQueuePayload{
 			Action: DeleteSkillAction,
 			Key:    &key,
 		})
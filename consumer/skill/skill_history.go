@@ -0,0 +1,35 @@
+package skill
+
+import "time"
+
+// HistoryEntry records one change applied to a skill. Before is nil for a
+// created skill and After is nil for a deleted one.
+type HistoryEntry struct {
+	Key        string
+	Action     SkillAction
+	Before     *Skill
+	After      *Skill
+	Actor      string
+	MessageID  string
+	OccurredAt time.Time
+}
+
+// newHistoryEntry describes the change payload made to the skill with key.
+// The time is when the change was requested, falling back to now for
+// messages that do not carry one.
+func newHistoryEntry(payload SkillQueuePayload, key string, before, after *Skill) HistoryEntry {
+	occurredAt := payload.Timestamp
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+
+	return HistoryEntry{
+		Key:        key,
+		Action:     payload.Action,
+		Before:     before,
+		After:      after,
+		Actor:      payload.Actor,
+		MessageID:  payload.MessageID,
+		OccurredAt: occurredAt.UTC(),
+	}
+}
@@ -0,0 +1,64 @@
+package skill
+
+import (
+	"context"
+	"log"
+	"skill-api-kafka-consumer/config"
+	"skill-api-kafka-consumer/metrics"
+	"time"
+)
+
+type TrashStorage interface {
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Purger empties the trash of skills deleted longer ago than the retention.
+// Purging is idempotent, so every consumer instance can run one.
+type Purger struct {
+	storage TrashStorage
+	config  config.PurgeConfig
+	now     func() time.Time
+}
+
+func NewPurger(storage TrashStorage, config config.PurgeConfig) Purger {
+	return Purger{
+		storage: storage,
+		config:  config,
+		now:     time.Now,
+	}
+}
+
+// Run purges once per interval until ctx is done. It returns straight away
+// when purging is disabled.
+func (p Purger) Run(ctx context.Context) {
+	if p.config.Retention == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := p.Purge(ctx); err != nil {
+				log.Println("Error purging deleted skills:", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p Purger) Purge(ctx context.Context) (int64, error) {
+	n, err := p.storage.PurgeDeleted(ctx, p.now().Add(-p.config.Retention))
+	if err != nil {
+		return 0, err
+	}
+
+	if n > 0 {
+		log.Printf("Purged %d deleted skills", n)
+		metrics.SkillsPurged.Add(float64(n))
+	}
+	return n, nil
+}
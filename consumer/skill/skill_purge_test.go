@@ -0,0 +1,50 @@
+package skill
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"skill-api-kafka-consumer/config"
+	"testing"
+	"time"
+)
+
+func TestPurger_Purge(t *testing.T) {
+	t.Run("should purge skills deleted before retention", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{purged: 2}
+		p := NewPurger(&s, config.PurgeConfig{Interval: time.Hour, Retention: 24 * time.Hour})
+		now := time.Date(2024, 7, 2, 0, 0, 0, 0, time.UTC)
+		p.now = func() time.Time { return now }
+
+		// Act
+		n, err := p.Purge(context.Background())
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected error to be nil, got %s", err)
+		}
+
+		if n != 2 {
+			t.Errorf("expected 2 skills purged but got %d", n)
+		}
+
+		if want := now.Add(-24 * time.Hour); !s.before.Equal(want) {
+			t.Errorf("expected skills deleted before %s to be purged but got %s", want, s.before)
+		}
+	})
+
+	t.Run("should return storage error", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{err: sql.ErrConnDone}
+		p := NewPurger(&s, config.PurgeConfig{Interval: time.Hour, Retention: 24 * time.Hour})
+
+		// Act
+		_, err := p.Purge(context.Background())
+
+		// Assert
+		if !errors.Is(err, sql.ErrConnDone) {
+			t.Errorf("expected error %s, got %v", sql.ErrConnDone, err)
+		}
+	})
+}
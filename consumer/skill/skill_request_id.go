@@ -0,0 +1,16 @@
+package skill
+
+import "context"
+
+type requestIDKey struct{}
+
+// WithRequestID carries the request ID of the message being applied, so log
+// lines written below the consumer can be matched to the API request.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -1,13 +1,34 @@
 package skill
 
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"skill-api-kafka-contract/message"
+	"sort"
+	"time"
+)
+
 type SkillStorage interface {
-	CreateSkill(req CreateSkillRequest) error
-	UpdateSkill(id string, skill UpdateSkillRequest) error
-	UpdateName(key string, name string) error
-	UpdateDescription(key string, desc string) error
-	UpdateLogo(key string, logo string) error
-	UpdateTags(key string, tag []string) error
-	DeleteSkill(key string) error
+	GetSkill(ctx context.Context, key string) (*Skill, error)
+	GetDeletedSkill(ctx context.Context, key string) (*Skill, error)
+	CreateSkill(ctx context.Context, req CreateSkillRequest) error
+	UpdateSkill(ctx context.Context, id string, skill UpdateSkillRequest) error
+	UpdateName(ctx context.Context, key string, name string) error
+	UpdateDescription(ctx context.Context, key string, desc string) error
+	UpdateLogo(ctx context.Context, key string, logo string) error
+	UpdateTags(ctx context.Context, key string, tag []string) error
+	AddTags(ctx context.Context, key string, tags []string) error
+	RemoveTags(ctx context.Context, key string, tags []string) error
+	DeleteSkill(ctx context.Context, key string) error
+	RestoreSkill(ctx context.Context, key string) error
+	RecordHistory(ctx context.Context, entry HistoryEntry) error
+	HasMessage(ctx context.Context, id string) (bool, error)
+	CountTags(ctx context.Context, added []string, removed []string) error
+	EnqueueEvent(ctx context.Context, event SkillEvent) error
+	InTx(ctx context.Context, fn func(SkillStorage) error) error
 }
 
 type skillService struct {
@@ -20,94 +41,239 @@ func NewSkillService(skillStorage SkillStorage) skillService {
 	}
 }
 
-func (s skillService) CreateSkill(payload SkillQueuePayload) error {
-	data, err := ConvertSkillType[CreateSkillRequest](payload.Payload)
+func (s skillService) CreateSkill(ctx context.Context, payload SkillQueuePayload) error {
+	data, err := message.DecodeRequest[CreateSkillRequest](payload.Payload)
 	if err != nil {
 		return ErrorInvalidPayload
 	}
 
-	err = s.skillStorage.CreateSkill(*data)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return s.apply(ctx, payload, SkillCreatedEvent, data.Key, func(storage SkillStorage) error {
+		return storage.CreateSkill(ctx, *data)
+	})
 }
 
-func (s skillService) UpdateSkill(payload SkillQueuePayload) error {
-	data, err := ConvertSkillType[UpdateSkillRequest](payload.Payload)
+func (s skillService) UpdateSkill(ctx context.Context, payload SkillQueuePayload) error {
+	data, err := message.DecodeRequest[UpdateSkillRequest](payload.Payload)
 	if err != nil {
 		return ErrorInvalidPayload
 	}
 
-	err = s.skillStorage.UpdateSkill(*payload.Key, *data)
+	return s.apply(ctx, payload, SkillUpdatedEvent, *payload.Key, func(storage SkillStorage) error {
+		return storage.UpdateSkill(ctx, *payload.Key, *data)
+	})
+}
+
+func (s skillService) UpdateName(ctx context.Context, payload SkillQueuePayload) error {
+	data, err := message.DecodeRequest[UpdateSkillNameRequest](payload.Payload)
 	if err != nil {
-		return err
+		return ErrorInvalidPayload
 	}
 
-	return nil
+	return s.apply(ctx, payload, SkillNameChangedEvent, *payload.Key, func(storage SkillStorage) error {
+		return storage.UpdateName(ctx, *payload.Key, data.Name)
+	})
 }
 
-func (s skillService) UpdateName(payload SkillQueuePayload) error {
-	data, err := ConvertSkillType[UpdateSkillNameRequest](payload.Payload)
+func (s skillService) UpdateDescription(ctx context.Context, payload SkillQueuePayload) error {
+	data, err := message.DecodeRequest[UpdateSkillDescriptionRequest](payload.Payload)
 	if err != nil {
 		return ErrorInvalidPayload
 	}
 
-	err = s.skillStorage.UpdateName(*payload.Key, data.Name)
+	return s.apply(ctx, payload, SkillDescriptionChangedEvent, *payload.Key, func(storage SkillStorage) error {
+		return storage.UpdateDescription(ctx, *payload.Key, data.Description)
+	})
+}
+
+func (s skillService) UpdateLogo(ctx context.Context, payload SkillQueuePayload) error {
+	data, err := message.DecodeRequest[UpdateSkillLogoRequest](payload.Payload)
 	if err != nil {
-		return err
+		return ErrorInvalidPayload
 	}
 
-	return nil
+	return s.apply(ctx, payload, SkillLogoChangedEvent, *payload.Key, func(storage SkillStorage) error {
+		return storage.UpdateLogo(ctx, *payload.Key, data.Logo)
+	})
 }
 
-func (s skillService) UpdateDescription(payload SkillQueuePayload) error {
-	data, err := ConvertSkillType[UpdateSkillDescriptionRequest](payload.Payload)
+func (s skillService) UpdateTags(ctx context.Context, payload SkillQueuePayload) error {
+	data, err := message.DecodeRequest[UpdateSkillTagsRequest](payload.Payload)
 	if err != nil {
 		return ErrorInvalidPayload
 	}
 
-	err = s.skillStorage.UpdateDescription(*payload.Key, data.Description)
+	return s.apply(ctx, payload, SkillTagsChangedEvent, *payload.Key, func(storage SkillStorage) error {
+		return storage.UpdateTags(ctx, *payload.Key, data.Tags)
+	})
+}
+
+// AddTags and RemoveTags change only the tags they name, on top of whatever
+// tags the skill has by the time the message is applied.
+func (s skillService) AddTags(ctx context.Context, payload SkillQueuePayload) error {
+	data, err := message.DecodeRequest[AddSkillTagsRequest](payload.Payload)
 	if err != nil {
-		return err
+		return ErrorInvalidPayload
 	}
 
-	return nil
+	return s.apply(ctx, payload, SkillTagsChangedEvent, *payload.Key, func(storage SkillStorage) error {
+		return storage.AddTags(ctx, *payload.Key, data.Tags)
+	})
 }
 
-func (s skillService) UpdateLogo(payload SkillQueuePayload) error {
-	data, err := ConvertSkillType[UpdateSkillLogoRequest](payload.Payload)
+func (s skillService) RemoveTags(ctx context.Context, payload SkillQueuePayload) error {
+	data, err := message.DecodeRequest[RemoveSkillTagsRequest](payload.Payload)
 	if err != nil {
 		return ErrorInvalidPayload
 	}
 
-	err = s.skillStorage.UpdateLogo(*payload.Key, data.Logo)
+	return s.apply(ctx, payload, SkillTagsChangedEvent, *payload.Key, func(storage SkillStorage) error {
+		return storage.RemoveTags(ctx, *payload.Key, data.Tags)
+	})
+}
+
+func (s skillService) DeleteSkill(ctx context.Context, payload SkillQueuePayload) error {
+	return s.apply(ctx, payload, SkillDeletedEvent, *payload.Key, func(storage SkillStorage) error {
+		return storage.DeleteSkill(ctx, *payload.Key)
+	})
+}
+
+func (s skillService) RestoreSkill(ctx context.Context, payload SkillQueuePayload) error {
+	return s.apply(ctx, payload, SkillRestoredEvent, *payload.Key, func(storage SkillStorage) error {
+		return storage.RestoreSkill(ctx, *payload.Key)
+	})
+}
+
+// apply runs write and records the skill before and after it in the history
+// within one transaction, so a change is never stored without its history.
+// A change made against an older version of the skill is rejected with
+// ErrVersionConflict instead of overwriting the newer one, a restore being
+// checked against the version the skill was deleted at. Messages for one
+// skill share a partition and are applied one at a time, so the version read
+// here cannot move before the write.
+// The tag counts follow the tags the live skill gained or lost, a deleted
+// skill losing all of them and a restored one gaining them back.
+// A message already applied, which Kafka delivers again when its offset was
+// not committed in time, is skipped so it is neither written nor reported
+// twice. Its message ID in the history tells it apart.
+// A write that touched no skill, such as an update of a missing one, is
+// rejected with ErrSkillNotFound and nothing is recorded.
+// An event carrying both states is written to the outbox in the same
+// transaction, so a change is never announced without being stored, nor
+// stored without being announced once the relay reaches Kafka.
+func (s skillService) apply(ctx context.Context, payload SkillQueuePayload, eventType SkillEventType, key string, write func(SkillStorage) error) error {
+	var before, after *Skill
+	var duplicate bool
+	err := s.skillStorage.InTx(ctx, func(storage SkillStorage) error {
+		var err error
+		if payload.MessageID != "" {
+			duplicate, err = storage.HasMessage(ctx, payload.MessageID)
+			if err != nil || duplicate {
+				return err
+			}
+		}
+
+		before, err = currentSkill(ctx, storage, key)
+		if err != nil {
+			return err
+		}
+
+		// A restore is made against the skill in the trash, which is not
+		// the live skill before holds.
+		current := before
+		if payload.Action == RestoreSkillAction {
+			current, err = deletedSkill(ctx, storage, key)
+			if err != nil {
+				return err
+			}
+		}
+
+		if current != nil && payload.ExpectedVersion != 0 && current.Version != payload.ExpectedVersion {
+			return fmt.Errorf("%w: skill %s is at version %d, expected %d", ErrVersionConflict, key, current.Version, payload.ExpectedVersion)
+		}
+
+		if err := write(storage); err != nil {
+			return err
+		}
+
+		after, err = currentSkill(ctx, storage, key)
+		if err != nil {
+			return err
+		}
+
+		if before == nil && after == nil {
+			return fmt.Errorf("%w: %s", ErrSkillNotFound, key)
+		}
+		if err := storage.RecordHistory(ctx, newHistoryEntry(payload, key, before, after)); err != nil {
+			return err
+		}
+
+		added, removed := tagChanges(before, after)
+		if len(added) > 0 || len(removed) > 0 {
+			if err := storage.CountTags(ctx, added, removed); err != nil {
+				return err
+			}
+		}
+
+		return storage.EnqueueEvent(ctx, SkillEvent{
+			Type:       eventType,
+			Key:        key,
+			Before:     before,
+			After:      after,
+			OccurredAt: time.Now().UTC(),
+		})
+	})
 	if err != nil {
 		return err
 	}
 
+	if duplicate {
+		log.Printf("Skipped message %s for skill: %s, request_id: %s, it was already applied", payload.MessageID, key, RequestIDFrom(ctx))
+	}
 	return nil
 }
 
-func (s skillService) UpdateTags(payload SkillQueuePayload) error {
-	data, err := ConvertSkillType[UpdateSkillTagsRequest](payload.Payload)
-	if err != nil {
-		return ErrorInvalidPayload
+// tagChanges returns the tags after has and before has not, and the other way
+// round, each once and sorted so concurrent writes lock counts in one order.
+// A nil skill has no tags.
+func tagChanges(before, after *Skill) (added []string, removed []string) {
+	had, has := tagSet(before), tagSet(after)
+	for tag := range has {
+		if !had[tag] {
+			added = append(added, tag)
+		}
+	}
+	for tag := range had {
+		if !has[tag] {
+			removed = append(removed, tag)
+		}
 	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
 
-	err = s.skillStorage.UpdateTags(*payload.Key, data.Tags)
-	if err != nil {
-		return err
+func tagSet(skill *Skill) map[string]bool {
+	set := map[string]bool{}
+	if skill != nil {
+		for _, tag := range skill.Tags {
+			set[tag] = true
+		}
 	}
+	return set
+}
 
-	return nil
+func currentSkill(ctx context.Context, storage SkillStorage, key string) (*Skill, error) {
+	skill, err := storage.GetSkill(ctx, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return skill, err
 }
 
-func (s skillService) DeleteSkill(payload SkillQueuePayload) error {
-	err := s.skillStorage.DeleteSkill(*payload.Key)
-	if err != nil {
-		return err
+func deletedSkill(ctx context.Context, storage SkillStorage, key string) (*Skill, error) {
+	skill, err := storage.GetDeletedSkill(ctx, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
 	}
-	return nil
+	return skill, err
 }
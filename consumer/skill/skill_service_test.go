@@ -1,8 +1,16 @@
 package skill
 
 import (
+	"bytes"
+	"context"
 	"database/sql"
+	"errors"
+	"log"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestSkillService_CreateSkill(t *testing.T) {
@@ -13,7 +21,7 @@ func TestSkillService_CreateSkill(t *testing.T) {
 		key := "figma"
 
 		// Act
-		err := service.CreateSkill(SkillQueuePayload{
+		err := service.CreateSkill(context.Background(), SkillQueuePayload{
 			Key: &key,
 			Payload: map[string]interface{}{
 				"key":         "figma",
@@ -38,7 +46,7 @@ func TestSkillService_CreateSkill(t *testing.T) {
 		key := "figma"
 
 		// Act
-		err := service.CreateSkill(SkillQueuePayload{
+		err := service.CreateSkill(context.Background(), SkillQueuePayload{
 			Key: &key,
 			Payload: map[string]interface{}{
 				"key":         "figma",
@@ -65,7 +73,7 @@ func TestSkillService_CreateSkill(t *testing.T) {
 		key := "figma"
 
 		// Act
-		err := service.CreateSkill(SkillQueuePayload{
+		err := service.CreateSkill(context.Background(), SkillQueuePayload{
 			Key: &key,
 			Payload: map[string]interface{}{
 				"key":         "figma",
@@ -87,12 +95,12 @@ func TestSkillService_CreateSkill(t *testing.T) {
 func TestSkillService_UpdateSkill(t *testing.T) {
 	t.Run("should be able to update skill", func(t *testing.T) {
 		// Arrange
-		s := mockSkillStorage{}
+		s := mockSkillStorage{skill: &Skill{Key: "figma"}}
 		service := NewSkillService(&s)
 		key := "figma"
 
 		// Act
-		err := service.UpdateSkill(SkillQueuePayload{
+		err := service.UpdateSkill(context.Background(), SkillQueuePayload{
 			Key: &key,
 			Payload: map[string]interface{}{
 				"name":        "Figma",
@@ -116,7 +124,7 @@ func TestSkillService_UpdateSkill(t *testing.T) {
 		key := "figma"
 
 		// Act
-		err := service.UpdateSkill(SkillQueuePayload{
+		err := service.UpdateSkill(context.Background(), SkillQueuePayload{
 			Key: &key,
 			Payload: map[string]interface{}{
 				"name":        "Figma",
@@ -142,7 +150,7 @@ func TestSkillService_UpdateSkill(t *testing.T) {
 		key := "figma"
 
 		// Act
-		err := service.UpdateSkill(SkillQueuePayload{
+		err := service.UpdateSkill(context.Background(), SkillQueuePayload{
 			Key: &key,
 			Payload: map[string]interface{}{
 				"name":        "Figma",
@@ -163,12 +171,12 @@ func TestSkillService_UpdateSkill(t *testing.T) {
 func TestSkillService_UpdateName(t *testing.T) {
 	t.Run("should be able to update name", func(t *testing.T) {
 		// Arrange
-		s := mockSkillStorage{}
+		s := mockSkillStorage{skill: &Skill{Key: "figma"}}
 		service := NewSkillService(&s)
 		key := "figma"
 
 		// Act
-		err := service.UpdateName(SkillQueuePayload{
+		err := service.UpdateName(context.Background(), SkillQueuePayload{
 			Key: &key,
 			Payload: map[string]interface{}{
 				"name": "Figma",
@@ -189,7 +197,7 @@ func TestSkillService_UpdateName(t *testing.T) {
 		key := "figma"
 
 		// Act
-		err := service.UpdateName(SkillQueuePayload{
+		err := service.UpdateName(context.Background(), SkillQueuePayload{
 			Key: &key,
 			Payload: map[string]interface{}{
 				"name": 1,
@@ -212,7 +220,7 @@ func TestSkillService_UpdateName(t *testing.T) {
 		key := "figma"
 
 		// Act
-		err := service.UpdateName(SkillQueuePayload{
+		err := service.UpdateName(context.Background(), SkillQueuePayload{
 			Key: &key,
 			Payload: map[string]interface{}{
 				"name": "Figma",
@@ -230,12 +238,12 @@ func TestSkillService_UpdateName(t *testing.T) {
 func TestSkillService_UpdateDescription(t *testing.T) {
 	t.Run("should be able to update description", func(t *testing.T) {
 		// Arrange
-		s := mockSkillStorage{}
+		s := mockSkillStorage{skill: &Skill{Key: "figma"}}
 		service := NewSkillService(&s)
 		key := "figma"
 
 		// Act
-		err := service.UpdateDescription(SkillQueuePayload{
+		err := service.UpdateDescription(context.Background(), SkillQueuePayload{
 			Key: &key,
 			Payload: map[string]interface{}{
 				"description": "Figma is a vector bla bla",
@@ -256,7 +264,7 @@ func TestSkillService_UpdateDescription(t *testing.T) {
 		key := "figma"
 
 		// Act
-		err := service.UpdateDescription(SkillQueuePayload{
+		err := service.UpdateDescription(context.Background(), SkillQueuePayload{
 			Key: &key,
 			Payload: map[string]interface{}{
 				"description": 1,
@@ -279,7 +287,7 @@ func TestSkillService_UpdateDescription(t *testing.T) {
 		key := "figma"
 
 		// Act
-		err := service.UpdateDescription(SkillQueuePayload{
+		err := service.UpdateDescription(context.Background(), SkillQueuePayload{
 			Key: &key,
 			Payload: map[string]interface{}{
 				"description": "Figma is a vector bla bla",
@@ -297,12 +305,12 @@ func TestSkillService_UpdateDescription(t *testing.T) {
 func TestSkillService_UpdateLogo(t *testing.T) {
 	t.Run("should be able to update logo", func(t *testing.T) {
 		// Arrange
-		s := mockSkillStorage{}
+		s := mockSkillStorage{skill: &Skill{Key: "figma"}}
 		service := NewSkillService(&s)
 		key := "figma"
 
 		// Act
-		err := service.UpdateLogo(SkillQueuePayload{
+		err := service.UpdateLogo(context.Background(), SkillQueuePayload{
 			Key: &key,
 			Payload: map[string]interface{}{
 				"logo": "logo",
@@ -323,7 +331,7 @@ func TestSkillService_UpdateLogo(t *testing.T) {
 		key := "figma"
 
 		// Act
-		err := service.UpdateLogo(SkillQueuePayload{
+		err := service.UpdateLogo(context.Background(), SkillQueuePayload{
 			Key: &key,
 			Payload: map[string]interface{}{
 				"logo": 1,
@@ -346,7 +354,7 @@ func TestSkillService_UpdateLogo(t *testing.T) {
 		key := "figma"
 
 		// Act
-		err := service.UpdateLogo(SkillQueuePayload{
+		err := service.UpdateLogo(context.Background(), SkillQueuePayload{
 			Key: &key,
 			Payload: map[string]interface{}{
 				"logo": "logo",
@@ -364,12 +372,12 @@ func TestSkillService_UpdateLogo(t *testing.T) {
 func TestSkillService_UpdateTags(t *testing.T) {
 	t.Run("should be able to update tags", func(t *testing.T) {
 		// Arrange
-		s := mockSkillStorage{}
+		s := mockSkillStorage{skill: &Skill{Key: "figma"}}
 		service := NewSkillService(&s)
 		key := "figma"
 
 		// Act
-		err := service.UpdateTags(SkillQueuePayload{
+		err := service.UpdateTags(context.Background(), SkillQueuePayload{
 			Key: &key,
 			Payload: map[string]interface{}{
 				"tags": []string{"tag"},
@@ -390,7 +398,7 @@ func TestSkillService_UpdateTags(t *testing.T) {
 		key := "figma"
 
 		// Act
-		err := service.UpdateTags(SkillQueuePayload{
+		err := service.UpdateTags(context.Background(), SkillQueuePayload{
 			Key: &key,
 			Payload: map[string]interface{}{
 				"tags": "tag",
@@ -413,7 +421,7 @@ func TestSkillService_UpdateTags(t *testing.T) {
 		key := "figma"
 
 		// Act
-		err := service.UpdateTags(SkillQueuePayload{
+		err := service.UpdateTags(context.Background(), SkillQueuePayload{
 			Key: &key,
 			Payload: map[string]interface{}{
 				"tags": []string{"tag"},
@@ -428,15 +436,126 @@ func TestSkillService_UpdateTags(t *testing.T) {
 	})
 }
 
+func TestSkillService_AddTags(t *testing.T) {
+	t.Run("should add only the tags in payload", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{skill: &Skill{Key: "figma", Tags: []string{"design"}}}
+		service := NewSkillService(&s)
+		key := "figma"
+
+		// Act
+		err := service.AddTags(context.Background(), SkillQueuePayload{
+			Key:     &key,
+			Payload: AddSkillTagsRequest{Tags: []string{"ui", "prototyping"}},
+			Action:  AddTagsAction,
+		})
+
+		// Assert
+		if err != nil {
+			t.Errorf("expected error to be nil, got %s", err)
+		}
+
+		if !reflect.DeepEqual(s.addedTags, []string{"ui", "prototyping"}) {
+			t.Errorf("expected ui and prototyping to be added, got %v", s.addedTags)
+		}
+	})
+
+	t.Run("should return error when json unmarshall error", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{}
+		service := NewSkillService(&s)
+		key := "figma"
+
+		// Act
+		err := service.AddTags(context.Background(), SkillQueuePayload{
+			Key: &key,
+			Payload: map[string]interface{}{
+				"tags": "ui",
+			},
+			Action: AddTagsAction,
+		})
+
+		// Assert
+		if !errors.Is(err, ErrorInvalidPayload) {
+			t.Errorf("expected error to be invalid payload, got %v", err)
+		}
+	})
+
+	t.Run("should return error when database error", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{
+			err: sql.ErrConnDone,
+		}
+		service := NewSkillService(&s)
+		key := "figma"
+
+		// Act
+		err := service.AddTags(context.Background(), SkillQueuePayload{
+			Key:     &key,
+			Payload: AddSkillTagsRequest{Tags: []string{"ui"}},
+			Action:  AddTagsAction,
+		})
+
+		// Assert
+		if err == nil {
+			t.Error("expected error to be not nil")
+		}
+	})
+}
+
+func TestSkillService_RemoveTags(t *testing.T) {
+	t.Run("should remove only the tags in payload", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{skill: &Skill{Key: "figma", Tags: []string{"design", "ui"}}}
+		service := NewSkillService(&s)
+		key := "figma"
+
+		// Act
+		err := service.RemoveTags(context.Background(), SkillQueuePayload{
+			Key:     &key,
+			Payload: RemoveSkillTagsRequest{Tags: []string{"ui"}},
+			Action:  RemoveTagsAction,
+		})
+
+		// Assert
+		if err != nil {
+			t.Errorf("expected error to be nil, got %s", err)
+		}
+
+		if !reflect.DeepEqual(s.removedTags, []string{"ui"}) {
+			t.Errorf("expected ui to be removed, got %v", s.removedTags)
+		}
+	})
+
+	t.Run("should return error when json unmarshall error", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{}
+		service := NewSkillService(&s)
+		key := "figma"
+
+		// Act
+		err := service.RemoveTags(context.Background(), SkillQueuePayload{
+			Key:     &key,
+			Payload: map[string]interface{}{},
+			Action:  RemoveTagsAction,
+		})
+
+		// Assert
+		if !errors.Is(err, ErrorInvalidPayload) {
+			t.Errorf("expected error to be invalid payload, got %v", err)
+		}
+	})
+}
+
 func TestSkillService_DeleteSkill(t *testing.T) {
 	t.Run("should be able to delete skill", func(t *testing.T) {
 		// Arrange
-		s := mockSkillStorage{}
+		s := mockSkillStorage{skill: &Skill{Key: "figma"}}
 		service := NewSkillService(&s)
 		key := "figma"
 
 		// Act
-		err := service.DeleteSkill(SkillQueuePayload{
+		err := service.DeleteSkill(context.Background(), SkillQueuePayload{
 			Key:    &key,
 			Action: DeleteSkillAction,
 		})
@@ -456,7 +575,7 @@ func TestSkillService_DeleteSkill(t *testing.T) {
 		key := "figma"
 
 		// Act
-		err := service.DeleteSkill(SkillQueuePayload{
+		err := service.DeleteSkill(context.Background(), SkillQueuePayload{
 			Key:    &key,
 			Action: DeleteSkillAction,
 		})
@@ -467,3 +586,499 @@ func TestSkillService_DeleteSkill(t *testing.T) {
 		}
 	})
 }
+
+func TestSkillService_PublishEvent(t *testing.T) {
+	t.Run("should publish created event without before state", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{}
+		service := NewSkillService(&s)
+		key := "figma"
+
+		// Act
+		err := service.CreateSkill(context.Background(), SkillQueuePayload{
+			Key: &key,
+			Payload: map[string]interface{}{
+				"key":         "figma",
+				"name":        "Figma",
+				"description": "Figma is a vector bla bla",
+				"logo":        "logo",
+				"tags":        []string{"tag"},
+			},
+			Action: CreateSkillAction,
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected error to be nil, got %s", err)
+		}
+
+		if len(s.events) != 1 {
+			t.Fatalf("expected 1 event but got %d", len(s.events))
+		}
+
+		event := s.events[0]
+		if event.Type != SkillCreatedEvent || event.Key != "figma" {
+			t.Errorf("expected %s for figma but got %s for %s", SkillCreatedEvent, event.Type, event.Key)
+		}
+
+		if event.Before != nil || event.After == nil || event.After.Name != "Figma" {
+			t.Errorf("expected only after state with name Figma but got before %v, after %v", event.Before, event.After)
+		}
+	})
+
+	t.Run("should publish name changed event with before and after state", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{skill: &Skill{Key: "figma", Name: "Figma"}}
+		service := NewSkillService(&s)
+		key := "figma"
+
+		// Act
+		err := service.UpdateName(context.Background(), SkillQueuePayload{
+			Key:     &key,
+			Payload: map[string]interface{}{"name": "Figma Design"},
+			Action:  UpdateNameAction,
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected error to be nil, got %s", err)
+		}
+
+		if len(s.events) != 1 {
+			t.Fatalf("expected 1 event but got %d", len(s.events))
+		}
+
+		event := s.events[0]
+		if event.Type != SkillNameChangedEvent {
+			t.Errorf("expected %s but got %s", SkillNameChangedEvent, event.Type)
+		}
+
+		if event.Before.Name != "Figma" || event.After.Name != "Figma Design" {
+			t.Errorf("expected name to change from Figma to Figma Design but got %q to %q", event.Before.Name, event.After.Name)
+		}
+	})
+
+	t.Run("should publish deleted event without after state", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{skill: &Skill{Key: "figma", Name: "Figma"}}
+		service := NewSkillService(&s)
+		key := "figma"
+
+		// Act
+		err := service.DeleteSkill(context.Background(), SkillQueuePayload{
+			Key:    &key,
+			Action: DeleteSkillAction,
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected error to be nil, got %s", err)
+		}
+
+		if len(s.events) != 1 || s.events[0].Type != SkillDeletedEvent {
+			t.Fatalf("expected 1 %s event but got %v", SkillDeletedEvent, s.events)
+		}
+
+		if s.events[0].Before == nil || s.events[0].After != nil {
+			t.Errorf("expected only before state but got before %v, after %v", s.events[0].Before, s.events[0].After)
+		}
+	})
+
+	t.Run("should publish restored event without before state", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{deleted: &Skill{Key: "figma", Name: "Figma"}}
+		service := NewSkillService(&s)
+		key := "figma"
+
+		// Act
+		err := service.RestoreSkill(context.Background(), SkillQueuePayload{
+			Key:    &key,
+			Action: RestoreSkillAction,
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected error to be nil, got %s", err)
+		}
+
+		if len(s.events) != 1 || s.events[0].Type != SkillRestoredEvent {
+			t.Fatalf("expected 1 %s event but got %v", SkillRestoredEvent, s.events)
+		}
+
+		if s.events[0].Before != nil || s.events[0].After == nil {
+			t.Errorf("expected only after state but got before %v, after %v", s.events[0].Before, s.events[0].After)
+		}
+	})
+
+	t.Run("should reject change when skill does not exist", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{}
+		service := NewSkillService(&s)
+		key := "figma"
+
+		// Act
+		err := service.DeleteSkill(context.Background(), SkillQueuePayload{
+			Key:    &key,
+			Action: DeleteSkillAction,
+		})
+
+		// Assert
+		if !errors.Is(err, ErrSkillNotFound) {
+			t.Fatalf("expected error %s, got %v", ErrSkillNotFound, err)
+		}
+
+		if len(s.events) != 0 {
+			t.Errorf("expected no event but got %v", s.events)
+		}
+	})
+
+	t.Run("should fail change when event cannot be written", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{skill: &Skill{Key: "figma", Name: "Figma"}, eventErr: sql.ErrConnDone}
+		service := NewSkillService(&s)
+		key := "figma"
+
+		// Act
+		err := service.UpdateName(context.Background(), SkillQueuePayload{
+			Key:     &key,
+			Payload: map[string]interface{}{"name": "Figma Design"},
+			Action:  UpdateNameAction,
+		})
+
+		// Assert
+		if !errors.Is(err, sql.ErrConnDone) {
+			t.Errorf("expected error %s, got %v", sql.ErrConnDone, err)
+		}
+	})
+}
+
+func TestSkillService_RecordHistory(t *testing.T) {
+	t.Run("should record change with actor of message", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{skill: &Skill{Key: "go", Description: "Golang"}}
+		service := NewSkillService(&s)
+		key := "go"
+		sent := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		// Act
+		err := service.UpdateDescription(context.Background(), SkillQueuePayload{
+			MessageID: "msg-1",
+			Timestamp: sent,
+			Actor:     "alice",
+			Key:       &key,
+			Payload:   map[string]interface{}{"description": "The Go language"},
+			Action:    UpdateDescAction,
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected error to be nil, got %s", err)
+		}
+
+		if len(s.history) != 1 {
+			t.Fatalf("expected 1 history entry but got %d", len(s.history))
+		}
+
+		entry := s.history[0]
+		if entry.Action != UpdateDescAction || entry.Actor != "alice" || entry.MessageID != "msg-1" || !entry.OccurredAt.Equal(sent) {
+			t.Errorf("expected %s by alice from msg-1 at %s but got %+v", UpdateDescAction, sent, entry)
+		}
+
+		if entry.Before == nil || entry.Before.Description != "Golang" {
+			t.Errorf("expected before description Golang but got %v", entry.Before)
+		}
+	})
+
+	t.Run("should not record history when skill does not exist", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{}
+		service := NewSkillService(&s)
+		key := "go"
+
+		// Act
+		err := service.DeleteSkill(context.Background(), SkillQueuePayload{
+			Key:    &key,
+			Action: DeleteSkillAction,
+		})
+
+		// Assert
+		if !errors.Is(err, ErrSkillNotFound) {
+			t.Fatalf("expected error %s, got %v", ErrSkillNotFound, err)
+		}
+
+		if len(s.history) != 0 {
+			t.Errorf("expected no history but got %v", s.history)
+		}
+	})
+}
+
+func TestSkillService_CountTags(t *testing.T) {
+	t.Run("should count tags of created skill", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{}
+		service := NewSkillService(&s)
+		key := "go"
+
+		// Act
+		err := service.CreateSkill(context.Background(), SkillQueuePayload{
+			Key: &key,
+			Payload: map[string]interface{}{
+				"key":         "go",
+				"name":        "Go",
+				"description": "Golang",
+				"logo":        "go.svg",
+				"tags":        []string{"go", "backend", "go"},
+			},
+			Action: CreateSkillAction,
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected error to be nil, got %s", err)
+		}
+
+		if !reflect.DeepEqual(s.countedTags, []string{"backend", "go"}) || len(s.uncountedTags) != 0 {
+			t.Errorf("expected backend and go to be counted once, got %v and %v", s.countedTags, s.uncountedTags)
+		}
+	})
+
+	t.Run("should uncount tags of deleted skill", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{skill: &Skill{Key: "go", Tags: []string{"go", "backend"}}}
+		service := NewSkillService(&s)
+		key := "go"
+
+		// Act
+		err := service.DeleteSkill(context.Background(), SkillQueuePayload{
+			Key:    &key,
+			Action: DeleteSkillAction,
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected error to be nil, got %s", err)
+		}
+
+		if len(s.countedTags) != 0 || !reflect.DeepEqual(s.uncountedTags, []string{"backend", "go"}) {
+			t.Errorf("expected backend and go to be uncounted, got %v and %v", s.countedTags, s.uncountedTags)
+		}
+	})
+
+	t.Run("should leave counts alone when tags do not change", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{skill: &Skill{Key: "go", Name: "Go", Tags: []string{"go"}}}
+		service := NewSkillService(&s)
+		key := "go"
+
+		// Act
+		err := service.UpdateName(context.Background(), SkillQueuePayload{
+			Key:     &key,
+			Payload: map[string]interface{}{"name": "Golang"},
+			Action:  UpdateNameAction,
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected error to be nil, got %s", err)
+		}
+
+		if s.countedTags != nil || s.uncountedTags != nil {
+			t.Errorf("expected no tag counts, got %v and %v", s.countedTags, s.uncountedTags)
+		}
+	})
+}
+
+func TestSkillService_Redelivery(t *testing.T) {
+	t.Run("should apply message delivered twice once", func(t *testing.T) {
+		// Arrange
+		db := newMockDB()
+		defer db.Close()
+		service := NewSkillService(NewSkillStorage(db))
+		key := "redelivered"
+		payload := SkillQueuePayload{
+			MessageID: "msg-redelivered",
+			Key:       &key,
+			Payload: map[string]interface{}{
+				"key":         "redelivered",
+				"name":        "Redelivered",
+				"description": "Applied once",
+				"logo":        "redelivered.svg",
+				"tags":        []string{"kafka"},
+			},
+			Action: CreateSkillAction,
+		}
+
+		// Act
+		first := service.CreateSkill(context.Background(), payload)
+		second := service.CreateSkill(context.Background(), payload)
+
+		// Assert
+		if first != nil || second != nil {
+			t.Fatalf("expected both deliveries to succeed, got %v and %v", first, second)
+		}
+
+		var history int
+		db.QueryRow("SELECT count(*) FROM skill_history WHERE skill_key = $1", key).Scan(&history)
+		if history != 1 {
+			t.Errorf("expected 1 history entry, got %d", history)
+		}
+
+		if version := getData(db, key).Version; version != 1 {
+			t.Errorf("expected version 1, got %d", version)
+		}
+
+		var events int
+		db.QueryRow("SELECT count(*) FROM skill_event_outbox WHERE key = $1", key).Scan(&events)
+		if events != 1 {
+			t.Errorf("expected 1 event, got %d", events)
+		}
+	})
+
+	t.Run("should log skipped message with its request id", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{skill: &Skill{Key: "go"}, history: []HistoryEntry{{MessageID: "msg-1"}}}
+		service := NewSkillService(&s)
+		key := "go"
+		var out bytes.Buffer
+		log.SetOutput(&out)
+		t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+		// Act
+		err := service.DeleteSkill(WithRequestID(context.Background(), "req-1"), SkillQueuePayload{
+			MessageID: "msg-1",
+			Key:       &key,
+			Action:    DeleteSkillAction,
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected error to be nil, got %s", err)
+		}
+
+		if !strings.Contains(out.String(), "request_id: req-1") {
+			t.Errorf("expected log line with request id req-1, got %q", out.String())
+		}
+	})
+}
+
+func TestSkillService_ExpectedVersion(t *testing.T) {
+	t.Run("should apply change made against current version", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{skill: &Skill{Key: "go", Name: "Go", Version: 2}}
+		service := NewSkillService(&s)
+		key := "go"
+
+		// Act
+		err := service.UpdateName(context.Background(), SkillQueuePayload{
+			Key:             &key,
+			ExpectedVersion: 2,
+			Payload:         map[string]interface{}{"name": "Golang"},
+			Action:          UpdateNameAction,
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected error to be nil, got %s", err)
+		}
+
+		if s.skill.Name != "Golang" {
+			t.Errorf("expected name Golang but got %s", s.skill.Name)
+		}
+	})
+
+	t.Run("should reject change made against stale version", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{skill: &Skill{Key: "go", Name: "Go", Version: 3}}
+		service := NewSkillService(&s)
+		key := "go"
+
+		// Act
+		err := service.UpdateName(context.Background(), SkillQueuePayload{
+			Key:             &key,
+			ExpectedVersion: 2,
+			Payload:         map[string]interface{}{"name": "Golang"},
+			Action:          UpdateNameAction,
+		})
+
+		// Assert
+		if !errors.Is(err, ErrVersionConflict) {
+			t.Fatalf("expected error %s, got %v", ErrVersionConflict, err)
+		}
+
+		if s.skill.Name != "Go" {
+			t.Errorf("expected name to stay Go but got %s", s.skill.Name)
+		}
+
+		if len(s.history) != 0 || len(s.events) != 0 {
+			t.Errorf("expected no history or event but got %v and %v", s.history, s.events)
+		}
+	})
+
+	t.Run("should apply change without expected version", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{skill: &Skill{Key: "go", Name: "Go", Version: 3}}
+		service := NewSkillService(&s)
+		key := "go"
+
+		// Act
+		err := service.DeleteSkill(context.Background(), SkillQueuePayload{
+			Key:    &key,
+			Action: DeleteSkillAction,
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected error to be nil, got %s", err)
+		}
+
+		if s.skill != nil {
+			t.Errorf("expected skill to be deleted but got %v", s.skill)
+		}
+	})
+
+	t.Run("should restore skill deleted at expected version", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{deleted: &Skill{Key: "go", Name: "Go", Version: 4}}
+		service := NewSkillService(&s)
+		key := "go"
+
+		// Act
+		err := service.RestoreSkill(context.Background(), SkillQueuePayload{
+			Key:             &key,
+			ExpectedVersion: 4,
+			Action:          RestoreSkillAction,
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected error to be nil, got %s", err)
+		}
+
+		if s.skill == nil || s.deleted != nil {
+			t.Errorf("expected skill to be restored")
+		}
+	})
+
+	t.Run("should reject restore made against stale version", func(t *testing.T) {
+		// Arrange
+		s := mockSkillStorage{deleted: &Skill{Key: "go", Name: "Go", Version: 5}}
+		service := NewSkillService(&s)
+		key := "go"
+
+		// Act
+		err := service.RestoreSkill(context.Background(), SkillQueuePayload{
+			Key:             &key,
+			ExpectedVersion: 4,
+			Action:          RestoreSkillAction,
+		})
+
+		// Assert
+		if !errors.Is(err, ErrVersionConflict) {
+			t.Fatalf("expected error %s, got %v", ErrVersionConflict, err)
+		}
+
+		if s.skill != nil || s.deleted == nil {
+			t.Errorf("expected skill to stay in the trash")
+		}
+	})
+}
@@ -1,61 +1,338 @@
 package skill
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"github.com/lib/pq"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"skill-api-kafka-consumer/outbox"
+	"skill-api-kafka-consumer/tracing"
+	"time"
 )
 
 type Skill struct {
-	Key         string
-	Name        string
-	Description string
-	Logo        string
-	Tags        []string
+	Key         string   `json:"key"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Logo        string   `json:"logo"`
+	Tags        []string `json:"tags"`
+	Version     int64    `json:"version"`
+}
+
+// querier is what the storage needs from either the database or a
+// transaction, so the same queries run inside and outside InTx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 }
 
 type skillStorage struct {
 	db *sql.DB
+	q  querier
 }
 
 func NewSkillStorage(db *sql.DB) skillStorage {
 	return skillStorage{
 		db: db,
+		q:  db,
+	}
+}
+
+// InTx runs fn with a storage bound to a single transaction, committed when fn
+// returns nil and rolled back otherwise. Storage already bound to a
+// transaction runs fn within it.
+func (s skillStorage) InTx(ctx context.Context, fn func(SkillStorage) error) error {
+	if s.db == nil {
+		return fn(s)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(skillStorage{q: tx}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetSkill returns the skill with key unless it is deleted.
+func (s skillStorage) GetSkill(ctx context.Context, key string) (*Skill, error) {
+	return s.getSkill(ctx, "SkillStorage.GetSkill", key, false)
+}
+
+// GetDeletedSkill returns the skill with key only while it is in the trash.
+func (s skillStorage) GetDeletedSkill(ctx context.Context, key string) (*Skill, error) {
+	return s.getSkill(ctx, "SkillStorage.GetDeletedSkill", key, true)
+}
+
+func (s skillStorage) getSkill(ctx context.Context, name string, key string, deleted bool) (*Skill, error) {
+	ctx, span := startQuerySpan(ctx, name, key)
+	defer span.End()
+
+	var skill Skill
+	qry := `SELECT key,name,description,logo,tags,version FROM skill WHERE key = $1 AND (deleted_at IS NOT NULL) = $2`
+	err := s.q.QueryRowContext(ctx, qry, key, deleted).Scan(&skill.Key, &skill.Name, &skill.Description, &skill.Logo, pq.Array(&skill.Tags), &skill.Version)
+	if err != nil {
+		// A missing skill is an answer, not a failure of the query.
+		if !errors.Is(err, sql.ErrNoRows) {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return nil, err
 	}
+
+	return &skill, nil
 }
 
-func (s skillStorage) CreateSkill(req CreateSkillRequest) error {
+func (s skillStorage) CreateSkill(ctx context.Context, req CreateSkillRequest) error {
 	qry := `INSERT INTO skill (key,name,description,logo,tags) VALUES($1,$2,$3,$4,$5);`
-	_, err := s.db.Exec(qry, req.Key, req.Name, req.Description, req.Logo, pq.Array(req.Tags))
-	return err
-}
-func (s skillStorage) UpdateSkill(id string, skill UpdateSkillRequest) error {
-	qry := `UPDATE skill SET name = $1, description = $2, logo = $3, tags = $4 WHERE key = $5`
-	_, err := s.db.Exec(qry, skill.Name, skill.Description, skill.Logo, pq.Array(skill.Tags), id)
-	return err
-}
-func (s skillStorage) UpdateName(key string, name string) error {
-	qry := `UPDATE skill SET name = $1 WHERE key = $2`
-	_, err := s.db.Exec(qry, name, key)
-	return err
-}
-func (s skillStorage) UpdateDescription(key string, desc string) error {
-	qry := `UPDATE skill SET description = $1 WHERE key = $2`
-	_, err := s.db.Exec(qry, desc, key)
-	return err
-}
-func (s skillStorage) UpdateLogo(key string, logo string) error {
-	qry := `UPDATE skill SET logo = $1 WHERE key = $2`
-	_, err := s.db.Exec(qry, logo, key)
-	return err
-}
-func (s skillStorage) UpdateTags(key string, tag []string) error {
-	qry := `UPDATE skill SET tags = $1 WHERE key = $2`
-	_, err := s.db.Exec(qry, pq.Array(tag), key)
-	return err
-}
-
-func (s skillStorage) DeleteSkill(key string) error {
-	qry := `DELETE FROM skill WHERE key = $1`
-	_, err := s.db.Exec(qry, key)
-	return err
+	return s.execSearchable(ctx, "SkillStorage.CreateSkill", req.Key, qry, req.Key, req.Name, req.Description, req.Logo, pq.Array(req.Tags))
+}
+
+func (s skillStorage) UpdateSkill(ctx context.Context, id string, skill UpdateSkillRequest) error {
+	qry := `UPDATE skill SET name = $1, description = $2, logo = $3, tags = $4, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE key = $5 AND deleted_at IS NULL`
+	return s.execSearchable(ctx, "SkillStorage.UpdateSkill", id, qry, skill.Name, skill.Description, skill.Logo, pq.Array(skill.Tags), id)
+}
+
+func (s skillStorage) UpdateName(ctx context.Context, key string, name string) error {
+	qry := `UPDATE skill SET name = $1, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE key = $2 AND deleted_at IS NULL`
+	return s.execSearchable(ctx, "SkillStorage.UpdateName", key, qry, name, key)
+}
+
+func (s skillStorage) UpdateDescription(ctx context.Context, key string, desc string) error {
+	qry := `UPDATE skill SET description = $1, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE key = $2 AND deleted_at IS NULL`
+	return s.execSearchable(ctx, "SkillStorage.UpdateDescription", key, qry, desc, key)
+}
+
+func (s skillStorage) UpdateLogo(ctx context.Context, key string, logo string) error {
+	qry := `UPDATE skill SET logo = $1, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE key = $2 AND deleted_at IS NULL`
+	return s.exec(ctx, "SkillStorage.UpdateLogo", key, qry, logo, key)
+}
+
+func (s skillStorage) UpdateTags(ctx context.Context, key string, tag []string) error {
+	qry := `UPDATE skill SET tags = $1, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE key = $2 AND deleted_at IS NULL`
+	return s.execSearchable(ctx, "SkillStorage.UpdateTags", key, qry, pq.Array(tag), key)
+}
+
+// AddTags appends the given tags the skill does not have yet, in the order
+// given and each once, keeping its other tags as they are.
+func (s skillStorage) AddTags(ctx context.Context, key string, tags []string) error {
+	return s.changeTags(ctx, "SkillStorage.AddTags", key, func(current []string) []string {
+		return addTags(current, tags)
+	})
+}
+
+// RemoveTags drops every occurrence of the given tags from the skill, keeping
+// the order of the rest. Tags it does not have are ignored.
+func (s skillStorage) RemoveTags(ctx context.Context, key string, tags []string) error {
+	return s.changeTags(ctx, "SkillStorage.RemoveTags", key, func(current []string) []string {
+		return removeTags(current, tags)
+	})
+}
+
+// changeTags replaces the tags of the live skill with key by what change
+// makes of them, leaving a missing skill alone. Messages for one skill are
+// applied one at a time within InTx, so the tags read here cannot move
+// before the write.
+func (s skillStorage) changeTags(ctx context.Context, name string, key string, change func([]string) []string) error {
+	var current []string
+	err := s.q.QueryRowContext(ctx, `SELECT tags FROM skill WHERE key = $1 AND deleted_at IS NULL`, key).Scan(pq.Array(&current))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	qry := `UPDATE skill SET tags = $1, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE key = $2 AND deleted_at IS NULL`
+	return s.execSearchable(ctx, name, key, qry, pq.Array(change(current)), key)
+}
+
+func addTags(current []string, tags []string) []string {
+	seen := make(map[string]bool, len(current)+len(tags))
+	res := make([]string, 0, len(current)+len(tags))
+	for _, tag := range current {
+		seen[tag] = true
+		res = append(res, tag)
+	}
+	for _, tag := range tags {
+		if !seen[tag] {
+			seen[tag] = true
+			res = append(res, tag)
+		}
+	}
+	return res
+}
+
+func removeTags(current []string, tags []string) []string {
+	drop := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		drop[tag] = true
+	}
+
+	res := make([]string, 0, len(current))
+	for _, tag := range current {
+		if !drop[tag] {
+			res = append(res, tag)
+		}
+	}
+	return res
+}
+
+// DeleteSkill moves the skill to the trash. It is hidden from then on but
+// can be restored until it is purged.
+func (s skillStorage) DeleteSkill(ctx context.Context, key string) error {
+	qry := `UPDATE skill SET deleted_at = CURRENT_TIMESTAMP, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE key = $1 AND deleted_at IS NULL`
+	return s.exec(ctx, "SkillStorage.DeleteSkill", key, qry, key)
+}
+
+func (s skillStorage) RestoreSkill(ctx context.Context, key string) error {
+	qry := `UPDATE skill SET deleted_at = NULL, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE key = $1 AND deleted_at IS NOT NULL`
+	return s.exec(ctx, "SkillStorage.RestoreSkill", key, qry, key)
+}
+
+// PurgeDeleted permanently removes skills deleted before the given time and
+// returns how many were removed. Their history is kept.
+func (s skillStorage) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	ctx, span := tracing.Tracer.Start(ctx, "SkillStorage.PurgeDeleted",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL),
+	)
+	defer span.End()
+
+	res, err := s.q.ExecContext(ctx, `DELETE FROM skill WHERE deleted_at < $1`, before)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// CountTags moves the number of live skills carrying each tag up by one for
+// added and down by one for removed. The counts are changed in place rather
+// than recounted, so concurrent writes to different skills cannot overwrite
+// each other's counts.
+func (s skillStorage) CountTags(ctx context.Context, added []string, removed []string) error {
+	ctx, span := tracing.Tracer.Start(ctx, "SkillStorage.CountTags",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL),
+	)
+	defer span.End()
+
+	for _, tag := range added {
+		qry := `INSERT INTO skill_tag (tag, count) VALUES ($1, 1) ON CONFLICT (tag) DO UPDATE SET count = skill_tag.count + 1`
+		if _, err := s.q.ExecContext(ctx, qry, tag); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+	}
+	for _, tag := range removed {
+		if _, err := s.q.ExecContext(ctx, `UPDATE skill_tag SET count = count - 1 WHERE tag = $1`, tag); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+	}
+	return nil
+}
+
+// HasMessage reports whether the message with id has already been applied,
+// which its history entry records.
+func (s skillStorage) HasMessage(ctx context.Context, id string) (bool, error) {
+	ctx, span := tracing.Tracer.Start(ctx, "SkillStorage.HasMessage",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL),
+	)
+	defer span.End()
+
+	var applied bool
+	err := s.q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM skill_history WHERE message_id = $1)`, id).Scan(&applied)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return false, err
+	}
+	return applied, nil
+}
+
+// EnqueueEvent writes event to the outbox the relay publishes from, along
+// with the trace context of ctx so the event continues the change's trace.
+func (s skillStorage) EnqueueEvent(ctx context.Context, event SkillEvent) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{EventTypeHeader: string(event.Type)}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(headers))
+	return outbox.NewTxOutboxStorage(s.q).Enqueue(ctx, outbox.Message{Key: event.Key, Value: value, Headers: headers})
+}
+
+func (s skillStorage) RecordHistory(ctx context.Context, entry HistoryEntry) error {
+	before, err := historyState(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := historyState(entry.After)
+	if err != nil {
+		return err
+	}
+
+	qry := `INSERT INTO skill_history (skill_key,action,before,after,actor,message_id,occurred_at) VALUES($1,$2,$3,$4,$5,$6,$7)`
+	return s.exec(ctx, "SkillStorage.RecordHistory", entry.Key, qry, entry.Key, string(entry.Action), before, after, entry.Actor, entry.MessageID, entry.OccurredAt)
+}
+
+// historyState encodes a skill state as JSON, a missing one is stored as NULL.
+func historyState(skill *Skill) (any, error) {
+	if skill == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(skill)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// exec runs a write for the skill with key inside its own span.
+func (s skillStorage) exec(ctx context.Context, name string, key string, qry string, args ...any) error {
+	ctx, span := startQuerySpan(ctx, name, key)
+	defer span.End()
+
+	if _, err := s.q.ExecContext(ctx, qry, args...); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
+
+// searchVector is what a skill is found by in full text search, with its
+// name weighing more than its description and its description more than its
+// tags.
+const searchVector = `setweight(to_tsvector('english', name), 'A') || ` +
+	`setweight(to_tsvector('english', description), 'B') || ` +
+	`setweight(to_tsvector('english', array_to_string(tags, ' ')), 'C')`
+
+// execSearchable runs a write changing text the skill with key is searched
+// by, then brings its search column up to date. Run it within InTx so the
+// two cannot drift apart.
+func (s skillStorage) execSearchable(ctx context.Context, name string, key string, qry string, args ...any) error {
+	if err := s.exec(ctx, name, key, qry, args...); err != nil {
+		return err
+	}
+	return s.exec(ctx, "SkillStorage.UpdateSearch", key, `UPDATE skill SET search = `+searchVector+` WHERE key = $1`, key)
+}
+
+func startQuerySpan(ctx context.Context, name string, key string) (context.Context, trace.Span) {
+	return tracing.Tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, attribute.String("skill.key", key)),
+	)
 }
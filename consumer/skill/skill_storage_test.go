@@ -1,13 +1,37 @@
 package skill
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/lib/pq"
-	_ "modernc.org/sqlite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"modernc.org/sqlite"
 )
 
+// init stands in for the Postgres text search functions searchVector uses,
+// writing each field followed by its weight so tests can read what went in.
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction("to_tsvector", 2, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		return args[1], nil
+	})
+	sqlite.MustRegisterDeterministicScalarFunction("array_to_string", 2, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		return args[0], nil
+	})
+	sqlite.MustRegisterDeterministicScalarFunction("setweight", 2, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		return fmt.Sprintf("%s:%s ", args[0], args[1]), nil
+	})
+}
+
 func newMockDB() *sql.DB {
 	db, _ := sql.Open("sqlite", "file:skill?mode=memory&cache=shared")
 	q := `
@@ -16,7 +40,34 @@ CREATE TABLE IF NOT EXISTS skill (
     name TEXT NOT NULL DEFAULT '',
     description TEXT NOT NULL DEFAULT '',
     logo TEXT NOT NULL DEFAULT '',
-    tags TEXT [] NOT NULL DEFAULT '{}'
+    tags TEXT [] NOT NULL DEFAULT '{}',
+    version INTEGER NOT NULL DEFAULT 1,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    search TEXT NOT NULL DEFAULT ''
+);
+CREATE TABLE IF NOT EXISTS skill_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    skill_key TEXT NOT NULL,
+    action TEXT NOT NULL,
+    before TEXT,
+    after TEXT,
+    actor TEXT NOT NULL DEFAULT '',
+    message_id TEXT NOT NULL DEFAULT '',
+    occurred_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS skill_history_message_idx ON skill_history (message_id) WHERE message_id <> '';
+CREATE TABLE IF NOT EXISTS skill_event_outbox (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    key TEXT NOT NULL,
+    value BLOB NOT NULL,
+    headers TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP
+);
+CREATE TABLE IF NOT EXISTS skill_tag (
+    tag TEXT PRIMARY KEY,
+    count INTEGER NOT NULL DEFAULT 0
 );
 `
 	db.Exec(q)
@@ -30,12 +81,51 @@ func getCount(db *sql.DB) int {
 }
 
 func getData(db *sql.DB, key string) Skill {
-	rows := db.QueryRow("SELECT key, name, description, logo, tags FROM skill WHERE key = $1", key)
+	rows := db.QueryRow("SELECT key, name, description, logo, tags, version FROM skill WHERE key = $1", key)
 	var skill Skill
-	rows.Scan(&skill.Key, &skill.Name, &skill.Description, &skill.Logo, pq.Array(&skill.Tags))
+	rows.Scan(&skill.Key, &skill.Name, &skill.Description, &skill.Logo, pq.Array(&skill.Tags), &skill.Version)
 	return skill
 }
 
+func TestStorageGetSkill(t *testing.T) {
+	t.Run("should return skill by key", func(t *testing.T) {
+		// Arrange
+		db := newMockDB()
+		defer db.Close()
+		db.Exec("INSERT INTO skill (key, name, description, logo, tags) VALUES ('go', 'Go', 'Golang', 'https://golang.org/doc/gopher/frontpage.png', '{go, golang}')")
+
+		storage := NewSkillStorage(db)
+
+		// Act
+		skill, err := storage.GetSkill(context.Background(), "go")
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if skill.Name != "Go" || len(skill.Tags) != 2 {
+			t.Errorf("GetSkill() = %v, want Go with 2 tags", skill)
+		}
+	})
+
+	t.Run("should return sql.ErrNoRows when skill does not exist", func(t *testing.T) {
+		// Arrange
+		db := newMockDB()
+		defer db.Close()
+
+		storage := NewSkillStorage(db)
+
+		// Act
+		_, err := storage.GetSkill(context.Background(), "go")
+
+		// Assert
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetSkill() error = %v, want %v", err, sql.ErrNoRows)
+		}
+	})
+}
+
 func TestStorageCreate(t *testing.T) {
 	// Arrange
 	db := newMockDB()
@@ -51,7 +141,7 @@ func TestStorageCreate(t *testing.T) {
 	}
 
 	// Act
-	err := storage.CreateSkill(give)
+	err := storage.CreateSkill(context.Background(), give)
 
 	// Assert
 	if err != nil {
@@ -67,7 +157,7 @@ func TestStorageUpdate(t *testing.T) {
 	// Arrange
 	db := newMockDB()
 	defer db.Close()
-	db.Exec("INSERT INTO skill (key, name, description, logo, tags) VALUES ('go', 'Go', 'Golang', 'https://golang.org/doc/gopher/frontpage.png', '{go, golang}')")
+	db.Exec("INSERT INTO skill (key, name, description, logo, tags, updated_at) VALUES ('go', 'Go', 'Golang', 'https://golang.org/doc/gopher/frontpage.png', '{go, golang}', '2000-01-01 00:00:00')")
 
 	storage := NewSkillStorage(db)
 	give := UpdateSkillRequest{
@@ -78,7 +168,7 @@ func TestStorageUpdate(t *testing.T) {
 	}
 
 	// Act
-	err := storage.UpdateSkill("go", give)
+	err := storage.UpdateSkill(context.Background(), "go", give)
 
 	// Assert
 	if err != nil {
@@ -89,6 +179,16 @@ func TestStorageUpdate(t *testing.T) {
 	if data.Name != "Golang Intensive Course" {
 		t.Errorf("got.Name = %s, want Golang", data.Name)
 	}
+
+	if data.Version != 2 {
+		t.Errorf("got.Version = %d, want 2", data.Version)
+	}
+
+	var stale int
+	db.QueryRow("SELECT count(*) FROM skill WHERE key = 'go' AND updated_at = '2000-01-01 00:00:00'").Scan(&stale)
+	if stale != 0 {
+		t.Error("expected updated_at to be set to the time of the update")
+	}
 }
 
 func TestStorageUpdateName(t *testing.T) {
@@ -100,7 +200,7 @@ func TestStorageUpdateName(t *testing.T) {
 	storage := NewSkillStorage(db)
 
 	// Act
-	err := storage.UpdateName("go", "Golang Intensive Course")
+	err := storage.UpdateName(context.Background(), "go", "Golang Intensive Course")
 
 	// Assert
 	if err != nil {
@@ -122,7 +222,7 @@ func TestStorageUpdateDescription(t *testing.T) {
 	storage := NewSkillStorage(db)
 
 	// Act
-	err := storage.UpdateDescription("go", "Go programming language")
+	err := storage.UpdateDescription(context.Background(), "go", "Go programming language")
 
 	// Assert
 	if err != nil {
@@ -144,7 +244,7 @@ func TestStorageUpdateLogo(t *testing.T) {
 	storage := NewSkillStorage(db)
 
 	// Act
-	err := storage.UpdateLogo("go", "https://golang.org/doc/gopher/frontpage.png")
+	err := storage.UpdateLogo(context.Background(), "go", "https://golang.org/doc/gopher/frontpage.png")
 
 	// Assert
 	if err != nil {
@@ -166,7 +266,7 @@ func TestStorageUpdateTags(t *testing.T) {
 	storage := NewSkillStorage(db)
 
 	// Act
-	err := storage.UpdateTags("go", []string{"go", "golang", "programming"})
+	err := storage.UpdateTags(context.Background(), "go", []string{"go", "golang", "programming"})
 
 	// Assert
 	if err != nil {
@@ -179,6 +279,72 @@ func TestStorageUpdateTags(t *testing.T) {
 	}
 }
 
+func TestStorageAddTags(t *testing.T) {
+	t.Run("should append new tags once in the order given", func(t *testing.T) {
+		// Arrange
+		db := newMockDB()
+		defer db.Close()
+		db.Exec("INSERT INTO skill (key, name, tags) VALUES ('added', 'Added', '{go,golang}')")
+
+		storage := NewSkillStorage(db)
+
+		// Act
+		err := storage.AddTags(context.Background(), "added", []string{"cloud", "go", "backend", "cloud"})
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := getData(db, "added")
+		if want := []string{"go", "golang", "cloud", "backend"}; !reflect.DeepEqual(got.Tags, want) || got.Version != 2 {
+			t.Errorf("AddTags() left %v at version %d, want %v at version 2", got.Tags, got.Version, want)
+		}
+	})
+
+	t.Run("should leave missing skill alone", func(t *testing.T) {
+		// Arrange
+		db := newMockDB()
+		defer db.Close()
+
+		storage := NewSkillStorage(db)
+
+		// Act
+		err := storage.AddTags(context.Background(), "missing", []string{"go"})
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if getData(db, "missing").Key != "" {
+			t.Errorf("expected no skill to be created")
+		}
+	})
+}
+
+func TestStorageRemoveTags(t *testing.T) {
+	// Arrange
+	db := newMockDB()
+	defer db.Close()
+	db.Exec("INSERT INTO skill (key, name, tags) VALUES ('removed', 'Removed', '{go,golang,cloud,golang}')")
+
+	storage := NewSkillStorage(db)
+
+	// Act
+	err := storage.RemoveTags(context.Background(), "removed", []string{"golang", "rust"})
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := getData(db, "removed")
+	if want := []string{"go", "cloud"}; !reflect.DeepEqual(got.Tags, want) || got.Version != 2 {
+		t.Errorf("RemoveTags() left %v at version %d, want %v at version 2", got.Tags, got.Version, want)
+	}
+}
+
 func TestStorageDeleteSkill(t *testing.T) {
 	// Arrange
 	db := newMockDB()
@@ -188,14 +354,351 @@ func TestStorageDeleteSkill(t *testing.T) {
 	storage := NewSkillStorage(db)
 
 	// Act
-	err := storage.DeleteSkill("go")
+	err := storage.DeleteSkill(context.Background(), "go")
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if getCount(db) != 1 {
+		t.Errorf("getCount() = %d, want the skill kept in the trash", getCount(db))
+	}
+
+	if _, err := storage.GetSkill(context.Background(), "go"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetSkill() error = %v, want %v", err, sql.ErrNoRows)
+	}
+}
+
+func TestStorageGetDeletedSkill(t *testing.T) {
+	// Arrange
+	db := newMockDB()
+	defer db.Close()
+	db.Exec("INSERT INTO skill (key, name, version, deleted_at) VALUES ('trashed', 'Trashed', 3, CURRENT_TIMESTAMP)")
+	db.Exec("INSERT INTO skill (key, name) VALUES ('live', 'Live')")
+
+	storage := NewSkillStorage(db)
+
+	// Act
+	skill, err := storage.GetDeletedSkill(context.Background(), "trashed")
 
 	// Assert
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	if getCount(db) != 0 {
-		t.Errorf("getCount() = %d, want 0", getCount(db))
+	if skill.Key != "trashed" || skill.Version != 3 {
+		t.Errorf("GetDeletedSkill() = %+v, want trashed at version 3", skill)
 	}
+
+	if _, err := storage.GetDeletedSkill(context.Background(), "live"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetDeletedSkill() error = %v, want %v", err, sql.ErrNoRows)
+	}
+}
+
+func TestStorageRestoreSkill(t *testing.T) {
+	// Arrange
+	db := newMockDB()
+	defer db.Close()
+	db.Exec("INSERT INTO skill (key, name, description, logo, tags, version, deleted_at) VALUES ('go', 'Go', 'Golang', 'https://golang.org/doc/gopher/frontpage.png', '{go, golang}', 2, CURRENT_TIMESTAMP)")
+
+	storage := NewSkillStorage(db)
+
+	// Act
+	err := storage.RestoreSkill(context.Background(), "go")
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	skill, err := storage.GetSkill(context.Background(), "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if skill.Name != "Go" || skill.Version != 3 {
+		t.Errorf("GetSkill() = %v, want Go at version 3", skill)
+	}
+}
+
+func TestStoragePurgeDeleted(t *testing.T) {
+	// Arrange
+	db := newMockDB()
+	defer db.Close()
+	now := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	db.Exec("INSERT INTO skill (key, name, deleted_at) VALUES ('old', 'Old', $1)", now.Add(-48*time.Hour))
+	db.Exec("INSERT INTO skill (key, name, deleted_at) VALUES ('recent', 'Recent', $1)", now.Add(-time.Hour))
+	db.Exec("INSERT INTO skill (key, name) VALUES ('go', 'Go')")
+
+	storage := NewSkillStorage(db)
+
+	// Act
+	n, err := storage.PurgeDeleted(context.Background(), now.Add(-24*time.Hour))
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 1 || getCount(db) != 2 {
+		t.Errorf("PurgeDeleted() = %d leaving %d skills, want 1 leaving 2", n, getCount(db))
+	}
+
+	if getData(db, "old").Key != "" {
+		t.Errorf("expected old to be purged")
+	}
+}
+
+func TestStorageCountTags(t *testing.T) {
+	// Arrange
+	db := newMockDB()
+	defer db.Close()
+	db.Exec("INSERT INTO skill_tag (tag, count) VALUES ('go', 2), ('legacy', 1)")
+
+	storage := NewSkillStorage(db)
+
+	// Act
+	err := storage.CountTags(context.Background(), []string{"backend", "go"}, []string{"legacy"})
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, _ := db.Query("SELECT tag, count FROM skill_tag ORDER BY tag")
+	defer rows.Close()
+	counts := map[string]int{}
+	for rows.Next() {
+		var tag string
+		var count int
+		rows.Scan(&tag, &count)
+		counts[tag] = count
+	}
+
+	want := map[string]int{"backend": 1, "go": 3, "legacy": 0}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("tag counts = %v, want %v", counts, want)
+	}
+}
+
+func TestStorageEnqueueEvent(t *testing.T) {
+	// Arrange
+	db := newMockDB()
+	defer db.Close()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator()) })
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "skill process")
+	defer span.End()
+
+	storage := NewSkillStorage(db)
+
+	// Act
+	err := storage.EnqueueEvent(ctx, SkillEvent{Type: SkillNameChangedEvent, Key: "enqueued", Before: &Skill{Name: "Go"}, After: &Skill{Name: "Golang"}})
+
+	// Assert
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var value, headers []byte
+	db.QueryRow("SELECT value, headers FROM skill_event_outbox WHERE key = 'enqueued'").Scan(&value, &headers)
+
+	var event SkillEvent
+	if err := json.Unmarshal(value, &event); err != nil || event.Type != SkillNameChangedEvent || event.After.Name != "Golang" {
+		t.Errorf("expected %s event to Golang, got %s (%v)", SkillNameChangedEvent, value, err)
+	}
+
+	var got map[string]string
+	json.Unmarshal(headers, &got)
+	want := "00-" + span.SpanContext().TraceID().String() + "-" + span.SpanContext().SpanID().String() + "-01"
+	if got[EventTypeHeader] != string(SkillNameChangedEvent) || got["traceparent"] != want {
+		t.Errorf("expected event type and traceparent %s in headers, got %v", want, got)
+	}
+}
+
+func TestStorageRecordHistory(t *testing.T) {
+	t.Run("should record before and after state of skill", func(t *testing.T) {
+		// Arrange
+		db := newMockDB()
+		defer db.Close()
+
+		storage := NewSkillStorage(db)
+		entry := HistoryEntry{
+			Key:        "go",
+			Action:     UpdateNameAction,
+			Before:     &Skill{Key: "go", Name: "Go"},
+			After:      &Skill{Key: "go", Name: "Golang"},
+			Actor:      "alice",
+			MessageID:  "msg-1",
+			OccurredAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		}
+
+		// Act
+		err := storage.RecordHistory(context.Background(), entry)
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var action, actor, messageID string
+		var before, after sql.NullString
+		db.QueryRow("SELECT action, before, after, actor, message_id FROM skill_history WHERE skill_key = 'go'").Scan(&action, &before, &after, &actor, &messageID)
+		if action != string(UpdateNameAction) || actor != "alice" || messageID != "msg-1" {
+			t.Errorf("got %s by %s from %s, want %s by alice from msg-1", action, actor, messageID, UpdateNameAction)
+		}
+
+		var got Skill
+		if err := json.Unmarshal([]byte(after.String), &got); err != nil || got.Name != "Golang" {
+			t.Errorf("got after %s, want skill named Golang", after.String)
+		}
+
+		if !before.Valid {
+			t.Errorf("expected before state to be recorded")
+		}
+	})
+
+	t.Run("should store missing state as null", func(t *testing.T) {
+		// Arrange
+		db := newMockDB()
+		defer db.Close()
+
+		storage := NewSkillStorage(db)
+
+		// Act
+		err := storage.RecordHistory(context.Background(), HistoryEntry{
+			Key:    "go",
+			Action: CreateSkillAction,
+			After:  &Skill{Key: "go", Name: "Go"},
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var before sql.NullString
+		db.QueryRow("SELECT before FROM skill_history WHERE skill_key = 'go'").Scan(&before)
+		if before.Valid {
+			t.Errorf("got before %s, want NULL", before.String)
+		}
+	})
+}
+
+func TestStorageInTx(t *testing.T) {
+	t.Run("should commit writes when fn succeeds", func(t *testing.T) {
+		// Arrange
+		db := newMockDB()
+		defer db.Close()
+
+		storage := NewSkillStorage(db)
+
+		// Act
+		err := storage.InTx(context.Background(), func(tx SkillStorage) error {
+			return tx.CreateSkill(context.Background(), CreateSkillRequest{Key: "go", Name: "Go", Tags: []string{"go"}})
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if count := getCount(db); count != 1 {
+			t.Errorf("got %d skills, want 1", count)
+		}
+	})
+
+	t.Run("should roll back writes when fn fails", func(t *testing.T) {
+		// Arrange
+		db := newMockDB()
+		defer db.Close()
+
+		storage := NewSkillStorage(db)
+		failure := errors.New("history failed")
+
+		// Act
+		err := storage.InTx(context.Background(), func(tx SkillStorage) error {
+			if err := tx.CreateSkill(context.Background(), CreateSkillRequest{Key: "go", Name: "Go", Tags: []string{"go"}}); err != nil {
+				return err
+			}
+			return failure
+		})
+
+		// Assert
+		if !errors.Is(err, failure) {
+			t.Errorf("InTx() error = %v, want %v", err, failure)
+		}
+
+		if count := getCount(db); count != 0 {
+			t.Errorf("got %d skills, want 0", count)
+		}
+	})
+}
+
+func TestStorageSearch(t *testing.T) {
+	t.Run("should weigh name, description and tags of created skill", func(t *testing.T) {
+		// Arrange
+		db := newMockDB()
+		defer db.Close()
+		storage := NewSkillStorage(db)
+
+		// Act
+		err := storage.CreateSkill(context.Background(), CreateSkillRequest{Key: "go", Name: "Go", Description: "Golang", Tags: []string{"go"}})
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := getSearch(db, "go"); got != `Go:A Golang:B {"go"}:C ` {
+			t.Errorf("search = %q, want %q", got, `Go:A Golang:B {"go"}:C `)
+		}
+	})
+
+	t.Run("should follow changed name", func(t *testing.T) {
+		// Arrange
+		db := newMockDB()
+		defer db.Close()
+		db.Exec("INSERT INTO skill (key, name, description, logo, tags) VALUES ('go', 'Go', 'Golang', 'https://golang.org/doc/gopher/frontpage.png', '{go, golang}')")
+		storage := NewSkillStorage(db)
+
+		// Act
+		err := storage.UpdateName(context.Background(), "go", "Golang Intensive Course")
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := getSearch(db, "go"); got != "Golang Intensive Course:A Golang:B {go, golang}:C " {
+			t.Errorf("search = %q, want %q", got, "Golang Intensive Course:A Golang:B {go, golang}:C ")
+		}
+	})
+
+	t.Run("should leave search alone when logo changes", func(t *testing.T) {
+		// Arrange
+		db := newMockDB()
+		defer db.Close()
+		db.Exec("INSERT INTO skill (key, name, description, logo, tags, search) VALUES ('go', 'Go', 'Golang', '', '{go}', 'indexed')")
+		storage := NewSkillStorage(db)
+
+		// Act
+		err := storage.UpdateLogo(context.Background(), "go", "https://golang.org/doc/gopher/frontpage.png")
+
+		// Assert
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := getSearch(db, "go"); got != "indexed" {
+			t.Errorf("search = %q, want indexed", got)
+		}
+	})
+}
+
+func getSearch(db *sql.DB, key string) string {
+	var search string
+	db.QueryRow("SELECT search FROM skill WHERE key = $1", key).Scan(&search)
+	return search
 }
@@ -0,0 +1,130 @@
+package skill
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"skill-api-kafka-contract/message"
+)
+
+// PayloadVersion is the envelope version HandleSkill understands. Messages
+// sent before the envelope existed carry no version and are version 1.
+const PayloadVersion = message.PayloadVersion
+
+var ErrUnsupportedVersion = message.ErrUnsupportedVersion
+
+// Upcaster rewrites a raw message of one version into the shape of the next.
+type Upcaster func(msg map[string]any) (map[string]any, error)
+
+type UpcasterRegistry struct {
+	upcasters map[int]Upcaster
+}
+
+func NewUpcasterRegistry() UpcasterRegistry {
+	return UpcasterRegistry{
+		upcasters: map[int]Upcaster{},
+	}
+}
+
+// DefaultUpcasters returns the registry for every version the API has
+// ever produced.
+func DefaultUpcasters() UpcasterRegistry {
+	r := NewUpcasterRegistry()
+	r.Register(1, upcastV1)
+	return r
+}
+
+// NewJSONSerializer decodes JSON messages of any version the registry can
+// upcast. Protobuf messages need no upcasting, their schemas evolve by the
+// registry's compatibility rules instead.
+func NewJSONSerializer(upcasters UpcasterRegistry) message.Serializer {
+	return jsonSerializer{upcasters: upcasters}
+}
+
+type jsonSerializer struct {
+	message.JSONSerializer
+	upcasters UpcasterRegistry
+}
+
+func (s jsonSerializer) Unmarshal(data []byte) (*SkillQueuePayload, error) {
+	var raw map[string]any
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if raw == nil {
+		return nil, errors.New("message is empty")
+	}
+
+	raw, err = s.upcasters.Upcast(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return message.Decode(current)
+}
+
+// Register sets the upcaster that moves a message from version to version+1.
+func (r UpcasterRegistry) Register(version int, upcaster Upcaster) {
+	r.upcasters[version] = upcaster
+}
+
+// Upcast applies upcasters one version at a time until the message reaches
+// PayloadVersion. Messages newer than PayloadVersion are rejected, since
+// this consumer cannot know what changed in them.
+func (r UpcasterRegistry) Upcast(msg map[string]any) (map[string]any, error) {
+	version, err := messageVersion(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if version > PayloadVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+
+	for version < PayloadVersion {
+		upcaster, ok := r.upcasters[version]
+		if !ok {
+			return nil, fmt.Errorf("%w: no upcaster from version %d", ErrUnsupportedVersion, version)
+		}
+
+		msg, err = upcaster(msg)
+		if err != nil {
+			return nil, fmt.Errorf("upcast from version %d: %w", version, err)
+		}
+
+		version++
+		msg["version"] = version
+	}
+
+	return msg, nil
+}
+
+func messageVersion(msg map[string]any) (int, error) {
+	v, ok := msg["version"]
+	if !ok || v == nil {
+		return 1, nil
+	}
+
+	n, ok := v.(float64)
+	if !ok || n < 1 || n != float64(int(n)) {
+		return 0, fmt.Errorf("%w: %v", ErrUnsupportedVersion, v)
+	}
+
+	return int(n), nil
+}
+
+// upcastV1 wraps a message sent before the envelope existed. The original
+// producer and send time were never recorded, so they stay unknown.
+func upcastV1(msg map[string]any) (map[string]any, error) {
+	if _, ok := msg["producer"]; !ok {
+		msg["producer"] = "unknown"
+	}
+	return msg, nil
+}
@@ -0,0 +1,107 @@
+package skill
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUpcast(t *testing.T) {
+	t.Run("should upcast message without version to current version", func(t *testing.T) {
+		// Arrange
+		r := DefaultUpcasters()
+
+		// Act
+		message, err := r.Upcast(map[string]any{"action": "create", "key": "go"})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if message["version"] != PayloadVersion {
+			t.Errorf("expected version %d, got %v", PayloadVersion, message["version"])
+		}
+
+		if message["producer"] != "unknown" {
+			t.Errorf("expected producer unknown, got %v", message["producer"])
+		}
+	})
+
+	t.Run("should leave current version unchanged", func(t *testing.T) {
+		// Arrange
+		r := DefaultUpcasters()
+
+		// Act
+		message, err := r.Upcast(map[string]any{"version": float64(PayloadVersion), "producer": "skill-api/a"})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if message["producer"] != "skill-api/a" {
+			t.Errorf("expected producer skill-api/a, got %v", message["producer"])
+		}
+	})
+
+	t.Run("should apply registered upcasters in order", func(t *testing.T) {
+		// Arrange
+		r := NewUpcasterRegistry()
+		var order []int
+		r.Register(1, func(m map[string]any) (map[string]any, error) {
+			order = append(order, 1)
+			return m, nil
+		})
+
+		// Act
+		_, err := r.Upcast(map[string]any{"version": float64(1)})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if len(order) != 1 {
+			t.Errorf("expected upcaster from version 1 to run once, got %v", order)
+		}
+	})
+
+	t.Run("should reject version without upcaster", func(t *testing.T) {
+		// Arrange
+		r := NewUpcasterRegistry()
+
+		// Act
+		_, err := r.Upcast(map[string]any{"version": float64(1)})
+
+		// Assert
+		if !errors.Is(err, ErrUnsupportedVersion) {
+			t.Errorf("expected %s, got %v", ErrUnsupportedVersion, err)
+		}
+	})
+
+	t.Run("should reject version newer than current", func(t *testing.T) {
+		// Arrange
+		r := DefaultUpcasters()
+
+		// Act
+		_, err := r.Upcast(map[string]any{"version": float64(PayloadVersion + 1)})
+
+		// Assert
+		if !errors.Is(err, ErrUnsupportedVersion) {
+			t.Errorf("expected %s, got %v", ErrUnsupportedVersion, err)
+		}
+	})
+
+	t.Run("should reject invalid version", func(t *testing.T) {
+		// Arrange
+		r := DefaultUpcasters()
+
+		// Act
+		_, err := r.Upcast(map[string]any{"version": "two"})
+
+		// Assert
+		if !errors.Is(err, ErrUnsupportedVersion) {
+			t.Errorf("expected %s, got %v", ErrUnsupportedVersion, err)
+		}
+	})
+}
@@ -0,0 +1,12 @@
+package tracing
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const ServiceName = "skill-consumer"
+
+// Tracer resolves the global provider on every call, so spans started before
+// Setup simply go nowhere.
+var Tracer trace.Tracer = otel.Tracer("skill-api-kafka-consumer")
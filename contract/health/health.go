@@ -0,0 +1,98 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Check reports whether one dependency is usable, nil meaning it is.
+type Check func(ctx context.Context) error
+
+type Result struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+type Checker struct {
+	checks  map[string]Check
+	timeout time.Duration
+}
+
+// NewChecker runs every registered check with the given timeout. A check
+// still running when it expires is reported down.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{
+		checks:  map[string]Check{},
+		timeout: timeout,
+	}
+}
+
+func (c *Checker) Register(name string, check Check) {
+	c.checks[name] = check
+}
+
+// Check runs all checks at once, so a slow dependency does not delay the
+// report on the others. The report is up only if every check is.
+func (c *Checker) Check(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	names := make([]string, 0, len(c.checks))
+	for name := range c.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make([]Result, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = run(ctx, check)
+		}(i, c.checks[name])
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]Result, len(names))}
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+// run gives up on check once ctx is done. Checks that cannot be cancelled,
+// such as a Kafka metadata fetch, finish in the background.
+func run(ctx context.Context, check Check) Result {
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		return Result{Status: StatusDown, Error: err.Error()}
+	}
+	return Result{Status: StatusUp}
+}
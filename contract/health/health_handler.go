@@ -0,0 +1,43 @@
+package health
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+type healthHandler struct {
+	checker *Checker
+}
+
+func NewHealthHandler(checker *Checker) healthHandler {
+	return healthHandler{
+		checker: checker,
+	}
+}
+
+// Liveness only tells whether the process can still serve requests, so a
+// broken dependency never gets the instance restarted.
+func (h healthHandler) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeReport(w, http.StatusOK, Report{Status: StatusUp})
+}
+
+// Readiness reports each dependency and fails while any of them is down, so
+// traffic is only routed to instances able to serve it.
+func (h healthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	report := h.checker.Check(r.Context())
+	if report.Status != StatusUp {
+		writeReport(w, http.StatusServiceUnavailable, report)
+		return
+	}
+
+	writeReport(w, http.StatusOK, report)
+}
+
+func writeReport(w http.ResponseWriter, status int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Println("Error writing health report:", err)
+	}
+}
@@ -0,0 +1,104 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func up(ctx context.Context) error {
+	return nil
+}
+
+func down(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+func hang(ctx context.Context) error {
+	time.Sleep(time.Second)
+	return nil
+}
+
+func TestReadinessHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		checks         map[string]Check
+		expectedStatus int
+		expectedReport Report
+	}{
+		{
+			name:           "should be ready when every dependency is up",
+			checks:         map[string]Check{"postgres": up, "kafka": up},
+			expectedStatus: http.StatusOK,
+			expectedReport: Report{Status: StatusUp, Checks: map[string]Result{
+				"postgres": {Status: StatusUp},
+				"kafka":    {Status: StatusUp},
+			}},
+		},
+		{
+			name:           "should not be ready when a dependency is down",
+			checks:         map[string]Check{"postgres": up, "kafka": down},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedReport: Report{Status: StatusDown, Checks: map[string]Result{
+				"postgres": {Status: StatusUp},
+				"kafka":    {Status: StatusDown, Error: "connection refused"},
+			}},
+		},
+		{
+			name:           "should not be ready when a dependency does not answer in time",
+			checks:         map[string]Check{"postgres": hang},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedReport: Report{Status: StatusDown, Checks: map[string]Result{
+				"postgres": {Status: StatusDown, Error: context.DeadlineExceeded.Error()},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			checker := NewChecker(50 * time.Millisecond)
+			for name, check := range tt.checks {
+				checker.Register(name, check)
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			NewHealthHandler(checker).Readiness(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var report Report
+			if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(report, tt.expectedReport) {
+				t.Errorf("expected report %+v, got %+v", tt.expectedReport, report)
+			}
+		})
+	}
+}
+
+func TestLivenessHandler(t *testing.T) {
+	// Arrange
+	checker := NewChecker(50 * time.Millisecond)
+	checker.Register("postgres", down)
+	w := httptest.NewRecorder()
+
+	// Act
+	NewHealthHandler(checker).Liveness(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	// Assert
+	if w.Code != http.StatusOK || w.Body.String() != "{\"status\":\"up\"}\n" {
+		t.Errorf("expected live instance regardless of dependencies, got %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,46 @@
+package message
+
+type SkillAction string
+
+const (
+	CreateSkillAction SkillAction = "create"
+	UpdateSkillAction SkillAction = "update"
+	DeleteSkillAction SkillAction = "delete"
+	UpdateNameAction  SkillAction = "update_name"
+	UpdateDescAction  SkillAction = "update_desc"
+	UpdateLogoAction  SkillAction = "update_logo"
+	UpdateTagsAction  SkillAction = "update_tags"
+	// RestoreSkillAction brings a deleted skill back out of the trash.
+	RestoreSkillAction SkillAction = "restore"
+	// AddTagsAction and RemoveTagsAction change some tags of a skill and
+	// leave the rest as the consumer finds them, so concurrent edits merge.
+	AddTagsAction    SkillAction = "add_tags"
+	RemoveTagsAction SkillAction = "remove_tags"
+)
+
+// Actions lists every action the API may publish. The consumer's contract
+// test walks it, so adding an action here fails that build until the
+// consumer handles it.
+func Actions() []SkillAction {
+	return []SkillAction{
+		CreateSkillAction,
+		UpdateSkillAction,
+		DeleteSkillAction,
+		UpdateNameAction,
+		UpdateDescAction,
+		UpdateLogoAction,
+		UpdateTagsAction,
+		RestoreSkillAction,
+		AddTagsAction,
+		RemoveTagsAction,
+	}
+}
+
+func (a SkillAction) Valid() bool {
+	for _, action := range Actions() {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
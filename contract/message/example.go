@@ -0,0 +1,56 @@
+package message
+
+import "time"
+
+// Examples returns one valid message per action. The golden files in
+// testdata pin their wire format, and both services run them through their
+// own producer and consumer code in contract tests.
+func Examples() []SkillQueuePayload {
+	key := "go"
+	envelope := func(action SkillAction, payload any) SkillQueuePayload {
+		// A skill being created has no version to expect yet, and tag edits
+		// merge with whatever the skill holds.
+		var expectedVersion int64
+		switch action {
+		case CreateSkillAction, AddTagsAction, RemoveTagsAction:
+		default:
+			expectedVersion = 3
+		}
+
+		return SkillQueuePayload{
+			Version:         PayloadVersion,
+			MessageID:       "6f1c3b5e-8f0a-4d7e-9b2a-2f4c1e0d9a11",
+			Timestamp:       time.Date(2024, 7, 1, 9, 30, 0, 0, time.UTC),
+			Producer:        "skill-api/example",
+			Actor:           "alice",
+			ExpectedVersion: expectedVersion,
+			Action:          action,
+			Key:             &key,
+			Payload:         payload,
+		}
+	}
+
+	return []SkillQueuePayload{
+		envelope(CreateSkillAction, CreateSkillRequest{
+			Key:         "go",
+			Name:        "Go",
+			Description: "Go is an open source programming language.",
+			Logo:        "https://go.dev/images/go-logo-blue.svg",
+			Tags:        []string{"programming language", "system"},
+		}),
+		envelope(UpdateSkillAction, UpdateSkillRequest{
+			Name:        "Golang",
+			Description: "Go is an open source programming language.",
+			Logo:        "https://go.dev/images/go-logo-blue.svg",
+			Tags:        []string{"programming language"},
+		}),
+		envelope(DeleteSkillAction, nil),
+		envelope(UpdateNameAction, UpdateSkillNameRequest{Name: "Golang"}),
+		envelope(UpdateDescAction, UpdateSkillDescriptionRequest{Description: "A language built for simplicity."}),
+		envelope(UpdateLogoAction, UpdateSkillLogoRequest{Logo: "https://go.dev/images/gophers/ladder.svg"}),
+		envelope(UpdateTagsAction, UpdateSkillTagsRequest{Tags: []string{"backend"}}),
+		envelope(RestoreSkillAction, nil),
+		envelope(AddTagsAction, AddSkillTagsRequest{Tags: []string{"cloud", "backend"}}),
+		envelope(RemoveTagsAction, RemoveSkillTagsRequest{Tags: []string{"system"}}),
+	}
+}
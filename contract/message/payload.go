@@ -0,0 +1,167 @@
+package message
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PayloadVersion is the envelope version the API produces. Bump it whenever
+// the message shape changes and register an upcaster for the previous
+// version in the consumer, so messages still in flight keep working.
+const PayloadVersion = 2
+
+const (
+	OperationIDHeader = "x-operation-id"
+	RequestIDHeader   = "x-request-id"
+)
+
+var (
+	ErrInvalidSkillAction = errors.New("invalid skill action")
+	ErrUnsupportedVersion = errors.New("unsupported payload version")
+	ErrActionEmpty        = errors.New("action is empty")
+	ErrKeyNil             = errors.New("key is nil")
+)
+
+type SkillQueuePayload struct {
+	Version   int       `json:"version"`
+	MessageID string    `json:"message_id"`
+	Timestamp time.Time `json:"timestamp"`
+	Producer  string    `json:"producer"`
+	// Actor is who asked for the change, empty when unknown. It is optional,
+	// so adding it did not need a new version.
+	Actor string `json:"actor,omitempty"`
+	// ExpectedVersion is the version of the skill the change was made
+	// against, the consumer rejects the change once the skill has moved on.
+	// Zero applies the change to whatever version is current.
+	ExpectedVersion int64       `json:"expected_version,omitempty"`
+	Action          SkillAction `json:"action"`
+	Key             *string     `json:"key"`
+	Payload         any         `json:"payload"`
+}
+
+// Validate checks the envelope and that the payload is the request the
+// action expects.
+func (p SkillQueuePayload) Validate() error {
+	if p.Version != PayloadVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, p.Version)
+	}
+
+	if p.Action == "" {
+		return ErrActionEmpty
+	}
+
+	if p.Key == nil {
+		return ErrKeyNil
+	}
+
+	switch p.Action {
+	case CreateSkillAction:
+		return validateRequest[CreateSkillRequest](p.Payload)
+	case UpdateSkillAction:
+		return validateRequest[UpdateSkillRequest](p.Payload)
+	case UpdateNameAction:
+		return validateRequest[UpdateSkillNameRequest](p.Payload)
+	case UpdateDescAction:
+		return validateRequest[UpdateSkillDescriptionRequest](p.Payload)
+	case UpdateLogoAction:
+		return validateRequest[UpdateSkillLogoRequest](p.Payload)
+	case UpdateTagsAction:
+		return validateRequest[UpdateSkillTagsRequest](p.Payload)
+	case AddTagsAction:
+		return validateRequest[AddSkillTagsRequest](p.Payload)
+	case RemoveTagsAction:
+		return validateRequest[RemoveSkillTagsRequest](p.Payload)
+	case DeleteSkillAction, RestoreSkillAction:
+		return nil
+	default:
+		return ErrInvalidSkillAction
+	}
+}
+
+// Encode refuses to produce a message the consumer would reject.
+func Encode(p SkillQueuePayload) ([]byte, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(p)
+}
+
+// Decode expects a message already at PayloadVersion, older messages have
+// to be upcast first. The payload is decoded straight into the request type
+// of the action.
+func Decode(data []byte) (*SkillQueuePayload, error) {
+	var envelope *struct {
+		SkillQueuePayload
+		Payload json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+
+	if envelope == nil {
+		return nil, errors.New("message is empty")
+	}
+
+	p := envelope.SkillQueuePayload
+	payload, err := decodePayload(p.Action, envelope.Payload)
+	if err != nil {
+		return nil, err
+	}
+	p.Payload = payload
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func decodePayload(action SkillAction, raw json.RawMessage) (any, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	switch action {
+	case CreateSkillAction:
+		return unmarshalRequest[CreateSkillRequest](raw)
+	case UpdateSkillAction:
+		return unmarshalRequest[UpdateSkillRequest](raw)
+	case UpdateNameAction:
+		return unmarshalRequest[UpdateSkillNameRequest](raw)
+	case UpdateDescAction:
+		return unmarshalRequest[UpdateSkillDescriptionRequest](raw)
+	case UpdateLogoAction:
+		return unmarshalRequest[UpdateSkillLogoRequest](raw)
+	case UpdateTagsAction:
+		return unmarshalRequest[UpdateSkillTagsRequest](raw)
+	case AddTagsAction:
+		return unmarshalRequest[AddSkillTagsRequest](raw)
+	case RemoveTagsAction:
+		return unmarshalRequest[RemoveSkillTagsRequest](raw)
+	default:
+		// Left generic, Validate reports what is wrong with the message.
+		var payload any
+		err := json.Unmarshal(raw, &payload)
+		return payload, err
+	}
+}
+
+func unmarshalRequest[T Request](raw json.RawMessage) (any, error) {
+	if string(raw) == "null" {
+		return nil, nil
+	}
+
+	var req T
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func validateRequest[T Request](payload any) error {
+	_, err := DecodeRequest[T](payload)
+	return err
+}
@@ -0,0 +1,162 @@
+package message
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestExamplesCoverEveryAction(t *testing.T) {
+	// Arrange
+	covered := map[SkillAction]bool{}
+	for _, example := range Examples() {
+		covered[example.Action] = true
+	}
+
+	// Act & Assert
+	for _, action := range Actions() {
+		if !covered[action] {
+			t.Errorf("expected an example for action %s", action)
+		}
+	}
+}
+
+func TestEncodeMatchesGolden(t *testing.T) {
+	for _, example := range Examples() {
+		t.Run(string(example.Action), func(t *testing.T) {
+			// Arrange
+			golden := filepath.Join("testdata", string(example.Action)+".json")
+
+			// Act
+			got, err := Encode(example)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if *update {
+				if err := os.WriteFile(golden, append(got, '\n'), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(got, bytes.TrimSpace(want)) {
+				t.Errorf("wire format drifted from %s\nwant %s\ngot  %s", golden, want, got)
+			}
+		})
+	}
+}
+
+func TestDecodeGolden(t *testing.T) {
+	for _, action := range Actions() {
+		t.Run(string(action), func(t *testing.T) {
+			// Arrange
+			data, err := os.ReadFile(filepath.Join("testdata", string(action)+".json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Act
+			p, err := Decode(data)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if p.Action != action || p.Key == nil || *p.Key != "go" {
+				t.Errorf("expected %s for go, got %s for %v", action, p.Action, p.Key)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	key := "go"
+	tests := []struct {
+		name    string
+		payload SkillQueuePayload
+		want    error
+	}{
+		{
+			name:    "should reject other versions",
+			payload: SkillQueuePayload{Version: 1, Action: DeleteSkillAction, Key: &key},
+			want:    ErrUnsupportedVersion,
+		},
+		{
+			name:    "should reject empty action",
+			payload: SkillQueuePayload{Version: PayloadVersion, Key: &key},
+			want:    ErrActionEmpty,
+		},
+		{
+			name:    "should reject missing key",
+			payload: SkillQueuePayload{Version: PayloadVersion, Action: DeleteSkillAction},
+			want:    ErrKeyNil,
+		},
+		{
+			name:    "should reject unknown action",
+			payload: SkillQueuePayload{Version: PayloadVersion, Action: "rename", Key: &key},
+			want:    ErrInvalidSkillAction,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := tt.payload.Validate()
+
+			// Assert
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %s, got %v", tt.want, err)
+			}
+		})
+	}
+
+	t.Run("should reject payload that does not match the action", func(t *testing.T) {
+		// Arrange
+		p := SkillQueuePayload{Version: PayloadVersion, Action: CreateSkillAction, Key: &key, Payload: UpdateSkillNameRequest{Name: "Go"}}
+
+		// Act
+		err := p.Validate()
+
+		// Assert
+		if err == nil {
+			t.Error("expected error to be not nil")
+		}
+	})
+}
+
+func TestEncodeRejectsInvalidMessage(t *testing.T) {
+	// Arrange
+	key := "go"
+	p := SkillQueuePayload{Version: PayloadVersion, Action: UpdateNameAction, Key: &key, Payload: UpdateSkillNameRequest{}}
+
+	// Act
+	_, err := Encode(p)
+
+	// Assert
+	if err == nil {
+		t.Error("expected error to be not nil")
+	}
+}
+
+func TestDecodeEmptyMessage(t *testing.T) {
+	// Act
+	_, err := Decode([]byte(`null`))
+
+	// Assert
+	if err == nil {
+		t.Error("expected error to be not nil")
+	}
+}
@@ -0,0 +1,201 @@
+package message
+
+import (
+	"fmt"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"mime"
+	"skill-api-kafka-contract/message/skillpb"
+	"skill-api-kafka-contract/schema"
+)
+
+var skillMessageName = (&skillpb.SkillMessage{}).ProtoReflect().Descriptor().FullName()
+
+// ProtobufSerializer encodes messages as skill.v1.SkillMessage.
+type ProtobufSerializer struct{}
+
+// NewProtobufSerializer fails unless the registry holds a schema for
+// skill.v1.SkillMessage that the compiled type can read, so a service never
+// starts with code that disagrees with the registry.
+func NewProtobufSerializer(registry *schema.Registry) (ProtobufSerializer, error) {
+	if err := checkWriterSchema(registry, string(skillMessageName)); err != nil {
+		return ProtobufSerializer{}, err
+	}
+	return ProtobufSerializer{}, nil
+}
+
+func (ProtobufSerializer) ContentType() string {
+	return mime.FormatMediaType(ContentTypeProtobuf, map[string]string{"messageType": string(skillMessageName)})
+}
+
+func (ProtobufSerializer) Marshal(p SkillQueuePayload) ([]byte, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	m, err := toProto(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(m)
+}
+
+func (ProtobufSerializer) Unmarshal(data []byte) (*SkillQueuePayload, error) {
+	var m skillpb.SkillMessage
+	if err := proto.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	p := fromProto(&m)
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func checkWriterSchema(registry *schema.Registry, messageType string) error {
+	if messageType == "" {
+		return fmt.Errorf("%w: protobuf without messageType", ErrUnsupportedContentType)
+	}
+
+	writer, err := registry.Resolve(messageType)
+	if err != nil {
+		return err
+	}
+
+	reader := (&skillpb.SkillMessage{}).ProtoReflect().Descriptor()
+	return schema.CheckCompatible(writer, reader)
+}
+
+func toProto(p SkillQueuePayload) (*skillpb.SkillMessage, error) {
+	m := &skillpb.SkillMessage{
+		Version:         int32(p.Version),
+		MessageId:       p.MessageID,
+		Timestamp:       timestamppb.New(p.Timestamp),
+		Producer:        p.Producer,
+		Actor:           p.Actor,
+		ExpectedVersion: p.ExpectedVersion,
+		Action:          string(p.Action),
+		Key:             p.Key,
+	}
+
+	switch p.Action {
+	case CreateSkillAction:
+		req, err := DecodeRequest[CreateSkillRequest](p.Payload)
+		if err != nil {
+			return nil, err
+		}
+		m.Payload = &skillpb.SkillMessage_Create{Create: &skillpb.CreateSkill{
+			Key:         req.Key,
+			Name:        req.Name,
+			Description: req.Description,
+			Logo:        req.Logo,
+			Tags:        req.Tags,
+		}}
+	case UpdateSkillAction:
+		req, err := DecodeRequest[UpdateSkillRequest](p.Payload)
+		if err != nil {
+			return nil, err
+		}
+		m.Payload = &skillpb.SkillMessage_Update{Update: &skillpb.UpdateSkill{
+			Name:        req.Name,
+			Description: req.Description,
+			Logo:        req.Logo,
+			Tags:        req.Tags,
+		}}
+	case UpdateNameAction:
+		req, err := DecodeRequest[UpdateSkillNameRequest](p.Payload)
+		if err != nil {
+			return nil, err
+		}
+		m.Payload = &skillpb.SkillMessage_UpdateName{UpdateName: &skillpb.UpdateName{Name: req.Name}}
+	case UpdateDescAction:
+		req, err := DecodeRequest[UpdateSkillDescriptionRequest](p.Payload)
+		if err != nil {
+			return nil, err
+		}
+		m.Payload = &skillpb.SkillMessage_UpdateDesc{UpdateDesc: &skillpb.UpdateDescription{Description: req.Description}}
+	case UpdateLogoAction:
+		req, err := DecodeRequest[UpdateSkillLogoRequest](p.Payload)
+		if err != nil {
+			return nil, err
+		}
+		m.Payload = &skillpb.SkillMessage_UpdateLogo{UpdateLogo: &skillpb.UpdateLogo{Logo: req.Logo}}
+	case UpdateTagsAction:
+		req, err := DecodeRequest[UpdateSkillTagsRequest](p.Payload)
+		if err != nil {
+			return nil, err
+		}
+		m.Payload = &skillpb.SkillMessage_UpdateTags{UpdateTags: &skillpb.UpdateTags{Tags: req.Tags}}
+	case AddTagsAction:
+		req, err := DecodeRequest[AddSkillTagsRequest](p.Payload)
+		if err != nil {
+			return nil, err
+		}
+		m.Payload = &skillpb.SkillMessage_AddTags{AddTags: &skillpb.AddTags{Tags: req.Tags}}
+	case RemoveTagsAction:
+		req, err := DecodeRequest[RemoveSkillTagsRequest](p.Payload)
+		if err != nil {
+			return nil, err
+		}
+		m.Payload = &skillpb.SkillMessage_RemoveTags{RemoveTags: &skillpb.RemoveTags{Tags: req.Tags}}
+	}
+
+	return m, nil
+}
+
+func fromProto(m *skillpb.SkillMessage) SkillQueuePayload {
+	p := SkillQueuePayload{
+		Version:         int(m.GetVersion()),
+		MessageID:       m.GetMessageId(),
+		Producer:        m.GetProducer(),
+		Actor:           m.GetActor(),
+		ExpectedVersion: m.GetExpectedVersion(),
+		Action:          SkillAction(m.GetAction()),
+		Key:             m.Key,
+	}
+
+	if m.Timestamp != nil {
+		p.Timestamp = m.Timestamp.AsTime()
+	}
+
+	switch payload := m.Payload.(type) {
+	case *skillpb.SkillMessage_Create:
+		p.Payload = CreateSkillRequest{
+			Key:         payload.Create.GetKey(),
+			Name:        payload.Create.GetName(),
+			Description: payload.Create.GetDescription(),
+			Logo:        payload.Create.GetLogo(),
+			Tags:        tags(payload.Create.GetTags()),
+		}
+	case *skillpb.SkillMessage_Update:
+		p.Payload = UpdateSkillRequest{
+			Name:        payload.Update.GetName(),
+			Description: payload.Update.GetDescription(),
+			Logo:        payload.Update.GetLogo(),
+			Tags:        tags(payload.Update.GetTags()),
+		}
+	case *skillpb.SkillMessage_UpdateName:
+		p.Payload = UpdateSkillNameRequest{Name: payload.UpdateName.GetName()}
+	case *skillpb.SkillMessage_UpdateDesc:
+		p.Payload = UpdateSkillDescriptionRequest{Description: payload.UpdateDesc.GetDescription()}
+	case *skillpb.SkillMessage_UpdateLogo:
+		p.Payload = UpdateSkillLogoRequest{Logo: payload.UpdateLogo.GetLogo()}
+	case *skillpb.SkillMessage_UpdateTags:
+		p.Payload = UpdateSkillTagsRequest{Tags: tags(payload.UpdateTags.GetTags())}
+	case *skillpb.SkillMessage_AddTags:
+		p.Payload = AddSkillTagsRequest{Tags: tags(payload.AddTags.GetTags())}
+	case *skillpb.SkillMessage_RemoveTags:
+		p.Payload = RemoveSkillTagsRequest{Tags: tags(payload.RemoveTags.GetTags())}
+	}
+
+	return p
+}
+
+// tags never returns nil: protobuf cannot tell an empty list from a missing
+// one, and an empty list is what the sender validated.
+func tags(t []string) []string {
+	return append([]string{}, t...)
+}
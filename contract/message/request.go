@@ -0,0 +1,142 @@
+package message
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// The binding tags are enforced by Gin when the API reads a request, and
+// Validate applies the same rules anywhere else a request is decoded.
+
+type CreateSkillRequest struct {
+	Key         string   `json:"key" binding:"required"`
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description" binding:"required"`
+	Logo        string   `json:"logo" binding:"required"`
+	Tags        []string `json:"tags" binding:"required"`
+}
+
+type UpdateSkillRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description" binding:"required"`
+	Logo        string   `json:"logo" binding:"required"`
+	Tags        []string `json:"tags" binding:"required"`
+}
+
+type UpdateSkillNameRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type UpdateSkillDescriptionRequest struct {
+	Description string `json:"description" binding:"required"`
+}
+
+type UpdateSkillLogoRequest struct {
+	Logo string `json:"logo" binding:"required"`
+}
+
+type UpdateSkillTagsRequest struct {
+	Tags []string `json:"tags" binding:"required"`
+}
+
+type AddSkillTagsRequest struct {
+	Tags []string `json:"tags" binding:"required"`
+}
+
+type RemoveSkillTagsRequest struct {
+	Tags []string `json:"tags" binding:"required"`
+}
+
+type Request interface {
+	CreateSkillRequest | UpdateSkillRequest | UpdateSkillNameRequest | UpdateSkillDescriptionRequest | UpdateSkillLogoRequest | UpdateSkillTagsRequest |
+		AddSkillTagsRequest | RemoveSkillTagsRequest
+	Validate() error
+}
+
+func (r CreateSkillRequest) Validate() error {
+	return errors.Join(
+		requiredString("key", r.Key),
+		requiredString("name", r.Name),
+		requiredString("description", r.Description),
+		requiredString("logo", r.Logo),
+		requiredSlice("tags", r.Tags),
+	)
+}
+
+func (r UpdateSkillRequest) Validate() error {
+	return errors.Join(
+		requiredString("name", r.Name),
+		requiredString("description", r.Description),
+		requiredString("logo", r.Logo),
+		requiredSlice("tags", r.Tags),
+	)
+}
+
+func (r UpdateSkillNameRequest) Validate() error {
+	return requiredString("name", r.Name)
+}
+
+func (r UpdateSkillDescriptionRequest) Validate() error {
+	return requiredString("description", r.Description)
+}
+
+func (r UpdateSkillLogoRequest) Validate() error {
+	return requiredString("logo", r.Logo)
+}
+
+func (r UpdateSkillTagsRequest) Validate() error {
+	return requiredSlice("tags", r.Tags)
+}
+
+func (r AddSkillTagsRequest) Validate() error {
+	return requiredSlice("tags", r.Tags)
+}
+
+func (r RemoveSkillTagsRequest) Validate() error {
+	return requiredSlice("tags", r.Tags)
+}
+
+// DecodeRequest returns the payload as the request type of its action and
+// validates it. Decoded messages already carry that type, anything else,
+// such as generic JSON, is converted.
+func DecodeRequest[T Request](payload any) (*T, error) {
+	if req, ok := payload.(T); ok {
+		if err := req.Validate(); err != nil {
+			return nil, err
+		}
+		return &req, nil
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var req T
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+// requiredString and requiredSlice mirror the validator's required rule:
+// a string must not be empty and a slice must not be nil, though it may be
+// empty.
+func requiredString(field string, value string) error {
+	if value == "" {
+		return errors.New(field + " is required")
+	}
+	return nil
+}
+
+func requiredSlice(field string, value []string) error {
+	if value == nil {
+		return errors.New(field + " is required")
+	}
+	return nil
+}
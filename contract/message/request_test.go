@@ -0,0 +1,78 @@
+package message
+
+import "testing"
+
+func TestDecodeRequest(t *testing.T) {
+	t.Run("should return CreateSkillRequest", func(t *testing.T) {
+		// Arrange
+		payload := map[string]interface{}{
+			"key":         "figma",
+			"name":        "Figma",
+			"description": "Figma is a vector bla bla",
+			"logo":        "logo",
+			"tags":        []string{"tag"},
+		}
+
+		// Act
+		req, err := DecodeRequest[CreateSkillRequest](payload)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if req.Key != "figma" || len(req.Tags) != 1 {
+			t.Errorf("expected figma with 1 tag, got %+v", req)
+		}
+	})
+
+	t.Run("should accept empty tags", func(t *testing.T) {
+		// Act
+		_, err := DecodeRequest[UpdateSkillTagsRequest](map[string]interface{}{"tags": []string{}})
+
+		// Assert
+		if err != nil {
+			t.Errorf("expected no error, got %s", err)
+		}
+	})
+
+	t.Run("should return error when required field is missing", func(t *testing.T) {
+		// Act
+		_, err := DecodeRequest[CreateSkillRequest](map[string]interface{}{"key": "figma"})
+
+		// Assert
+		if err == nil {
+			t.Error("expected error to be not nil")
+		}
+	})
+
+	t.Run("should return error when payload is nil", func(t *testing.T) {
+		// Act
+		_, err := DecodeRequest[UpdateSkillNameRequest](nil)
+
+		// Assert
+		if err == nil {
+			t.Error("expected error to be not nil")
+		}
+	})
+
+	t.Run("should return error when failed to unmarshal skill data", func(t *testing.T) {
+		// Act
+		_, err := DecodeRequest[CreateSkillRequest](map[string]interface{}{"tags": "tag"})
+
+		// Assert
+		if err == nil {
+			t.Error("expected error to be not nil")
+		}
+	})
+
+	t.Run("should return error when failed to marshal skill data", func(t *testing.T) {
+		// Act
+		_, err := DecodeRequest[CreateSkillRequest](make(chan int))
+
+		// Assert
+		if err == nil || err.Error() != "json: unsupported type: chan int" {
+			t.Errorf("expected unsupported type error, got %v", err)
+		}
+	})
+}
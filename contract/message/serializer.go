@@ -0,0 +1,93 @@
+package message
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"skill-api-kafka-contract/schema"
+)
+
+const ContentTypeHeader = "content-type"
+
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+// Serializer turns messages into Kafka record values and back. The content
+// type travels in the ContentTypeHeader so the consumer can pick the same
+// serializer.
+type Serializer interface {
+	ContentType() string
+	Marshal(p SkillQueuePayload) ([]byte, error)
+	Unmarshal(data []byte) (*SkillQueuePayload, error)
+}
+
+type JSONSerializer struct{}
+
+func (JSONSerializer) ContentType() string {
+	return ContentTypeJSON
+}
+
+func (JSONSerializer) Marshal(p SkillQueuePayload) ([]byte, error) {
+	return Encode(p)
+}
+
+func (JSONSerializer) Unmarshal(data []byte) (*SkillQueuePayload, error) {
+	return Decode(data)
+}
+
+// Serializers picks a serializer by content type, checking protobuf writer
+// schemas against the registry first.
+type Serializers struct {
+	registry    *schema.Registry
+	serializers map[string]Serializer
+}
+
+func NewSerializers(registry *schema.Registry) (Serializers, error) {
+	protobuf, err := NewProtobufSerializer(registry)
+	if err != nil {
+		return Serializers{}, err
+	}
+
+	return Serializers{
+		registry: registry,
+		serializers: map[string]Serializer{
+			ContentTypeJSON:     JSONSerializer{},
+			ContentTypeProtobuf: protobuf,
+		},
+	}, nil
+}
+
+// Register replaces the serializer for a media type, without parameters.
+func (s Serializers) Register(mediaType string, serializer Serializer) {
+	s.serializers[mediaType] = serializer
+}
+
+// Lookup treats a missing content type as JSON, which is all messages sent
+// before the header existed could be.
+func (s Serializers) Lookup(contentType string) (Serializer, error) {
+	if contentType == "" {
+		contentType = ContentTypeJSON
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedContentType, contentType)
+	}
+
+	serializer, ok := s.serializers[mediaType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedContentType, contentType)
+	}
+
+	if mediaType == ContentTypeProtobuf {
+		if err := checkWriterSchema(s.registry, params["messagetype"]); err != nil {
+			return nil, err
+		}
+	}
+
+	return serializer, nil
+}
@@ -0,0 +1,171 @@
+package message
+
+import (
+	"errors"
+	"skill-api-kafka-contract/message/skillpb"
+	"skill-api-kafka-contract/schema"
+	"testing"
+	"testing/fstest"
+)
+
+func newSerializers(t *testing.T) Serializers {
+	t.Helper()
+
+	registry, err := schema.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewSerializers(registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSerializersRoundTrip(t *testing.T) {
+	s := newSerializers(t)
+
+	for _, contentType := range []string{ContentTypeJSON, ContentTypeProtobuf + "; messageType=skill.v1.SkillMessage"} {
+		serializer, err := s.Lookup(contentType)
+		if err != nil {
+			t.Fatalf("expected serializer for %s, got %s", contentType, err)
+		}
+
+		for _, example := range Examples() {
+			t.Run(contentType+"/"+string(example.Action), func(t *testing.T) {
+				// Act
+				data, err := serializer.Marshal(example)
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				got, err := serializer.Unmarshal(data)
+
+				// Assert
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+
+				want, _ := Encode(example)
+				have, _ := Encode(*got)
+				if string(have) != string(want) {
+					t.Errorf("expected %s, got %s", want, have)
+				}
+			})
+		}
+	}
+}
+
+func TestSerializersLookup(t *testing.T) {
+	s := newSerializers(t)
+
+	t.Run("should treat missing content type as json", func(t *testing.T) {
+		// Act
+		serializer, err := s.Lookup("")
+
+		// Assert
+		if err != nil || serializer.ContentType() != ContentTypeJSON {
+			t.Errorf("expected json serializer, got %v, %v", serializer, err)
+		}
+	})
+
+	t.Run("should look up its own protobuf content type", func(t *testing.T) {
+		// Arrange
+		protobuf, _ := NewProtobufSerializer(s.registry)
+
+		// Act
+		_, err := s.Lookup(protobuf.ContentType())
+
+		// Assert
+		if err != nil {
+			t.Errorf("expected no error, got %s", err)
+		}
+	})
+
+	t.Run("should reject unknown content type", func(t *testing.T) {
+		// Act
+		_, err := s.Lookup("application/avro")
+
+		// Assert
+		if !errors.Is(err, ErrUnsupportedContentType) {
+			t.Errorf("expected %s, got %v", ErrUnsupportedContentType, err)
+		}
+	})
+
+	t.Run("should reject protobuf without message type", func(t *testing.T) {
+		// Act
+		_, err := s.Lookup(ContentTypeProtobuf)
+
+		// Assert
+		if !errors.Is(err, ErrUnsupportedContentType) {
+			t.Errorf("expected %s, got %v", ErrUnsupportedContentType, err)
+		}
+	})
+
+	t.Run("should reject protobuf with unregistered message type", func(t *testing.T) {
+		// Act
+		_, err := s.Lookup(ContentTypeProtobuf + "; messageType=skill.v9.SkillMessage")
+
+		// Assert
+		if !errors.Is(err, schema.ErrSchemaNotFound) {
+			t.Errorf("expected %s, got %v", schema.ErrSchemaNotFound, err)
+		}
+	})
+}
+
+func TestNewProtobufSerializerChecksRegistry(t *testing.T) {
+	// Arrange
+	registry, err := schema.Load(fstest.MapFS{"skill/v1/skill_message.proto": {Data: []byte(
+		`syntax = "proto3"; package skill.v1; message SkillMessage { string version = 1; }`,
+	)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	_, err = NewProtobufSerializer(registry)
+
+	// Assert
+	if !errors.Is(err, schema.ErrIncompatible) {
+		t.Errorf("expected %s, got %v", schema.ErrIncompatible, err)
+	}
+}
+
+// TestRegistryMatchesGeneratedCode fails when the .proto files in the
+// registry change without regenerating skillpb.
+func TestRegistryMatchesGeneratedCode(t *testing.T) {
+	registry, err := schema.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := skillpb.File_skill_v1_skill_message_proto.Messages()
+	for i := 0; i < messages.Len(); i++ {
+		generated := messages.Get(i)
+		t.Run(string(generated.FullName()), func(t *testing.T) {
+			// Act
+			registered, err := registry.Resolve(string(generated.FullName()))
+
+			// Assert
+			if err != nil {
+				t.Fatalf("expected schema to be registered, got %s", err)
+			}
+
+			if registered.Fields().Len() != generated.Fields().Len() {
+				t.Fatalf("expected %d fields, got %d", registered.Fields().Len(), generated.Fields().Len())
+			}
+
+			fields := registered.Fields()
+			for j := 0; j < fields.Len(); j++ {
+				f := generated.Fields().ByNumber(fields.Get(j).Number())
+				if f == nil || f.Name() != fields.Get(j).Name() {
+					t.Errorf("expected field %d to be %s", fields.Get(j).Number(), fields.Get(j).Name())
+				}
+			}
+
+			if err := schema.CheckCompatible(registered, generated); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,956 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: skill/v1/skill_message.proto
+
+package skillpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SkillMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version         int32                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	MessageId       string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Timestamp       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Producer        string                 `protobuf:"bytes,4,opt,name=producer,proto3" json:"producer,omitempty"`
+	Action          string                 `protobuf:"bytes,5,opt,name=action,proto3" json:"action,omitempty"`
+	Key             *string                `protobuf:"bytes,6,opt,name=key,proto3,oneof" json:"key,omitempty"`
+	Actor           string                 `protobuf:"bytes,7,opt,name=actor,proto3" json:"actor,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,8,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	// Types that are assignable to Payload:
+	//	*SkillMessage_Create
+	//	*SkillMessage_Update
+	//	*SkillMessage_UpdateName
+	//	*SkillMessage_UpdateDesc
+	//	*SkillMessage_UpdateLogo
+	//	*SkillMessage_UpdateTags
+	//	*SkillMessage_AddTags
+	//	*SkillMessage_RemoveTags
+	Payload isSkillMessage_Payload `protobuf_oneof:"payload"`
+}
+
+func (x *SkillMessage) Reset() {
+	*x = SkillMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_skill_v1_skill_message_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SkillMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SkillMessage) ProtoMessage() {}
+
+func (x *SkillMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_skill_v1_skill_message_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SkillMessage.ProtoReflect.Descriptor instead.
+func (*SkillMessage) Descriptor() ([]byte, []int) {
+	return file_skill_v1_skill_message_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SkillMessage) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *SkillMessage) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *SkillMessage) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *SkillMessage) GetProducer() string {
+	if x != nil {
+		return x.Producer
+	}
+	return ""
+}
+
+func (x *SkillMessage) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *SkillMessage) GetKey() string {
+	if x != nil && x.Key != nil {
+		return *x.Key
+	}
+	return ""
+}
+
+func (x *SkillMessage) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *SkillMessage) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (m *SkillMessage) GetPayload() isSkillMessage_Payload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (x *SkillMessage) GetCreate() *CreateSkill {
+	if x, ok := x.GetPayload().(*SkillMessage_Create); ok {
+		return x.Create
+	}
+	return nil
+}
+
+func (x *SkillMessage) GetUpdate() *UpdateSkill {
+	if x, ok := x.GetPayload().(*SkillMessage_Update); ok {
+		return x.Update
+	}
+	return nil
+}
+
+func (x *SkillMessage) GetUpdateName() *UpdateName {
+	if x, ok := x.GetPayload().(*SkillMessage_UpdateName); ok {
+		return x.UpdateName
+	}
+	return nil
+}
+
+func (x *SkillMessage) GetUpdateDesc() *UpdateDescription {
+	if x, ok := x.GetPayload().(*SkillMessage_UpdateDesc); ok {
+		return x.UpdateDesc
+	}
+	return nil
+}
+
+func (x *SkillMessage) GetUpdateLogo() *UpdateLogo {
+	if x, ok := x.GetPayload().(*SkillMessage_UpdateLogo); ok {
+		return x.UpdateLogo
+	}
+	return nil
+}
+
+func (x *SkillMessage) GetUpdateTags() *UpdateTags {
+	if x, ok := x.GetPayload().(*SkillMessage_UpdateTags); ok {
+		return x.UpdateTags
+	}
+	return nil
+}
+
+func (x *SkillMessage) GetAddTags() *AddTags {
+	if x, ok := x.GetPayload().(*SkillMessage_AddTags); ok {
+		return x.AddTags
+	}
+	return nil
+}
+
+func (x *SkillMessage) GetRemoveTags() *RemoveTags {
+	if x, ok := x.GetPayload().(*SkillMessage_RemoveTags); ok {
+		return x.RemoveTags
+	}
+	return nil
+}
+
+type isSkillMessage_Payload interface {
+	isSkillMessage_Payload()
+}
+
+type SkillMessage_Create struct {
+	Create *CreateSkill `protobuf:"bytes,10,opt,name=create,proto3,oneof"`
+}
+
+type SkillMessage_Update struct {
+	Update *UpdateSkill `protobuf:"bytes,11,opt,name=update,proto3,oneof"`
+}
+
+type SkillMessage_UpdateName struct {
+	UpdateName *UpdateName `protobuf:"bytes,12,opt,name=update_name,json=updateName,proto3,oneof"`
+}
+
+type SkillMessage_UpdateDesc struct {
+	UpdateDesc *UpdateDescription `protobuf:"bytes,13,opt,name=update_desc,json=updateDesc,proto3,oneof"`
+}
+
+type SkillMessage_UpdateLogo struct {
+	UpdateLogo *UpdateLogo `protobuf:"bytes,14,opt,name=update_logo,json=updateLogo,proto3,oneof"`
+}
+
+type SkillMessage_UpdateTags struct {
+	UpdateTags *UpdateTags `protobuf:"bytes,15,opt,name=update_tags,json=updateTags,proto3,oneof"`
+}
+
+type SkillMessage_AddTags struct {
+	AddTags *AddTags `protobuf:"bytes,16,opt,name=add_tags,json=addTags,proto3,oneof"`
+}
+
+type SkillMessage_RemoveTags struct {
+	RemoveTags *RemoveTags `protobuf:"bytes,17,opt,name=remove_tags,json=removeTags,proto3,oneof"`
+}
+
+func (*SkillMessage_Create) isSkillMessage_Payload() {}
+
+func (*SkillMessage_Update) isSkillMessage_Payload() {}
+
+func (*SkillMessage_UpdateName) isSkillMessage_Payload() {}
+
+func (*SkillMessage_UpdateDesc) isSkillMessage_Payload() {}
+
+func (*SkillMessage_UpdateLogo) isSkillMessage_Payload() {}
+
+func (*SkillMessage_UpdateTags) isSkillMessage_Payload() {}
+
+func (*SkillMessage_AddTags) isSkillMessage_Payload() {}
+
+func (*SkillMessage_RemoveTags) isSkillMessage_Payload() {}
+
+type CreateSkill struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key         string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Name        string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string   `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Logo        string   `protobuf:"bytes,4,opt,name=logo,proto3" json:"logo,omitempty"`
+	Tags        []string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+}
+
+func (x *CreateSkill) Reset() {
+	*x = CreateSkill{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_skill_v1_skill_message_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateSkill) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSkill) ProtoMessage() {}
+
+func (x *CreateSkill) ProtoReflect() protoreflect.Message {
+	mi := &file_skill_v1_skill_message_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSkill.ProtoReflect.Descriptor instead.
+func (*CreateSkill) Descriptor() ([]byte, []int) {
+	return file_skill_v1_skill_message_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateSkill) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *CreateSkill) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateSkill) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateSkill) GetLogo() string {
+	if x != nil {
+		return x.Logo
+	}
+	return ""
+}
+
+func (x *CreateSkill) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type UpdateSkill struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description string   `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Logo        string   `protobuf:"bytes,3,opt,name=logo,proto3" json:"logo,omitempty"`
+	Tags        []string `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+}
+
+func (x *UpdateSkill) Reset() {
+	*x = UpdateSkill{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_skill_v1_skill_message_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateSkill) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSkill) ProtoMessage() {}
+
+func (x *UpdateSkill) ProtoReflect() protoreflect.Message {
+	mi := &file_skill_v1_skill_message_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSkill.ProtoReflect.Descriptor instead.
+func (*UpdateSkill) Descriptor() ([]byte, []int) {
+	return file_skill_v1_skill_message_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UpdateSkill) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateSkill) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateSkill) GetLogo() string {
+	if x != nil {
+		return x.Logo
+	}
+	return ""
+}
+
+func (x *UpdateSkill) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type UpdateName struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *UpdateName) Reset() {
+	*x = UpdateName{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_skill_v1_skill_message_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateName) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateName) ProtoMessage() {}
+
+func (x *UpdateName) ProtoReflect() protoreflect.Message {
+	mi := &file_skill_v1_skill_message_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateName.ProtoReflect.Descriptor instead.
+func (*UpdateName) Descriptor() ([]byte, []int) {
+	return file_skill_v1_skill_message_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UpdateName) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type UpdateDescription struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Description string `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+}
+
+func (x *UpdateDescription) Reset() {
+	*x = UpdateDescription{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_skill_v1_skill_message_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateDescription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDescription) ProtoMessage() {}
+
+func (x *UpdateDescription) ProtoReflect() protoreflect.Message {
+	mi := &file_skill_v1_skill_message_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDescription.ProtoReflect.Descriptor instead.
+func (*UpdateDescription) Descriptor() ([]byte, []int) {
+	return file_skill_v1_skill_message_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateDescription) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type UpdateLogo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Logo string `protobuf:"bytes,1,opt,name=logo,proto3" json:"logo,omitempty"`
+}
+
+func (x *UpdateLogo) Reset() {
+	*x = UpdateLogo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_skill_v1_skill_message_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateLogo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLogo) ProtoMessage() {}
+
+func (x *UpdateLogo) ProtoReflect() protoreflect.Message {
+	mi := &file_skill_v1_skill_message_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLogo.ProtoReflect.Descriptor instead.
+func (*UpdateLogo) Descriptor() ([]byte, []int) {
+	return file_skill_v1_skill_message_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdateLogo) GetLogo() string {
+	if x != nil {
+		return x.Logo
+	}
+	return ""
+}
+
+type UpdateTags struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tags []string `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+}
+
+func (x *UpdateTags) Reset() {
+	*x = UpdateTags{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_skill_v1_skill_message_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateTags) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTags) ProtoMessage() {}
+
+func (x *UpdateTags) ProtoReflect() protoreflect.Message {
+	mi := &file_skill_v1_skill_message_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTags.ProtoReflect.Descriptor instead.
+func (*UpdateTags) Descriptor() ([]byte, []int) {
+	return file_skill_v1_skill_message_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateTags) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type AddTags struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tags []string `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+}
+
+func (x *AddTags) Reset() {
+	*x = AddTags{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_skill_v1_skill_message_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddTags) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddTags) ProtoMessage() {}
+
+func (x *AddTags) ProtoReflect() protoreflect.Message {
+	mi := &file_skill_v1_skill_message_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddTags.ProtoReflect.Descriptor instead.
+func (*AddTags) Descriptor() ([]byte, []int) {
+	return file_skill_v1_skill_message_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AddTags) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type RemoveTags struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tags []string `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+}
+
+func (x *RemoveTags) Reset() {
+	*x = RemoveTags{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_skill_v1_skill_message_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoveTags) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveTags) ProtoMessage() {}
+
+func (x *RemoveTags) ProtoReflect() protoreflect.Message {
+	mi := &file_skill_v1_skill_message_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveTags.ProtoReflect.Descriptor instead.
+func (*RemoveTags) Descriptor() ([]byte, []int) {
+	return file_skill_v1_skill_message_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RemoveTags) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+var File_skill_v1_skill_message_proto protoreflect.FileDescriptor
+
+var file_skill_v1_skill_message_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x73, 0x6b, 0x69, 0x6c, 0x6c, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x6b, 0x69, 0x6c, 0x6c,
+	0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08,
+	0x73, 0x6b, 0x69, 0x6c, 0x6c, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd6, 0x05, 0x0a, 0x0c, 0x53, 0x6b,
+	0x69, 0x6c, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x49, 0x64, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1a, 0x0a,
+	0x08, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x15, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x88, 0x01, 0x01, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x63, 0x74, 0x6f,
+	0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x29,
+	0x0a, 0x10, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74,
+	0x65, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2f, 0x0a, 0x06, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x73, 0x6b, 0x69, 0x6c,
+	0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x6b, 0x69, 0x6c, 0x6c,
+	0x48, 0x00, 0x52, 0x06, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x2f, 0x0a, 0x06, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x73, 0x6b, 0x69,
+	0x6c, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x6b, 0x69, 0x6c,
+	0x6c, 0x48, 0x00, 0x52, 0x06, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x37, 0x0a, 0x0b, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x73, 0x6b, 0x69, 0x6c, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x48, 0x00, 0x52, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x3e, 0x0a, 0x0b, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x64,
+	0x65, 0x73, 0x63, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x73, 0x6b, 0x69, 0x6c,
+	0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x44, 0x65, 0x73, 0x63, 0x12, 0x37, 0x0a, 0x0b, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x6c,
+	0x6f, 0x67, 0x6f, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x73, 0x6b, 0x69, 0x6c,
+	0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4c, 0x6f, 0x67, 0x6f, 0x48,
+	0x00, 0x52, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4c, 0x6f, 0x67, 0x6f, 0x12, 0x37, 0x0a,
+	0x0b, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x61, 0x67, 0x73, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x73, 0x6b, 0x69, 0x6c, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x54, 0x61, 0x67, 0x73, 0x48, 0x00, 0x52, 0x0a, 0x75, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x54, 0x61, 0x67, 0x73, 0x12, 0x2e, 0x0a, 0x08, 0x61, 0x64, 0x64, 0x5f, 0x74, 0x61,
+	0x67, 0x73, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x73, 0x6b, 0x69, 0x6c, 0x6c,
+	0x2e, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x54, 0x61, 0x67, 0x73, 0x48, 0x00, 0x52, 0x07, 0x61,
+	0x64, 0x64, 0x54, 0x61, 0x67, 0x73, 0x12, 0x37, 0x0a, 0x0b, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65,
+	0x5f, 0x74, 0x61, 0x67, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x73, 0x6b,
+	0x69, 0x6c, 0x6c, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x54, 0x61, 0x67,
+	0x73, 0x48, 0x00, 0x52, 0x0a, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x54, 0x61, 0x67, 0x73, 0x42,
+	0x09, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x6b,
+	0x65, 0x79, 0x22, 0x7d, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x6b, 0x69, 0x6c,
+	0x6c, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x67,
+	0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x6f, 0x67, 0x6f, 0x12, 0x12, 0x0a,
+	0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67,
+	0x73, 0x22, 0x6b, 0x0a, 0x0b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x6b, 0x69, 0x6c, 0x6c,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x67, 0x6f, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x6f, 0x67, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61,
+	0x67, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x22, 0x20,
+	0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x22, 0x35, 0x0a, 0x11, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x20, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x4c, 0x6f, 0x67, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x67, 0x6f, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x6f, 0x67, 0x6f, 0x22, 0x20, 0x0a, 0x0a, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x54, 0x61, 0x67, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x22, 0x1d, 0x0a, 0x07, 0x41,
+	0x64, 0x64, 0x54, 0x61, 0x67, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x22, 0x20, 0x0a, 0x0a, 0x52, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x54, 0x61, 0x67, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x42, 0x2a, 0x5a, 0x28,
+	0x73, 0x6b, 0x69, 0x6c, 0x6c, 0x2d, 0x61, 0x70, 0x69, 0x2d, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x2d,
+	0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x2f, 0x73, 0x6b, 0x69, 0x6c, 0x6c, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_skill_v1_skill_message_proto_rawDescOnce sync.Once
+	file_skill_v1_skill_message_proto_rawDescData = file_skill_v1_skill_message_proto_rawDesc
+)
+
+func file_skill_v1_skill_message_proto_rawDescGZIP() []byte {
+	file_skill_v1_skill_message_proto_rawDescOnce.Do(func() {
+		file_skill_v1_skill_message_proto_rawDescData = protoimpl.X.CompressGZIP(file_skill_v1_skill_message_proto_rawDescData)
+	})
+	return file_skill_v1_skill_message_proto_rawDescData
+}
+
+var file_skill_v1_skill_message_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_skill_v1_skill_message_proto_goTypes = []any{
+	(*SkillMessage)(nil),          // 0: skill.v1.SkillMessage
+	(*CreateSkill)(nil),           // 1: skill.v1.CreateSkill
+	(*UpdateSkill)(nil),           // 2: skill.v1.UpdateSkill
+	(*UpdateName)(nil),            // 3: skill.v1.UpdateName
+	(*UpdateDescription)(nil),     // 4: skill.v1.UpdateDescription
+	(*UpdateLogo)(nil),            // 5: skill.v1.UpdateLogo
+	(*UpdateTags)(nil),            // 6: skill.v1.UpdateTags
+	(*AddTags)(nil),               // 7: skill.v1.AddTags
+	(*RemoveTags)(nil),            // 8: skill.v1.RemoveTags
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+}
+var file_skill_v1_skill_message_proto_depIdxs = []int32{
+	9, // 0: skill.v1.SkillMessage.timestamp:type_name -> google.protobuf.Timestamp
+	1, // 1: skill.v1.SkillMessage.create:type_name -> skill.v1.CreateSkill
+	2, // 2: skill.v1.SkillMessage.update:type_name -> skill.v1.UpdateSkill
+	3, // 3: skill.v1.SkillMessage.update_name:type_name -> skill.v1.UpdateName
+	4, // 4: skill.v1.SkillMessage.update_desc:type_name -> skill.v1.UpdateDescription
+	5, // 5: skill.v1.SkillMessage.update_logo:type_name -> skill.v1.UpdateLogo
+	6, // 6: skill.v1.SkillMessage.update_tags:type_name -> skill.v1.UpdateTags
+	7, // 7: skill.v1.SkillMessage.add_tags:type_name -> skill.v1.AddTags
+	8, // 8: skill.v1.SkillMessage.remove_tags:type_name -> skill.v1.RemoveTags
+	9, // [9:9] is the sub-list for method output_type
+	9, // [9:9] is the sub-list for method input_type
+	9, // [9:9] is the sub-list for extension type_name
+	9, // [9:9] is the sub-list for extension extendee
+	0, // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_skill_v1_skill_message_proto_init() }
+func file_skill_v1_skill_message_proto_init() {
+	if File_skill_v1_skill_message_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_skill_v1_skill_message_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*SkillMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_skill_v1_skill_message_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateSkill); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_skill_v1_skill_message_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateSkill); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_skill_v1_skill_message_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateName); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_skill_v1_skill_message_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateDescription); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_skill_v1_skill_message_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateLogo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_skill_v1_skill_message_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateTags); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_skill_v1_skill_message_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*AddTags); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_skill_v1_skill_message_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*RemoveTags); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_skill_v1_skill_message_proto_msgTypes[0].OneofWrappers = []any{
+		(*SkillMessage_Create)(nil),
+		(*SkillMessage_Update)(nil),
+		(*SkillMessage_UpdateName)(nil),
+		(*SkillMessage_UpdateDesc)(nil),
+		(*SkillMessage_UpdateLogo)(nil),
+		(*SkillMessage_UpdateTags)(nil),
+		(*SkillMessage_AddTags)(nil),
+		(*SkillMessage_RemoveTags)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_skill_v1_skill_message_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_skill_v1_skill_message_proto_goTypes,
+		DependencyIndexes: file_skill_v1_skill_message_proto_depIdxs,
+		MessageInfos:      file_skill_v1_skill_message_proto_msgTypes,
+	}.Build()
+	File_skill_v1_skill_message_proto = out.File
+	file_skill_v1_skill_message_proto_rawDesc = nil
+	file_skill_v1_skill_message_proto_goTypes = nil
+	file_skill_v1_skill_message_proto_depIdxs = nil
+}
@@ -0,0 +1,158 @@
+package schema
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// Files is the registry shipped with the contract, used when no registry
+// directory is configured.
+//
+//go:embed proto
+var Files embed.FS
+
+var (
+	ErrSchemaNotFound = errors.New("schema not found")
+	ErrIncompatible   = errors.New("incompatible schema")
+)
+
+// Registry resolves protobuf message types by full name from the .proto
+// files in a directory tree.
+type Registry struct {
+	messages map[protoreflect.FullName]protoreflect.MessageDescriptor
+}
+
+// Open loads the registry from dir, or from the embedded Files when dir is
+// empty.
+func Open(dir string) (*Registry, error) {
+	if dir == "" {
+		sub, err := fs.Sub(Files, "proto")
+		if err != nil {
+			return nil, err
+		}
+		return Load(sub)
+	}
+
+	return Load(os.DirFS(dir))
+}
+
+// Load compiles every .proto file in fsys. Imports are resolved relative to
+// the root of fsys, and the well-known google/protobuf types are built in.
+func Load(fsys fs.FS) (*Registry, error) {
+	var paths []string
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && path.Ext(p) == ".proto" {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: func(p string) (io.ReadCloser, error) {
+				return fsys.Open(p)
+			},
+		}),
+	}
+
+	files, err := compiler.Compile(context.Background(), paths...)
+	if err != nil {
+		return nil, fmt.Errorf("compile schemas: %w", err)
+	}
+
+	r := &Registry{messages: map[protoreflect.FullName]protoreflect.MessageDescriptor{}}
+	for _, f := range files {
+		if err := r.add(f.Messages()); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func (r *Registry) add(messages protoreflect.MessageDescriptors) error {
+	for i := 0; i < messages.Len(); i++ {
+		md := messages.Get(i)
+		if _, ok := r.messages[md.FullName()]; ok {
+			return fmt.Errorf("schema %s is defined twice", md.FullName())
+		}
+		r.messages[md.FullName()] = md
+
+		if err := r.add(md.Messages()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Registry) Resolve(name string) (protoreflect.MessageDescriptor, error) {
+	md, ok := r.messages[protoreflect.FullName(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSchemaNotFound, name)
+	}
+	return md, nil
+}
+
+// CheckCompatible reports whether messages written with writer can be read
+// with reader. Fields only one side knows are fine, protobuf skips or
+// defaults them, but a field number both sides use must keep its type and
+// cardinality.
+func CheckCompatible(writer, reader protoreflect.MessageDescriptor) error {
+	var problems []string
+	checkCompatible(writer, reader, map[protoreflect.FullName]bool{}, &problems)
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrIncompatible, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func checkCompatible(writer, reader protoreflect.MessageDescriptor, seen map[protoreflect.FullName]bool, problems *[]string) {
+	if seen[writer.FullName()] {
+		return
+	}
+	seen[writer.FullName()] = true
+
+	fields := writer.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		w := fields.Get(i)
+		r := reader.Fields().ByNumber(w.Number())
+		if r == nil {
+			continue
+		}
+
+		if w.Kind() != r.Kind() || w.Cardinality() != r.Cardinality() || w.IsMap() != r.IsMap() {
+			*problems = append(*problems, fmt.Sprintf("field %d of %s is %s %s, reader expects %s %s",
+				w.Number(), writer.FullName(), w.Cardinality(), w.Kind(), r.Cardinality(), r.Kind()))
+			continue
+		}
+
+		if w.Message() != nil {
+			if w.Message().FullName() != r.Message().FullName() {
+				*problems = append(*problems, fmt.Sprintf("field %d of %s is %s, reader expects %s",
+					w.Number(), writer.FullName(), w.Message().FullName(), r.Message().FullName()))
+				continue
+			}
+			checkCompatible(w.Message(), r.Message(), seen, problems)
+		}
+
+		if w.Enum() != nil && w.Enum().FullName() != r.Enum().FullName() {
+			*problems = append(*problems, fmt.Sprintf("field %d of %s is %s, reader expects %s",
+				w.Number(), writer.FullName(), w.Enum().FullName(), r.Enum().FullName()))
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+const v1 = `syntax = "proto3";
+package test.v1;
+import "google/protobuf/timestamp.proto";
+message Skill {
+  string key = 1;
+  repeated string tags = 2;
+  google.protobuf.Timestamp at = 3;
+}
+`
+
+func TestLoad(t *testing.T) {
+	t.Run("should resolve messages by full name", func(t *testing.T) {
+		// Arrange
+		fsys := fstest.MapFS{"test/v1/skill.proto": {Data: []byte(v1)}}
+
+		// Act
+		r, err := Load(fsys)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		md, err := r.Resolve("test.v1.Skill")
+		if err != nil {
+			t.Fatalf("expected schema to resolve, got %s", err)
+		}
+
+		if md.Fields().Len() != 3 {
+			t.Errorf("expected 3 fields, got %d", md.Fields().Len())
+		}
+	})
+
+	t.Run("should return error when schema does not compile", func(t *testing.T) {
+		// Arrange
+		fsys := fstest.MapFS{"test/v1/skill.proto": {Data: []byte(`syntax = "proto3"; message {`)}}
+
+		// Act
+		_, err := Load(fsys)
+
+		// Assert
+		if err == nil {
+			t.Error("expected error to be not nil")
+		}
+	})
+
+	t.Run("should return ErrSchemaNotFound for unknown message", func(t *testing.T) {
+		// Arrange
+		r, _ := Load(fstest.MapFS{"test/v1/skill.proto": {Data: []byte(v1)}})
+
+		// Act
+		_, err := r.Resolve("test.v1.Unknown")
+
+		// Assert
+		if !errors.Is(err, ErrSchemaNotFound) {
+			t.Errorf("expected %s, got %v", ErrSchemaNotFound, err)
+		}
+	})
+}
+
+func TestOpenEmbedded(t *testing.T) {
+	// Act
+	r, err := Open("")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if _, err := r.Resolve("skill.v1.SkillMessage"); err != nil {
+		t.Errorf("expected skill.v1.SkillMessage to be registered, got %s", err)
+	}
+}
+
+func TestCheckCompatible(t *testing.T) {
+	tests := []struct {
+		name   string
+		writer string
+		want   error
+	}{
+		{
+			name:   "should accept same schema",
+			writer: v1,
+		},
+		{
+			name:   "should accept added and removed fields",
+			writer: `syntax = "proto3"; package test.v1; message Skill { string key = 1; string logo = 4; }`,
+		},
+		{
+			name:   "should reject changed field type",
+			writer: `syntax = "proto3"; package test.v1; message Skill { int64 key = 1; }`,
+			want:   ErrIncompatible,
+		},
+		{
+			name:   "should reject changed cardinality",
+			writer: `syntax = "proto3"; package test.v1; message Skill { string tags = 2; }`,
+			want:   ErrIncompatible,
+		},
+		{
+			name:   "should reject changed message type",
+			writer: `syntax = "proto3"; package test.v1; message Other {} message Skill { Other at = 3; }`,
+			want:   ErrIncompatible,
+		},
+	}
+
+	reader, _ := Load(fstest.MapFS{"test/v1/skill.proto": {Data: []byte(v1)}})
+	readerSkill, _ := reader.Resolve("test.v1.Skill")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			writer, err := Load(fstest.MapFS{"test/v1/skill.proto": {Data: []byte(tt.writer)}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			writerSkill, _ := writer.Resolve("test.v1.Skill")
+
+			// Act
+			err = CheckCompatible(writerSkill, readerSkill)
+
+			// Assert
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
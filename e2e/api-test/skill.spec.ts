@@ -16,6 +16,7 @@ test.describe('GET /skills/:key', () => {
     test('should response skill with status success', async ({request,}) => {
         const res = await request.get(`/api/v1/skills/` + testDataKey.insertSetupKey)
         expect(res.ok()).toBeTruthy()
+        expect(res.headers()['etag']).toEqual('"1"')
         expect(await res.json()).toEqual(
             expect.objectContaining({
                 "status": "success",
@@ -38,7 +39,7 @@ test.describe('GET /skills/:key', () => {
 
 test.describe('GET /skills', () => {
     test('should response all skills with status success', async ({request,}) => {
-        const res = await request.get(`/api/v1/skills`)
+        const res = await request.get(`/api/v1/skills?key_prefix=` + testDataKey.insertSetupKey)
         expect(res.ok()).toBeTruthy()
         expect(await res.json()).toEqual(
             expect.objectContaining({
@@ -55,9 +56,77 @@ test.describe('GET /skills', () => {
                         "automation",
                         "testing"
                     ])
+                })]),
+                "page": expect.objectContaining({"total": 1})
+            }))
+    })
+
+    test('should response a page of skills with cursor of next page', async ({request,}) => {
+        const res = await request.get(`/api/v1/skills?limit=1&sort=name&order=desc`)
+        expect(res.ok()).toBeTruthy()
+        const body = await res.json()
+        expect(body.data).toHaveLength(1)
+        if (body.page.total > 1) {
+            expect(body.page.next_cursor).toBeTruthy()
+        }
+    })
+
+    test('should response bad request with invalid sort', async ({request,}) => {
+        const res = await request.get(`/api/v1/skills?sort=logo`)
+        expect(res.status()).toEqual(400)
+    })
+})
+
+test.describe('GET /skills/search', () => {
+    test('should response matching skills with highlighted snippet', async ({request,}) => {
+        const res = await request.get(`/api/v1/skills/search?q=webkit`)
+        expect(res.ok()).toBeTruthy()
+        expect(await res.json()).toEqual(
+            expect.objectContaining({
+                "status": "success",
+                "data": expect.arrayContaining([expect.objectContaining({
+                    "key": testDataKey.insertSetupKey,
+                    "snippet": expect.stringContaining("<mark>WebKit</mark>")
                 })])
             }))
     })
+
+    test('should escape markup of skill in snippet', async ({request,}) => {
+        const res = await request.get(`/api/v1/skills/search?q=sanitizer`)
+        expect(res.ok()).toBeTruthy()
+        const body = await res.json()
+        const result = body.data.find((skill: { key: string }) => skill.key === testDataKey.markupSkillKey)
+        expect(result.snippet).toContain("<mark>Sanitizer</mark>")
+        expect(result.snippet).toContain("&lt;img")
+        expect(result.snippet).not.toContain("<img")
+    })
+
+    test('should response bad request without query', async ({request,}) => {
+        const res = await request.get(`/api/v1/skills/search`)
+        expect(res.status()).toEqual(400)
+    })
+})
+
+test.describe('GET /tags', () => {
+    test('should response tags with usage counts', async ({request,}) => {
+        const res = await request.get(`/api/v1/tags`)
+        expect(res.ok()).toBeTruthy()
+        const body = await res.json()
+        expect(body.data).toEqual(expect.arrayContaining([
+            {"tag": "automation", "count": expect.any(Number)}
+        ]))
+    })
+
+    test('should response skills carrying the tag', async ({request,}) => {
+        const res = await request.get(`/api/v1/tags/automation/skills?key_prefix=` + testDataKey.insertSetupKey)
+        expect(res.ok()).toBeTruthy()
+        expect(await res.json()).toEqual(
+            expect.objectContaining({
+                "status": "success",
+                "data": [expect.objectContaining({"key": testDataKey.insertSetupKey})],
+                "page": expect.objectContaining({"total": 1})
+            }))
+    })
 })
 
 test.describe('POST /skills', () => {
@@ -81,9 +150,53 @@ test.describe('POST /skills', () => {
     })
 })
 
+test.describe('POST /skills/import', () => {
+    test('should response batch of imported skills', async ({request}) => {
+        const res = await request.post(`/api/v1/skills/import?format=ndjson`, {
+            data: [
+                JSON.stringify({
+                    "key": testDataKey.importSkillKey,
+                    "name": "E2E Vitest",
+                    "description": "Vitest is a next generation testing framework powered by Vite.",
+                    "logo": "https://vitest.dev/logo.svg",
+                    "tags": ["node", "typescript", "testing"]
+                }),
+                JSON.stringify({"key": testDataKey.insertSetupKey, "name": "E2E Playwright"}),
+            ].join("\n")
+        })
+        expect(res.status()).toEqual(202)
+        const body = await res.json()
+        expect(body).toEqual(
+            expect.objectContaining({
+                "status": "success",
+                "message": "importing skills already in progress",
+                "data": expect.objectContaining({
+                    "total": 2,
+                    "created": 1,
+                    "errors": [expect.objectContaining({"row": 2, "key": testDataKey.insertSetupKey})]
+                })
+            })
+        )
+
+        const batch = await request.get(res.headers()['location'])
+        expect(batch.ok()).toBeTruthy()
+        expect((await batch.json()).data).toEqual(
+            expect.objectContaining({"id": body.data.batch_id, "total": 1})
+        )
+    })
+
+    test('should response conflict when skill already exists', async ({request}) => {
+        const res = await request.post(`/api/v1/skills/import?format=csv`, {
+            data: "key,name,description,logo,tags\n" + testDataKey.insertSetupKey + ",E2E Playwright,Playwright,https://playwright.dev/img/playwright-logo.svg,node;testing\n"
+        })
+        expect(res.status()).toEqual(409)
+    })
+})
+
 test.describe('PUT /skills/:key', () => {
     test('should response with status success', async ({request}) => {
         const res = await request.put(`/api/v1/skills/` + testDataKey.updateSkillKey, {
+            headers: { 'If-Match': '"1"' },
             data: {
                 "name": "E2E Jest",
                 "description": "Jest is a delightful JavaScript Testing Framework with a focus on simplicity.",
@@ -104,6 +217,7 @@ test.describe('PUT /skills/:key', () => {
 test.describe('PATCH /skills/:key/actions/name', () => {
     test('should response with status success', async ({request}) => {
         const res = await request.patch(`/api/v1/skills/` + testDataKey.updateNameKey + `/actions/name`, {
+            headers: { 'If-Match': '"1"' },
             data: {
                 "name": testDataKey.updateNameKey
             }
@@ -121,6 +235,7 @@ test.describe('PATCH /skills/:key/actions/name', () => {
 test.describe('PATCH /skills/:key/actions/description', () => {
     test('should response with status success', async ({request}) => {
         const res = await request.patch(`/api/v1/skills/` + testDataKey.updateDescriptionKey + `/actions/description`, {
+            headers: { 'If-Match': '"1"' },
             data: {
                 "description": testDataKey.updateDescriptionKey
             }
@@ -138,6 +253,7 @@ test.describe('PATCH /skills/:key/actions/description', () => {
 test.describe('PATCH /skills/:key/actions/logo', () => {
     test('should response with status success', async ({request}) => {
         const res = await request.patch(`/api/v1/skills/` + testDataKey.updateLogoKey + `/actions/logo`, {
+            headers: { 'If-Match': '"1"' },
             data: {
                 "logo": testDataKey.updateLogoKey
             }
@@ -152,9 +268,45 @@ test.describe('PATCH /skills/:key/actions/logo', () => {
     })
 })
 
+test.describe('PATCH /skills/:key/actions/add_tags', () => {
+    test('should response with status success without If-Match', async ({request}) => {
+        const res = await request.patch(`/api/v1/skills/` + testDataKey.updateTagsKey + `/actions/add_tags`, {
+            data: {
+                "tags": ["playwright", "e2e"]
+            }
+        })
+        expect(res.ok()).toBeTruthy()
+        expect(await res.json()).toEqual(
+            expect.objectContaining({
+                "status": "success",
+                "message": "adding skill tags already in progress"
+            })
+        )
+    })
+})
+
+test.describe('PATCH /skills/:key/actions/remove_tags', () => {
+    test('should response with status success without If-Match', async ({request}) => {
+        const res = await request.patch(`/api/v1/skills/` + testDataKey.updateTagsKey + `/actions/remove_tags`, {
+            data: {
+                "tags": ["automation"]
+            }
+        })
+        expect(res.ok()).toBeTruthy()
+        expect(await res.json()).toEqual(
+            expect.objectContaining({
+                "status": "success",
+                "message": "removing skill tags already in progress"
+            })
+        )
+    })
+})
+
 test.describe('DELETE /skills/:key', () => {
     test('should response with status success', async ({request}) => {
-        const res = await request.delete(`/api/v1/skills/` + testDataKey.deleteSkillKey)
+        const res = await request.delete(`/api/v1/skills/` + testDataKey.deleteSkillKey, {
+            headers: { 'If-Match': '"1"' }
+        })
         expect(res.ok()).toBeTruthy()
         expect(await res.json()).toEqual(
             expect.objectContaining({
@@ -15,6 +15,8 @@ export interface TestDataKey {
     updateLogoKey: string;
     updateTagsKey: string;
     deleteSkillKey: string;
+    importSkillKey: string;
+    markupSkillKey: string;
 }
 
 export const testDataKey: TestDataKey = {
@@ -27,6 +29,8 @@ export const testDataKey: TestDataKey = {
     updateLogoKey: createRandomString(10),
     updateTagsKey: createRandomString(10),
     deleteSkillKey: createRandomString(10),
+    importSkillKey: createRandomString(10),
+    markupSkillKey: createRandomString(10),
 }
 
 export async function pingDatabase() {
@@ -42,6 +46,10 @@ export async function pingDatabase() {
 
 export async function clearDatabase() {
     try {
+        await client.query(`UPDATE skill_tag SET count = skill_tag.count - t.count FROM (
+            SELECT tag, count(DISTINCT key) AS count FROM skill, unnest(tags) AS tag
+            WHERE key LIKE 'E2E_%' AND deleted_at IS NULL GROUP BY tag
+        ) t WHERE skill_tag.tag = t.tag`)
         await client.query("DELETE FROM skill where key LIKE 'E2E_%'")
     } catch (error) {
         console.error('Error connecting to database:', error)
@@ -93,6 +101,13 @@ export async function insertSampleData() {
                 logo: 'https://playwright.dev/img/playwright-logo.svg',
                 tags: ['node', 'javascript', 'typescript', 'automation', 'testing']
             },
+            {
+                key: testDataKey.markupSkillKey,
+                name: 'E2E Markup',
+                description: 'Sanitizer <img src=x onerror=alert(1)> keeps markup out of snippets.',
+                logo: 'https://example.com/markup.svg',
+                tags: ['security']
+            },
             {
                 key: testDataKey.deleteSkillKey,
                 name: 'E2E Playwright',
@@ -103,8 +118,13 @@ export async function insertSampleData() {
         ]
 
         for (const data of sampleData) {
-            const insertQuery = 'INSERT INTO skill (key, name, description, logo, tags) values ($1, $2, $3, $4, $5)'
+            // The consumer keeps search current on writes, rows seeded here have to fill it in themselves.
+            const insertQuery = `INSERT INTO skill (key, name, description, logo, tags, search) values ($1, $2, $3, $4, $5,
+                setweight(to_tsvector('english', $2), 'A') || setweight(to_tsvector('english', $3), 'B') || setweight(to_tsvector('english', array_to_string($5::text[], ' ')), 'C'))`
             await client.query(insertQuery, [data.key, data.name, data.description, data.logo, data.tags])
+            // Likewise for the tag counts the consumer keeps.
+            await client.query(`INSERT INTO skill_tag (tag, count) SELECT DISTINCT unnest($1::text[]), 1
+                ON CONFLICT (tag) DO UPDATE SET count = skill_tag.count + 1`, [data.tags])
         }
 
         console.log('Inserted sample data')
@@ -1,4 +1,4 @@
-CREATE TABLE skill (
+CREATE TABLE IF NOT EXISTS skill (
 	key TEXT PRIMARY KEY,
 	name TEXT NOT NULL DEFAULT '',
 	description TEXT NOT NULL DEFAULT '',
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS skill_operation (
+	id TEXT PRIMARY KEY,
+	action TEXT NOT NULL DEFAULT '',
+	skill_key TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT 'pending',
+	reason TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1 @@
+ALTER TABLE skill_operation ADD COLUMN IF NOT EXISTS request_id TEXT NOT NULL DEFAULT '';
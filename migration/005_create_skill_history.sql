@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS skill_history (
+	id BIGSERIAL PRIMARY KEY,
+	skill_key TEXT NOT NULL,
+	action TEXT NOT NULL,
+	before JSONB,
+	after JSONB,
+	actor TEXT NOT NULL DEFAULT '',
+	message_id TEXT NOT NULL DEFAULT '',
+	occurred_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS skill_history_key_idx ON skill_history (skill_key, id DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE skill ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS skill_deleted_at_idx ON skill (deleted_at) WHERE deleted_at IS NOT NULL;
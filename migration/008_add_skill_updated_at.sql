@@ -0,0 +1,4 @@
+ALTER TABLE skill ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX IF NOT EXISTS skill_name_idx ON skill (name, key);
+CREATE INDEX IF NOT EXISTS skill_updated_at_idx ON skill (updated_at, key);
@@ -0,0 +1,10 @@
+ALTER TABLE skill ADD COLUMN IF NOT EXISTS search TSVECTOR NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS skill_search_idx ON skill USING GIN (search);
+
+-- The consumer keeps search current on every write, skills written before it
+-- did are brought up to date here.
+UPDATE skill SET search = setweight(to_tsvector('english', name), 'A') ||
+	setweight(to_tsvector('english', description), 'B') ||
+	setweight(to_tsvector('english', array_to_string(tags, ' ')), 'C')
+WHERE search = '';
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS skill_tags_idx ON skill USING GIN (tags);
@@ -0,0 +1,3 @@
+ALTER TABLE skill_operation ADD COLUMN IF NOT EXISTS batch_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS skill_operation_batch_idx ON skill_operation (batch_id) WHERE batch_id <> '';
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS skill_tag (
+    tag TEXT PRIMARY KEY,
+    count BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS skill_tag_count_idx ON skill_tag (count DESC, tag) WHERE count > 0;
+
+-- The consumer keeps the counts current on every write, tags of skills
+-- written before it did are counted here once.
+INSERT INTO skill_tag (tag, count)
+SELECT tag, count(DISTINCT key) FROM skill, unnest(tags) AS tag
+WHERE deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM skill_tag)
+GROUP BY tag;
@@ -0,0 +1,8 @@
+-- Messages delivered again before the consumer skipped them could be applied
+-- twice, only the first entry keeps the message ID so the index can be built.
+UPDATE skill_history SET message_id = ''
+WHERE message_id <> '' AND id NOT IN (
+	SELECT min(id) FROM skill_history WHERE message_id <> '' GROUP BY message_id
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS skill_history_message_idx ON skill_history (message_id) WHERE message_id <> '';
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS skill_event_outbox (
+	id BIGSERIAL PRIMARY KEY,
+	key TEXT NOT NULL,
+	value BYTEA NOT NULL,
+	headers JSONB NOT NULL DEFAULT '{}',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	sent_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS skill_event_outbox_pending_idx ON skill_event_outbox (id) WHERE sent_at IS NULL;
+CREATE INDEX IF NOT EXISTS skill_event_outbox_sent_idx ON skill_event_outbox (sent_at) WHERE sent_at IS NOT NULL;